@@ -16,6 +16,15 @@ limitations under the License.
 
 package v1beta1
 
+const (
+	// DriftedCondition indicates whether the objects found on the cluster differ from the rendered objects, as
+	// determined by the last drift detection.
+	DriftedCondition string = "Drifted"
+
+	// AwaitingApprovalCondition indicates whether a diff is waiting for manual approval before it is deployed.
+	AwaitingApprovalCondition string = "AwaitingApproval"
+)
+
 const (
 	// DeployFailedReason represents the fact that the
 	// kluctl deploy command failed.
@@ -28,10 +37,58 @@ const (
 	// PrepareFailedReason represents failure in the kluctl preparation phase
 	PrepareFailedReason string = "PrepareFailed"
 
+	// SourceVerificationFailedReason represents the fact that the signature
+	// of the source could not be verified.
+	SourceVerificationFailedReason string = "SourceVerificationFailed"
+
+	// DependencyNotReadyReason represents the fact that one of the
+	// deployments listed in dependsOn is not ready yet.
+	DependencyNotReadyReason string = "DependencyNotReady"
+
 	// ReconciliationSucceededReason represents the fact that
 	// the reconciliation succeeded.
 	ReconciliationSucceededReason string = "ReconciliationSucceeded"
 
 	// WaitingForLegacyMigrationReason means that the controller is waiting for the legacy controller to set `readyForMigration=true`
 	WaitingForLegacyMigrationReason string = "WaitingForLegacyMigration"
+
+	// DriftDetectedReason represents the fact that drift detection found
+	// objects that differ from the rendered objects.
+	DriftDetectedReason string = "DriftDetected"
+
+	// DriftCorrectedReason represents the fact that detected drift was
+	// corrected by a deployment.
+	DriftCorrectedReason string = "DriftCorrected"
+
+	// NoDriftReason represents the fact that drift detection did not find
+	// any drift.
+	NoDriftReason string = "NoDrift"
+
+	// DriftDetectionFailedReason represents the fact that drift detection
+	// failed.
+	DriftDetectionFailedReason string = "DriftDetectionFailed"
+
+	// ApprovalPendingReason represents the fact that a non-empty diff
+	// is waiting for manual approval.
+	ApprovalPendingReason string = "ApprovalPending"
+
+	// ApprovedReason represents the fact that the pending diff was
+	// approved and deployed.
+	ApprovedReason string = "Approved"
+
+	// NoChangesReason represents the fact that the diff performed in
+	// approval mode was empty, so that no approval was required.
+	NoChangesReason string = "NoChanges"
+
+	// OutsideDeployWindowReason represents the fact that a deployment is
+	// pending because the current time is outside of the deploy windows.
+	OutsideDeployWindowReason string = "OutsideDeployWindow"
+
+	// ApprovalFailedReason represents the fact that the diff required
+	// for the approval could not be performed.
+	ApprovalFailedReason string = "ApprovalFailed"
+
+	// GenerateFailedReason represents the fact that the generators or the
+	// template of a KluctlDeploymentSet failed.
+	GenerateFailedReason string = "GenerateFailed"
 )
@@ -33,8 +33,26 @@ const (
 	KluctlDeployModeFull   = "full-deploy"
 	KluctlDeployPokeImages = "poke-images"
 
+	KluctlRollbackNone    = "none"
+	KluctlRollbackOnError = "on-error"
+
+	NotificationTypeWebhook = "webhook"
+	NotificationTypeSlack   = "slack"
+	NotificationTypeMSTeams = "msteams"
+
+	NotificationEventDeploySucceeded = "deploy-succeeded"
+	NotificationEventDeployFailed    = "deploy-failed"
+	NotificationEventValidateFailed  = "validate-failed"
+	NotificationEventDriftDetected   = "drift-detected"
+	NotificationEventPrune           = "prune"
+
+	CommitStatusProviderGitHub = "github"
+	CommitStatusProviderGitLab = "gitlab"
+	CommitStatusProviderGitea  = "gitea"
+
 	KluctlRequestReconcileAnnotation = "kluctl.io/request-reconcile"
 	KluctlRequestDeployAnnotation    = "kluctl.io/request-deploy"
+	KluctlApproveDeployAnnotation    = "kluctl.io/approve-deploy"
 )
 
 type KluctlDeploymentSpec struct {
@@ -67,6 +85,12 @@ type KluctlDeploymentSpec struct {
 	// +optional
 	DeployInterval *SafeDuration `json:"deployInterval,omitempty"`
 
+	// DeployWindows restricts deployments to the specified time windows. Outside of the allowed windows, the
+	// controller still renders, diffs and validates the deployment, but defers the deployment until the next allowed
+	// window opens.
+	// +optional
+	DeployWindows *DeployWindows `json:"deployWindows,omitempty"`
+
 	// ValidateInterval specifies the interval at which to validate the KluctlDeployment.
 	// Validation is performed the same way as with 'kluctl validate -t <target>'.
 	// Defaults to the same value as specified in Interval.
@@ -74,6 +98,11 @@ type KluctlDeploymentSpec struct {
 	// +optional
 	ValidateInterval *SafeDuration `json:"validateInterval,omitempty"`
 
+	// DriftDetection enables periodic drift detection. Drift detection compares the rendered objects with the
+	// objects found on the cluster without applying anything.
+	// +optional
+	DriftDetection *DriftDetection `json:"driftDetection,omitempty"`
+
 	// Timeout for all operations.
 	// Defaults to 'Interval' duration.
 	// +optional
@@ -86,6 +115,12 @@ type KluctlDeploymentSpec struct {
 	// +optional
 	Suspend bool `json:"suspend,omitempty"`
 
+	// DependsOn specifies a list of KluctlDeployments that must be ready before this
+	// deployment is reconciled. A dependency is considered ready when its Ready condition
+	// is true for its current generation.
+	// +optional
+	DependsOn []DependencyReference `json:"dependsOn,omitempty"`
+
 	// HelmCredentials is a list of Helm credentials used when non pre-pulled Helm Charts are used inside a
 	// Kluctl deployment.
 	// +optional
@@ -166,6 +201,13 @@ type KluctlDeploymentSpec struct {
 	// +optional
 	AbortOnError bool `json:"abortOnError,omitempty"`
 
+	// ApplyConcurrency specifies the maximum number of deployment items that are applied in parallel.
+	// Equivalent to using '--apply-concurrency' when calling kluctl.
+	// +kubebuilder:default:=8
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	ApplyConcurrency int `json:"applyConcurrency,omitempty"`
+
 	// IncludeTags instructs kluctl to only include deployments with given tags.
 	// Equivalent to using '--include-tag' when calling kluctl.
 	// +optional
@@ -194,11 +236,28 @@ type KluctlDeploymentSpec struct {
 	// +optional
 	DeployMode string `json:"deployMode,omitempty"`
 
+	// Approval enables the manual approval mode. In this mode, the controller performs a diff before deploying and
+	// only deploys after the resulting command result has been approved, either by setting the
+	// 'kluctl.io/approve-deploy' annotation to the id of the pending command result or via the webui.
+	// Pending approvals expire when a new commit arrives or the rendered objects change.
+	// +kubebuilder:default:=false
+	// +optional
+	Approval bool `json:"approval,omitempty"`
+
 	// Validate enables validation after deploying
 	// +kubebuilder:default:=true
 	// +optional
 	Validate bool `json:"validate"`
 
+	// Rollback specifies the rollback policy to use when a deployment fails.
+	// The options 'none' and 'on-error' are supported.
+	// With the 'on-error' option, a failed deployment is rolled back to the last successful deployment found in the
+	// command results.
+	// +kubebuilder:default:=none
+	// +kubebuilder:validation:Enum=none;on-error
+	// +optional
+	Rollback string `json:"rollback,omitempty"`
+
 	// Prune enables pruning after deploying.
 	// +kubebuilder:default:=false
 	// +optional
@@ -208,6 +267,16 @@ type KluctlDeploymentSpec struct {
 	// +kubebuilder:default:=false
 	// +optional
 	Delete bool `json:"delete,omitempty"`
+
+	// Notifications specifies a list of notifications to send when deployments, validations or drift detections
+	// finish.
+	// +optional
+	Notifications []Notification `json:"notifications,omitempty"`
+
+	// CommitStatus enables reporting of deploy and validate results as commit statuses to the Git forge hosting
+	// the source repository.
+	// +optional
+	CommitStatus *CommitStatus `json:"commitStatus,omitempty"`
 }
 
 // GetRetryInterval returns the retry interval
@@ -218,10 +287,105 @@ func (in KluctlDeploymentSpec) GetRetryInterval() time.Duration {
 	return in.Interval.Duration
 }
 
-type ProjectSource struct {
-	// Url specifies the Git url where the project source is located
+type DriftDetection struct {
+	// Interval specifies the interval at which drift detection is performed.
 	// +required
-	URL types.GitUrl `json:"url"`
+	// +kubebuilder:validation:Type=string
+	// +kubebuilder:validation:Pattern="^([0-9]+(\\.[0-9]+)?(ms|s|m|h))+$"
+	Interval metav1.Duration `json:"interval"`
+
+	// AutoCorrect instructs the controller to perform a deployment whenever drift is detected.
+	// +kubebuilder:default:=false
+	// +optional
+	AutoCorrect bool `json:"autoCorrect,omitempty"`
+}
+
+type DeployWindows struct {
+	// TimeZone specifies the IANA time zone used to evaluate the window schedules, e.g. 'Europe/Berlin'.
+	// Defaults to UTC.
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+
+	// Allow specifies the windows in which deployments are allowed. If empty, deployments are allowed at all
+	// times except inside the deny windows.
+	// +optional
+	Allow []DeployWindow `json:"allow,omitempty"`
+
+	// Deny specifies the windows in which deployments are not allowed. Deny windows take precedence over allow
+	// windows.
+	// +optional
+	Deny []DeployWindow `json:"deny,omitempty"`
+}
+
+type DeployWindow struct {
+	// Schedule is a cron expression in the standard 5 field format that specifies when the window opens,
+	// e.g. '0 18 * * 1-5' for every weekday at 18:00.
+	// +required
+	Schedule string `json:"schedule"`
+
+	// Duration specifies how long the window stays open after it has been opened.
+	// +required
+	// +kubebuilder:validation:Type=string
+	// +kubebuilder:validation:Pattern="^([0-9]+(\\.[0-9]+)?(ms|s|m|h))+$"
+	Duration metav1.Duration `json:"duration"`
+}
+
+type Notification struct {
+	// Type specifies the kind of webhook to send the notification to.
+	// The options 'webhook', 'slack' and 'msteams' are supported. 'webhook' sends the full notification payload as
+	// JSON to the given URL, while 'slack' and 'msteams' send the rendered message to an incoming webhook.
+	// +kubebuilder:validation:Enum=webhook;slack;msteams
+	// +required
+	Type string `json:"type"`
+
+	// SecretRef specifies the Secret containing the webhook URL. If no key is set, the key will default to 'url'.
+	// +required
+	SecretRef SecretKeyReference `json:"secretRef"`
+
+	// Events specifies the events that trigger the notification.
+	// The options 'deploy-succeeded', 'deploy-failed', 'validate-failed', 'drift-detected' and 'prune' are
+	// supported. If omitted, all events trigger the notification.
+	// +optional
+	Events []string `json:"events,omitempty"`
+
+	// Template specifies a Jinja2 template used to render the notification message. If omitted, a default template
+	// is used.
+	// +optional
+	Template string `json:"template,omitempty"`
+}
+
+type CommitStatus struct {
+	// Provider specifies the API of the Git forge that hosts the source repository.
+	// The options 'github', 'gitlab' and 'gitea' are supported.
+	// +kubebuilder:validation:Enum=github;gitlab;gitea
+	// +required
+	Provider string `json:"provider"`
+
+	// Address specifies the base url of the Git forge API. If omitted, it is derived from the source url. For
+	// github.com, 'https://api.github.com' is used, for other GitHub hosts 'https://<host>/api/v3' is used. For
+	// GitLab and Gitea, 'https://<host>' is used.
+	// +optional
+	Address string `json:"address,omitempty"`
+
+	// Repository specifies the repository to report the statuses to, e.g. 'owner/repo'. For GitLab, this is the
+	// full project path including all sub-groups. If omitted, it is derived from the path of the source url.
+	// +optional
+	Repository string `json:"repository,omitempty"`
+
+	// SecretRef specifies the Secret containing the API token. If no key is set, the key will default to 'token'.
+	// +required
+	SecretRef SecretKeyReference `json:"secretRef"`
+
+	// Context specifies the prefix used for the status context (or name on GitLab). '/deploy' and '/validate' are
+	// appended to it. Defaults to 'kluctl/<namespace>/<name>'.
+	// +optional
+	Context string `json:"context,omitempty"`
+}
+
+type ProjectSource struct {
+	// Url specifies the Git url where the project source is located. Either Url or Oci must be specified.
+	// +optional
+	URL types.GitUrl `json:"url,omitempty"`
 
 	// Ref specifies the branch, tag or commit that should be used. If omitted, the default branch of the repo is used.
 	// +optional
@@ -239,6 +403,69 @@ type ProjectSource struct {
 	// and 'known_hosts' fields.
 	// +optional
 	SecretRef *LocalObjectReference `json:"secretRef,omitempty"`
+
+	// Oci specifies an OCI artifact (as pushed by 'kluctl project push') to be used as project source instead of
+	// a Git repository. Path is then interpreted relative to the root of the artifact.
+	// +optional
+	Oci *ProjectSourceOci `json:"oci,omitempty"`
+
+	// Verify specifies that the source must be signed by one of the trusted keys before it is deployed.
+	// +optional
+	Verify *ProjectSourceVerify `json:"verify,omitempty"`
+}
+
+type ProjectSourceOci struct {
+	// URL specifies the OCI repository where the project artifact is located, e.g. oci://ghcr.io/org/project
+	// +required
+	// +kubebuilder:validation:Pattern="^oci://.+$"
+	URL string `json:"url"`
+
+	// Tag specifies the tag to pull. Defaults to 'latest' if neither Tag nor Digest is specified.
+	// +optional
+	Tag string `json:"tag,omitempty"`
+
+	// Digest specifies the digest to pull. Takes precedence over Tag.
+	// +optional
+	Digest string `json:"digest,omitempty"`
+
+	// SecretRef specifies the Secret containing authentication credentials for the registry.
+	// The Secret must either contain 'username' and 'password' fields or a '.dockerconfigjson' field.
+	// Optionally, it can contain 'caFile' and 'insecure' fields.
+	// +optional
+	SecretRef *LocalObjectReference `json:"secretRef,omitempty"`
+}
+
+const (
+	VerifyModeHEAD       = "HEAD"
+	VerifyModeTag        = "Tag"
+	VerifyModeTagAndHEAD = "TagAndHEAD"
+)
+
+// DependencyReference references another KluctlDeployment.
+type DependencyReference struct {
+	// Name of the referenced KluctlDeployment.
+	// +required
+	Name string `json:"name"`
+
+	// Namespace of the referenced KluctlDeployment. Defaults to the namespace of
+	// the referring KluctlDeployment.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+}
+
+type ProjectSourceVerify struct {
+	// Mode specifies what is verified for Git sources. 'HEAD' verifies the commit that Ref resolves to,
+	// 'Tag' verifies the annotated tag specified in Ref and 'TagAndHEAD' verifies both.
+	// Mode is ignored for OCI sources, where the signature created by 'kluctl project push --sign-key' is verified.
+	// +kubebuilder:validation:Enum=HEAD;Tag;TagAndHEAD
+	// +kubebuilder:default:=HEAD
+	// +optional
+	Mode string `json:"mode,omitempty"`
+
+	// SecretRef specifies the Secret containing the trusted public keys. Each field of the Secret can either
+	// contain armored GPG public keys or SSH public keys in authorized_keys format.
+	// +required
+	SecretRef LocalObjectReference `json:"secretRef"`
 }
 
 // Decryption defines how decryption is handled for Kubernetes manifests.
@@ -319,6 +546,10 @@ type KluctlDeploymentStatus struct {
 	// +optional
 	LastObjectsHash string `json:"lastObjectsHash,omitempty"`
 
+	// LastDeployedObjectsHash is the objects hash at the time of the last deployment
+	// +optional
+	LastDeployedObjectsHash string `json:"lastDeployedObjectsHash,omitempty"`
+
 	// +optional
 	LastDeployError string `json:"lastDeployError,omitempty"`
 	// +optional
@@ -332,6 +563,40 @@ type KluctlDeploymentStatus struct {
 	// LastValidateResult is the result of the last validate command
 	// +optional
 	LastValidateResult *runtime.RawExtension `json:"lastValidateResult,omitempty"`
+
+	// +optional
+	LastDriftDetectionError string `json:"lastDriftDetectionError,omitempty"`
+
+	// LastDriftDetectionResult is the result of the last drift detection
+	// +optional
+	LastDriftDetectionResult *runtime.RawExtension `json:"lastDriftDetectionResult,omitempty"`
+
+	// PendingApproval holds the diff that is waiting for approval when the approval mode is enabled
+	// +optional
+	PendingApproval *PendingApproval `json:"pendingApproval,omitempty"`
+
+	// DeployDeferred is true when a deployment is required but was deferred because it is outside of the
+	// deploy windows.
+	// +optional
+	DeployDeferred bool `json:"deployDeferred,omitempty"`
+}
+
+// PendingApproval describes a diff that must be approved before the controller deploys it.
+type PendingApproval struct {
+	// ResultId is the id of the command result holding the diff. Approve the deployment by setting the
+	// 'kluctl.io/approve-deploy' annotation to this value.
+	ResultId string `json:"resultId"`
+
+	// Commit is the commit the diff was performed for.
+	// +optional
+	Commit string `json:"commit,omitempty"`
+
+	// ObjectsHash is the hash of the rendered objects the diff was performed for.
+	// +optional
+	ObjectsHash string `json:"objectsHash,omitempty"`
+
+	// RequestedAt is the time the diff was performed.
+	RequestedAt metav1.Time `json:"requestedAt"`
 }
 
 func (s *KluctlDeploymentStatus) SetLastDeployResult(crs *result.CommandResultSummary, err error) error {
@@ -368,6 +633,23 @@ func (s *KluctlDeploymentStatus) SetLastValidateResult(crs *result.ValidateResul
 	return nil
 }
 
+func (s *KluctlDeploymentStatus) SetLastDriftDetectionResult(dr *result.DriftDetectionResult, err error) error {
+	s.LastDriftDetectionError = ""
+	if err != nil {
+		s.LastDriftDetectionError = err.Error()
+	}
+	if dr == nil {
+		s.LastDriftDetectionResult = nil
+	} else {
+		b, err := yaml.WriteJsonString(dr)
+		if err != nil {
+			return err
+		}
+		s.LastDriftDetectionResult = &runtime.RawExtension{Raw: []byte(b)}
+	}
+	return nil
+}
+
 func (s *KluctlDeploymentStatus) GetLastDeployResult() (*result.CommandResultSummary, error) {
 	if s.LastDeployResult == nil {
 		return nil, nil
@@ -392,11 +674,24 @@ func (s *KluctlDeploymentStatus) GetLastValidateResult() (*result.ValidateResult
 	return &ret, nil
 }
 
+func (s *KluctlDeploymentStatus) GetLastDriftDetectionResult() (*result.DriftDetectionResult, error) {
+	if s.LastDriftDetectionResult == nil {
+		return nil, nil
+	}
+	var ret result.DriftDetectionResult
+	err := yaml.ReadYamlBytes(s.LastDriftDetectionResult.Raw, &ret)
+	if err != nil {
+		return nil, err
+	}
+	return &ret, nil
+}
+
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
 //+kubebuilder:printcolumn:name="DryRun",type="boolean",JSONPath=".spec.dryRun",description=""
 //+kubebuilder:printcolumn:name="Deployed",type="date",JSONPath=".status.lastDeployResult.commandInfo.endTime",description=""
 //+kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status",description=""
+//+kubebuilder:printcolumn:name="Drifted",type="string",JSONPath=".status.conditions[?(@.type==\"Drifted\")].status",description=""
 //+kubebuilder:printcolumn:name="Status",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].message",description=""
 //+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description=""
 
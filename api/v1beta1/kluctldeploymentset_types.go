@@ -0,0 +1,186 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"github.com/kluctl/kluctl/v2/pkg/types"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	KluctlDeploymentSetKind = "KluctlDeploymentSet"
+
+	// KluctlDeploymentSetLabel is set on all KluctlDeployments generated by a KluctlDeploymentSet and contains the
+	// name of the KluctlDeploymentSet.
+	KluctlDeploymentSetLabel = "gitops.kluctl.io/deployment-set"
+)
+
+// KluctlDeploymentSetSpec defines the desired state of KluctlDeploymentSet
+type KluctlDeploymentSetSpec struct {
+	// Interval specifies the interval at which the generators are re-evaluated.
+	// +kubebuilder:validation:Type=string
+	// +kubebuilder:validation:Pattern="^([0-9]+(\\.[0-9]+)?(ms|s|m|h))+$"
+	// +required
+	Interval metav1.Duration `json:"interval"`
+
+	// Suspend tells the controller to suspend the generation of KluctlDeployments. Already generated
+	// KluctlDeployments are not affected.
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+
+	// Generators specifies the list of generators. Each generator produces a list of parameter sets and each
+	// parameter set results in one generated KluctlDeployment.
+	// +kubebuilder:validation:MinItems=1
+	// +required
+	Generators []KluctlDeploymentSetGenerator `json:"generators"`
+
+	// Template specifies the template used to generate the KluctlDeployments.
+	// +required
+	Template KluctlDeploymentTemplate `json:"template"`
+}
+
+// KluctlDeploymentSetGenerator specifies a single generator. Exactly one of the fields must be set.
+type KluctlDeploymentSetGenerator struct {
+	// List generates one parameter set per list element.
+	// +optional
+	List *ListGenerator `json:"list,omitempty"`
+
+	// Git generates parameter sets from the branches or directories of a Git repository.
+	// +optional
+	Git *GitGenerator `json:"git,omitempty"`
+}
+
+type ListGenerator struct {
+	// Elements specifies the list of parameter sets. The keys of each element are passed as variables to the
+	// template.
+	// +required
+	Elements []map[string]string `json:"elements"`
+}
+
+type GitGenerator struct {
+	// URL specifies the Git url of the repository.
+	// +required
+	URL types.GitUrl `json:"url"`
+
+	// SecretRef specifies the Secret containing authentication credentials for the git repository. See
+	// source.secretRef of KluctlDeployment for details.
+	// +optional
+	SecretRef *LocalObjectReference `json:"secretRef,omitempty"`
+
+	// Branches generates one parameter set per branch. Either Branches or Directories must be specified.
+	// +optional
+	Branches *GitBranchesGenerator `json:"branches,omitempty"`
+
+	// Directories generates one parameter set per directory. Either Branches or Directories must be specified.
+	// +optional
+	Directories *GitDirectoriesGenerator `json:"directories,omitempty"`
+}
+
+type GitBranchesGenerator struct {
+	// Pattern specifies a regular expression that branch names must match. If omitted, all branches are used.
+	// +optional
+	Pattern string `json:"pattern,omitempty"`
+}
+
+type GitDirectoriesGenerator struct {
+	// Ref specifies the branch or tag that should be used. If omitted, the default branch of the repo is used.
+	// +optional
+	Ref *GitRef `json:"ref,omitempty"`
+
+	// Path specifies a glob pattern matching the directories relative to the repository root, e.g. 'apps/*'.
+	// +required
+	Path string `json:"path"`
+}
+
+type KluctlDeploymentTemplate struct {
+	// Metadata specifies the name, labels and annotations of the generated KluctlDeployments. All values are
+	// rendered with the generated parameters.
+	// +required
+	Metadata KluctlDeploymentTemplateMetadata `json:"metadata"`
+
+	// Spec specifies the spec of the generated KluctlDeployments. All string values are rendered with the
+	// generated parameters.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +required
+	Spec runtime.RawExtension `json:"spec"`
+}
+
+type KluctlDeploymentTemplateMetadata struct {
+	// Name specifies the name of the generated KluctlDeployments. It must render to a unique name for each
+	// parameter set.
+	// +required
+	Name string `json:"name"`
+
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// KluctlDeploymentSetStatus defines the observed state of KluctlDeploymentSet
+type KluctlDeploymentSetStatus struct {
+	// ObservedGeneration is the last reconciled generation.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// Deployments contains the names of the currently generated KluctlDeployments.
+	// +optional
+	Deployments []string `json:"deployments,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status",description=""
+//+kubebuilder:printcolumn:name="Status",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].message",description=""
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description=""
+
+// KluctlDeploymentSet is the Schema for the kluctldeploymentsets API
+type KluctlDeploymentSet struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   KluctlDeploymentSetSpec   `json:"spec,omitempty"`
+	Status KluctlDeploymentSetStatus `json:"status,omitempty"`
+}
+
+// GetConditions returns the status conditions of the object.
+func (in *KluctlDeploymentSet) GetConditions() []metav1.Condition {
+	return in.Status.Conditions
+}
+
+// SetConditions sets the status conditions on the object.
+func (in *KluctlDeploymentSet) SetConditions(conditions []metav1.Condition) {
+	in.Status.Conditions = conditions
+}
+
+//+kubebuilder:object:root=true
+
+// KluctlDeploymentSetList contains a list of KluctlDeploymentSet
+type KluctlDeploymentSetList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []KluctlDeploymentSet `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&KluctlDeploymentSet{}, &KluctlDeploymentSetList{})
+}
@@ -28,6 +28,22 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CommitStatus) DeepCopyInto(out *CommitStatus) {
+	*out = *in
+	out.SecretRef = in.SecretRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CommitStatus.
+func (in *CommitStatus) DeepCopy() *CommitStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CommitStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Decryption) DeepCopyInto(out *Decryption) {
 	*out = *in
@@ -48,6 +64,144 @@ func (in *Decryption) DeepCopy() *Decryption {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DependencyReference) DeepCopyInto(out *DependencyReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DependencyReference.
+func (in *DependencyReference) DeepCopy() *DependencyReference {
+	if in == nil {
+		return nil
+	}
+	out := new(DependencyReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeployWindow) DeepCopyInto(out *DeployWindow) {
+	*out = *in
+	out.Duration = in.Duration
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeployWindow.
+func (in *DeployWindow) DeepCopy() *DeployWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(DeployWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeployWindows) DeepCopyInto(out *DeployWindows) {
+	*out = *in
+	if in.Allow != nil {
+		in, out := &in.Allow, &out.Allow
+		*out = make([]DeployWindow, len(*in))
+		copy(*out, *in)
+	}
+	if in.Deny != nil {
+		in, out := &in.Deny, &out.Deny
+		*out = make([]DeployWindow, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeployWindows.
+func (in *DeployWindows) DeepCopy() *DeployWindows {
+	if in == nil {
+		return nil
+	}
+	out := new(DeployWindows)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DriftDetection) DeepCopyInto(out *DriftDetection) {
+	*out = *in
+	out.Interval = in.Interval
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DriftDetection.
+func (in *DriftDetection) DeepCopy() *DriftDetection {
+	if in == nil {
+		return nil
+	}
+	out := new(DriftDetection)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitBranchesGenerator) DeepCopyInto(out *GitBranchesGenerator) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitBranchesGenerator.
+func (in *GitBranchesGenerator) DeepCopy() *GitBranchesGenerator {
+	if in == nil {
+		return nil
+	}
+	out := new(GitBranchesGenerator)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitDirectoriesGenerator) DeepCopyInto(out *GitDirectoriesGenerator) {
+	*out = *in
+	if in.Ref != nil {
+		in, out := &in.Ref, &out.Ref
+		*out = new(GitRef)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitDirectoriesGenerator.
+func (in *GitDirectoriesGenerator) DeepCopy() *GitDirectoriesGenerator {
+	if in == nil {
+		return nil
+	}
+	out := new(GitDirectoriesGenerator)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitGenerator) DeepCopyInto(out *GitGenerator) {
+	*out = *in
+	in.URL.DeepCopyInto(&out.URL)
+	if in.SecretRef != nil {
+		in, out := &in.SecretRef, &out.SecretRef
+		*out = new(LocalObjectReference)
+		**out = **in
+	}
+	if in.Branches != nil {
+		in, out := &in.Branches, &out.Branches
+		*out = new(GitBranchesGenerator)
+		**out = **in
+	}
+	if in.Directories != nil {
+		in, out := &in.Directories, &out.Directories
+		*out = new(GitDirectoriesGenerator)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitGenerator.
+func (in *GitGenerator) DeepCopy() *GitGenerator {
+	if in == nil {
+		return nil
+	}
+	out := new(GitGenerator)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GitRef) DeepCopyInto(out *GitRef) {
 	*out = *in
@@ -138,6 +292,141 @@ func (in *KluctlDeploymentList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KluctlDeploymentSet) DeepCopyInto(out *KluctlDeploymentSet) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KluctlDeploymentSet.
+func (in *KluctlDeploymentSet) DeepCopy() *KluctlDeploymentSet {
+	if in == nil {
+		return nil
+	}
+	out := new(KluctlDeploymentSet)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KluctlDeploymentSet) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KluctlDeploymentSetGenerator) DeepCopyInto(out *KluctlDeploymentSetGenerator) {
+	*out = *in
+	if in.List != nil {
+		in, out := &in.List, &out.List
+		*out = new(ListGenerator)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Git != nil {
+		in, out := &in.Git, &out.Git
+		*out = new(GitGenerator)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KluctlDeploymentSetGenerator.
+func (in *KluctlDeploymentSetGenerator) DeepCopy() *KluctlDeploymentSetGenerator {
+	if in == nil {
+		return nil
+	}
+	out := new(KluctlDeploymentSetGenerator)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KluctlDeploymentSetList) DeepCopyInto(out *KluctlDeploymentSetList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]KluctlDeploymentSet, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KluctlDeploymentSetList.
+func (in *KluctlDeploymentSetList) DeepCopy() *KluctlDeploymentSetList {
+	if in == nil {
+		return nil
+	}
+	out := new(KluctlDeploymentSetList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KluctlDeploymentSetList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KluctlDeploymentSetSpec) DeepCopyInto(out *KluctlDeploymentSetSpec) {
+	*out = *in
+	out.Interval = in.Interval
+	if in.Generators != nil {
+		in, out := &in.Generators, &out.Generators
+		*out = make([]KluctlDeploymentSetGenerator, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.Template.DeepCopyInto(&out.Template)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KluctlDeploymentSetSpec.
+func (in *KluctlDeploymentSetSpec) DeepCopy() *KluctlDeploymentSetSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KluctlDeploymentSetSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KluctlDeploymentSetStatus) DeepCopyInto(out *KluctlDeploymentSetStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Deployments != nil {
+		in, out := &in.Deployments, &out.Deployments
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KluctlDeploymentSetStatus.
+func (in *KluctlDeploymentSetStatus) DeepCopy() *KluctlDeploymentSetStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(KluctlDeploymentSetStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KluctlDeploymentSpec) DeepCopyInto(out *KluctlDeploymentSpec) {
 	*out = *in
@@ -158,16 +447,31 @@ func (in *KluctlDeploymentSpec) DeepCopyInto(out *KluctlDeploymentSpec) {
 		*out = new(SafeDuration)
 		**out = **in
 	}
+	if in.DeployWindows != nil {
+		in, out := &in.DeployWindows, &out.DeployWindows
+		*out = new(DeployWindows)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.ValidateInterval != nil {
 		in, out := &in.ValidateInterval, &out.ValidateInterval
 		*out = new(SafeDuration)
 		**out = **in
 	}
+	if in.DriftDetection != nil {
+		in, out := &in.DriftDetection, &out.DriftDetection
+		*out = new(DriftDetection)
+		**out = **in
+	}
 	if in.Timeout != nil {
 		in, out := &in.Timeout, &out.Timeout
 		*out = new(v1.Duration)
 		**out = **in
 	}
+	if in.DependsOn != nil {
+		in, out := &in.DependsOn, &out.DependsOn
+		*out = make([]DependencyReference, len(*in))
+		copy(*out, *in)
+	}
 	if in.HelmCredentials != nil {
 		in, out := &in.HelmCredentials, &out.HelmCredentials
 		*out = make([]HelmCredentials, len(*in))
@@ -221,6 +525,18 @@ func (in *KluctlDeploymentSpec) DeepCopyInto(out *KluctlDeploymentSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Notifications != nil {
+		in, out := &in.Notifications, &out.Notifications
+		*out = make([]Notification, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.CommitStatus != nil {
+		in, out := &in.CommitStatus, &out.CommitStatus
+		*out = new(CommitStatus)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KluctlDeploymentSpec.
@@ -263,6 +579,16 @@ func (in *KluctlDeploymentStatus) DeepCopyInto(out *KluctlDeploymentStatus) {
 		*out = new(runtime.RawExtension)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.LastDriftDetectionResult != nil {
+		in, out := &in.LastDriftDetectionResult, &out.LastDriftDetectionResult
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PendingApproval != nil {
+		in, out := &in.PendingApproval, &out.PendingApproval
+		*out = new(PendingApproval)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KluctlDeploymentStatus.
@@ -275,6 +601,52 @@ func (in *KluctlDeploymentStatus) DeepCopy() *KluctlDeploymentStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KluctlDeploymentTemplate) DeepCopyInto(out *KluctlDeploymentTemplate) {
+	*out = *in
+	in.Metadata.DeepCopyInto(&out.Metadata)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KluctlDeploymentTemplate.
+func (in *KluctlDeploymentTemplate) DeepCopy() *KluctlDeploymentTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(KluctlDeploymentTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KluctlDeploymentTemplateMetadata) DeepCopyInto(out *KluctlDeploymentTemplateMetadata) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KluctlDeploymentTemplateMetadata.
+func (in *KluctlDeploymentTemplateMetadata) DeepCopy() *KluctlDeploymentTemplateMetadata {
+	if in == nil {
+		return nil
+	}
+	out := new(KluctlDeploymentTemplateMetadata)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KubeConfig) DeepCopyInto(out *KubeConfig) {
 	*out = *in
@@ -291,6 +663,34 @@ func (in *KubeConfig) DeepCopy() *KubeConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ListGenerator) DeepCopyInto(out *ListGenerator) {
+	*out = *in
+	if in.Elements != nil {
+		in, out := &in.Elements, &out.Elements
+		*out = make([]map[string]string, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = make(map[string]string, len(*in))
+				for key, val := range *in {
+					(*out)[key] = val
+				}
+			}
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ListGenerator.
+func (in *ListGenerator) DeepCopy() *ListGenerator {
+	if in == nil {
+		return nil
+	}
+	out := new(ListGenerator)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LocalObjectReference) DeepCopyInto(out *LocalObjectReference) {
 	*out = *in
@@ -306,6 +706,43 @@ func (in *LocalObjectReference) DeepCopy() *LocalObjectReference {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Notification) DeepCopyInto(out *Notification) {
+	*out = *in
+	out.SecretRef = in.SecretRef
+	if in.Events != nil {
+		in, out := &in.Events, &out.Events
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Notification.
+func (in *Notification) DeepCopy() *Notification {
+	if in == nil {
+		return nil
+	}
+	out := new(Notification)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PendingApproval) DeepCopyInto(out *PendingApproval) {
+	*out = *in
+	in.RequestedAt.DeepCopyInto(&out.RequestedAt)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PendingApproval.
+func (in *PendingApproval) DeepCopy() *PendingApproval {
+	if in == nil {
+		return nil
+	}
+	out := new(PendingApproval)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ProjectSource) DeepCopyInto(out *ProjectSource) {
 	*out = *in
@@ -320,6 +757,16 @@ func (in *ProjectSource) DeepCopyInto(out *ProjectSource) {
 		*out = new(LocalObjectReference)
 		**out = **in
 	}
+	if in.Oci != nil {
+		in, out := &in.Oci, &out.Oci
+		*out = new(ProjectSourceOci)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Verify != nil {
+		in, out := &in.Verify, &out.Verify
+		*out = new(ProjectSourceVerify)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectSource.
@@ -332,6 +779,42 @@ func (in *ProjectSource) DeepCopy() *ProjectSource {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProjectSourceOci) DeepCopyInto(out *ProjectSourceOci) {
+	*out = *in
+	if in.SecretRef != nil {
+		in, out := &in.SecretRef, &out.SecretRef
+		*out = new(LocalObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectSourceOci.
+func (in *ProjectSourceOci) DeepCopy() *ProjectSourceOci {
+	if in == nil {
+		return nil
+	}
+	out := new(ProjectSourceOci)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProjectSourceVerify) DeepCopyInto(out *ProjectSourceVerify) {
+	*out = *in
+	out.SecretRef = in.SecretRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectSourceVerify.
+func (in *ProjectSourceVerify) DeepCopy() *ProjectSourceVerify {
+	if in == nil {
+		return nil
+	}
+	out := new(ProjectSourceVerify)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SafeDuration) DeepCopyInto(out *SafeDuration) {
 	*out = *in
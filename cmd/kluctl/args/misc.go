@@ -30,6 +30,15 @@ type HookFlags struct {
 	ReadinessTimeout time.Duration `group:"misc" help:"Maximum time to wait for object readiness. The timeout is meant per-object. Timeouts are in the duration format (1s, 1m, 1h, ...). If not specified, a default timeout of 5m is used." default:"5m"`
 }
 
+type ApplyConcurrencyFlags struct {
+	ApplyConcurrency int `group:"misc" help:"Maximum number of deployment items that are applied in parallel. Also limits the number of objects that are diffed in parallel." default:"8"`
+}
+
+type K8sClientFlags struct {
+	KubeQps   int `group:"misc" help:"Maximum queries per second for the Kubernetes client. If not specified, a default of 10 is used. A negative value disables client-side rate limiting."`
+	KubeBurst int `group:"misc" help:"Maximum burst of requests for the Kubernetes client. If not specified, a default of 20 is used."`
+}
+
 type IgnoreFlags struct {
 	IgnoreTags        bool `group:"misc" help:"Ignores changes in tags when diffing"`
 	IgnoreLabels      bool `group:"misc" help:"Ignores changes in labels when diffing"`
@@ -41,7 +50,7 @@ type AbortOnErrorFlags struct {
 }
 
 type OutputFormatFlags struct {
-	OutputFormat []string `group:"misc" short:"o" help:"Specify output format and target file, in the format 'format=path'. Format can either be 'text' or 'yaml'. Can be specified multiple times. The actual format for yaml is currently not documented and subject to change."`
+	OutputFormat []string `group:"misc" short:"o" help:"Specify output format and target file, in the format 'format=path'. Format can either be 'text', 'yaml', 'markdown', 'junit' or 'sarif'. Can be specified multiple times. The actual format for yaml is currently not documented and subject to change."`
 	NoObfuscate  bool     `group:"misc" help:"Disable obfuscation of sensitive/secret data"`
 	ShortOutput  bool     `group:"misc" help:"When using the 'text' output format (which is the default), only names of changes objects are shown instead of showing all changes."`
 }
@@ -50,6 +59,11 @@ type OutputFlags struct {
 	Output []string `group:"misc" short:"o" help:"Specify output target file. Can be specified multiple times"`
 }
 
+// ValidateOutputFlags specifies the output formats and target files of the validate command
+type ValidateOutputFlags struct {
+	Output []string `group:"misc" short:"o" help:"Specify output format and target file, in the format 'format=path'. Format can either be 'text', 'yaml', 'junit' or 'sarif'. Can be specified multiple times."`
+}
+
 type RenderOutputDirFlags struct {
 	RenderOutputDir string `group:"misc" help:"Specifies the target directory to render the project into. If omitted, a temporary directory is used."`
 }
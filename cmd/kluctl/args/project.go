@@ -24,7 +24,9 @@ func (a ProjectDir) GetProjectDir() (string, error) {
 type ProjectFlags struct {
 	ProjectDir
 
-	ProjectConfig ExistingFileType `group:"project" short:"c" help:"Location of the .kluctl.yaml config file. Defaults to $PROJECT/.kluctl.yaml" exts:"yml,yaml"`
+	ProjectConfig    ExistingFileType `group:"project" short:"c" help:"Location of the .kluctl.yaml config file. Defaults to $PROJECT/.kluctl.yaml" exts:"yml,yaml"`
+	ProjectUrl       string           `group:"project" help:"Pull the project from the given OCI artifact url (oci://registry/repository:tag or oci://registry/repository@digest) instead of using a local project directory. The artifact must have been pushed via 'kluctl project push'."`
+	ProjectVerifyKey []string         `group:"project" help:"Specify a file containing trusted GPG or SSH public keys. If specified, the artifact pulled via --project-url must be signed by one of the keys. Can be specified multiple times."`
 
 	Timeout                time.Duration `group:"project" help:"Specify timeout for all operations, including loading of the project, all external api calls and waiting for readiness." default:"10m"`
 	GitCacheUpdateInterval time.Duration `group:"project" help:"Specify the time to wait between git cache updates. Defaults to not wait at all and always updating caches."`
@@ -43,9 +45,47 @@ type TargetFlags struct {
 	Context            string `group:"project" help:"Overrides the context name specified in the target. If the selected target does not specify a context or the no-name target is used, --context will override the currently active context."`
 }
 
+type MultiTargetFlags struct {
+	Target             []string `group:"project" short:"t" help:"Target name to run command for. Target must exist in .kluctl.yaml. Can be specified multiple times to run the command for multiple targets in parallel."`
+	TargetNameOverride string   `group:"project" short:"T" help:"Overrides the target name. If -t is used at the same time, then the target will be looked up based on -t <name> and then renamed to the value of -T. If no target is specified via -t, then the no-name target is renamed to the value of -T. Can not be used with multiple targets."`
+	Context            string   `group:"project" help:"Overrides the context name specified in the target. If the selected target does not specify a context or the no-name target is used, --context will override the currently active context."`
+	AllTargets         bool     `group:"project" help:"Run the command for all targets found in .kluctl.yaml. Targets are processed in parallel."`
+	TargetSelector     []string `group:"project" help:"Run the command for all targets with matching args, in the form 'name=value'. Nested args can be matched with the 'my.nested.arg=value' syntax. Can be specified multiple times, in which case all selectors must match. Targets are processed in parallel."`
+}
+
+// IsMultiTarget returns true if the flags potentially select more than one target
+func (f *MultiTargetFlags) IsMultiTarget() bool {
+	return len(f.Target) > 1 || f.AllTargets || len(f.TargetSelector) != 0
+}
+
+// ForTarget returns TargetFlags for the given target name
+func (f *MultiTargetFlags) ForTarget(name string) TargetFlags {
+	return TargetFlags{
+		Target:             name,
+		TargetNameOverride: f.TargetNameOverride,
+		Context:            f.Context,
+	}
+}
+
 type CommandResultFlags struct {
 	WriteCommandResult      bool   `group:"results" help:"Enable writing of command results into the cluster. This is enabled by default." default:"true"`
 	ForceWriteCommandResult bool   `group:"results" help:"Force writing of command results, even if the command is run in dry-run mode."`
 	CommandResultNamespace  string `group:"results" help:"Override the namespace to be used when writing command results." default:"kluctl-results"`
-	KeepCommandResultsCount int    `group:"results" help:"Configure how many old command results to keep." default:"10"`
+	CommandResultStore      string `group:"results" help:"Specify where to store command results. By default, command results are stored as Secrets inside the target cluster. Use 'file://<path>' to store command results in a local directory instead. If the path is omitted, a directory inside the user cache directory is used. Use 's3://<bucket>/<prefix>' to store command results in an S3 compatible bucket."`
+
+	CommandResultRetentionFlags
+}
+
+type CommandResultRetentionFlags struct {
+	KeepCommandResultsCount           int           `group:"results" help:"Configure how many old command results to keep." default:"10"`
+	KeepCommandResultsCountPerCommand []string      `group:"results" help:"Configure how many old command results to keep for individual commands, in the form 'command=count', e.g. 'deploy=20'. Can be specified multiple times. Results of these commands are counted separately from the results of other commands."`
+	KeepCommandResultsMaxAge          time.Duration `group:"results" help:"Delete command results that are older than the given duration, e.g. '720h' for 30 days. Disabled by default."`
+	KeepLastSuccessfulDeployResult    bool          `group:"results" help:"Always keep the command result of the last successful deployment, even if it would be deleted due to the other retention settings."`
+	KeepFailedCommandResultsCount     int           `group:"results" help:"Always keep the given number of failed command results, even if they would be deleted due to the other retention settings."`
+}
+
+type CommandResultReadOnlyFlags struct {
+	Context            []string `group:"misc" help:"List of kubernetes contexts to use."`
+	AllContexts        bool     `group:"misc" help:"Use all Kubernetes contexts found in the kubeconfig."`
+	CommandResultStore []string `group:"misc" help:"Read command results from the given store, e.g. 'file://<path>' or 's3://<bucket>/<prefix>'. Can be specified multiple times. If specified, Kubernetes contexts are only used when --context or --all-contexts is passed as well."`
 }
@@ -40,7 +40,7 @@ func (cmd *controllerInstallCmd) Run(ctx context.Context) error {
 			},
 			Timeout: 10 * time.Minute,
 		},
-		TargetFlags: args.TargetFlags{
+		MultiTargetFlags: args.MultiTargetFlags{
 			Context: cmd.Context,
 		},
 		ArgsFlags: args.ArgsFlags{
@@ -6,8 +6,8 @@ import (
 	kluctlv1 "github.com/kluctl/kluctl/v2/api/v1beta1"
 	"github.com/kluctl/kluctl/v2/cmd/kluctl/args"
 	"github.com/kluctl/kluctl/v2/pkg/controllers"
+	"github.com/kluctl/kluctl/v2/pkg/controllers/receiver"
 	ssh_pool "github.com/kluctl/kluctl/v2/pkg/git/ssh-pool"
-	"github.com/kluctl/kluctl/v2/pkg/results"
 	"github.com/kluctl/kluctl/v2/pkg/utils/flux_utils/metrics"
 	log "github.com/sirupsen/logrus"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -25,6 +25,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	crtlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+	"time"
 )
 
 var (
@@ -46,6 +47,13 @@ type controllerRunCmd struct {
 	DefaultServiceAccount string `group:"misc" help:"Default service account used for impersonation."`
 	DryRun                bool   `group:"misc" help:"Run all deployments in dryRun=true mode."`
 
+	WebuiUrl string `group:"misc" help:"Base URL of the Kluctl webui. If specified, notifications will contain links to the command results."`
+
+	DependencyRequeueInterval time.Duration `group:"misc" help:"The interval at which failing dependencies are reevaluated." default:"30s"`
+
+	WebhookReceiverBindAddress string `group:"misc" help:"The address the webhook receiver binds to, e.g. ':9292'. The webhook receiver accepts push events from GitHub, GitLab, Gitea and generic webhooks and requests reconciliation of the affected KluctlDeployments. The receiver is disabled if empty."`
+	WebhookReceiverSecret      string `group:"misc" help:"The secret used to verify webhook payloads. Required when the webhook receiver is enabled. Consider passing it via the KLUCTL_WEBHOOK_RECEIVER_SECRET environment variable."`
+
 	args.CommandResultFlags
 }
 
@@ -66,6 +74,10 @@ func (cmd *controllerRunCmd) initScheme() {
 }
 
 func (cmd *controllerRunCmd) Run(ctx context.Context) error {
+	if cmd.WebhookReceiverBindAddress != "" && cmd.WebhookReceiverSecret == "" {
+		return fmt.Errorf("--webhook-receiver-secret is required when the webhook receiver is enabled")
+	}
+
 	cmd.initScheme()
 
 	metricsRecorder := metrics.NewRecorder()
@@ -122,6 +134,7 @@ func (cmd *controllerRunCmd) Run(ctx context.Context) error {
 		ControllerName:        controllerName,
 		DefaultServiceAccount: cmd.DefaultServiceAccount,
 		DryRun:                cmd.DryRun,
+		WebuiUrl:              cmd.WebuiUrl,
 		RestConfig:            restConfig,
 		Client:                mgr.GetClient(),
 		Scheme:                mgr.GetScheme(),
@@ -131,11 +144,9 @@ func (cmd *controllerRunCmd) Run(ctx context.Context) error {
 	}
 
 	if cmd.WriteCommandResult {
-		c, err := client.NewWithWatch(restConfig, client.Options{})
-		if err != nil {
-			return err
-		}
-		resultStore, err := results.NewResultStoreSecrets(ctx, c, cmd.CommandResultNamespace, cmd.KeepCommandResultsCount)
+		resultStore, err := buildResultStore(ctx, func() (client.WithWatch, error) {
+			return client.NewWithWatch(restConfig, client.Options{})
+		}, &cmd.CommandResultFlags)
 		if err != nil {
 			return err
 		}
@@ -143,12 +154,38 @@ func (cmd *controllerRunCmd) Run(ctx context.Context) error {
 	}
 
 	if err = r.SetupWithManager(ctx, mgr, controllers.KluctlDeploymentReconcilerOpts{
-		HTTPRetry: 9,
+		HTTPRetry:                 9,
+		DependencyRequeueInterval: cmd.DependencyRequeueInterval,
 	}); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", kluctlv1.KluctlDeploymentKind)
 		os.Exit(1)
 	}
 
+	setReconciler := controllers.KluctlDeploymentSetReconciler{
+		ControllerName: controllerName,
+		Client:         mgr.GetClient(),
+		Scheme:         mgr.GetScheme(),
+		EventRecorder:  eventRecorder,
+		SshPool:        sshPool,
+	}
+	if err = setReconciler.SetupWithManager(ctx, mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", kluctlv1.KluctlDeploymentSetKind)
+		os.Exit(1)
+	}
+
+	if cmd.WebhookReceiverBindAddress != "" {
+		err = mgr.Add(&receiver.Receiver{
+			Client:       mgr.GetClient(),
+			BindAddress:  cmd.WebhookReceiverBindAddress,
+			Secret:       cmd.WebhookReceiverSecret,
+			FieldManager: controllerName,
+		})
+		if err != nil {
+			setupLog.Error(err, "unable to add webhook receiver")
+			os.Exit(1)
+		}
+	}
+
 	//+kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -11,7 +11,7 @@ import (
 
 type deployCmd struct {
 	args.ProjectFlags
-	args.TargetFlags
+	args.MultiTargetFlags
 	args.ArgsFlags
 	args.ImageFlags
 	args.InclusionFlags
@@ -24,11 +24,15 @@ type deployCmd struct {
 	args.HookFlags
 	args.OutputFormatFlags
 	args.RenderOutputDirFlags
+	args.ApplyConcurrencyFlags
+	args.K8sClientFlags
 	args.CommandResultFlags
 
 	NoWait bool `group:"misc" help:"Don't wait for objects readiness'"`
 	Prune  bool `group:"misc" help:"Prune orphaned objects directly after deploying. See the help for the 'prune' sub-command for details.'"`
 
+	RollbackOnError bool `group:"misc" help:"Roll back to the last successful deployment in case the deployment fails. This requires command results to be written to the cluster."`
+
 	internal bool
 }
 
@@ -40,19 +44,23 @@ It will also output a list of prunable objects (without actually deleting them).
 }
 
 func (cmd *deployCmd) Run(ctx context.Context) error {
+	if cmd.IsMultiTarget() && !cmd.Yes && !cmd.DryRun {
+		return fmt.Errorf("--yes or --dry-run must be specified when running for multiple targets")
+	}
+
 	ptArgs := projectTargetCommandArgs{
 		projectFlags:         cmd.ProjectFlags,
-		targetFlags:          cmd.TargetFlags,
 		argsFlags:            cmd.ArgsFlags,
 		imageFlags:           cmd.ImageFlags,
 		inclusionFlags:       cmd.InclusionFlags,
 		helmCredentials:      cmd.HelmCredentials,
 		dryRunArgs:           &cmd.DryRunFlags,
 		renderOutputDirFlags: cmd.RenderOutputDirFlags,
+		k8sClientFlags:       &cmd.K8sClientFlags,
 		commandResultFlags:   &cmd.CommandResultFlags,
 		internalDeploy:       cmd.internal,
 	}
-	return withProjectCommandContext(ctx, ptArgs, func(cmdCtx *commandCtx) error {
+	return withMultiTargetCommandContext(ctx, ptArgs, cmd.MultiTargetFlags, func(cmdCtx *commandCtx) error {
 		return cmd.runCmdDeploy(cmdCtx)
 	})
 }
@@ -67,9 +75,12 @@ func (cmd *deployCmd) runCmdDeploy(cmdCtx *commandCtx) error {
 	cmd2.ForceReplaceOnError = cmd.ForceReplaceOnError
 	cmd2.AbortOnError = cmd.AbortOnError
 	cmd2.ReadinessTimeout = cmd.ReadinessTimeout
+	cmd2.ApplyConcurrency = cmd.ApplyConcurrency
 	cmd2.NoWait = cmd.NoWait
 	cmd2.Prune = cmd.Prune
 	cmd2.WaitPrune = !cmd.NoWait
+	cmd2.RollbackOnError = cmd.RollbackOnError
+	cmd2.ResultStore = cmdCtx.resultStore
 
 	cb := func(diffResult *result.CommandResult) error {
 		return cmd.diffResultCb(cmdCtx, diffResult)
@@ -9,7 +9,7 @@ import (
 
 type diffCmd struct {
 	args.ProjectFlags
-	args.TargetFlags
+	args.MultiTargetFlags
 	args.ArgsFlags
 	args.InclusionFlags
 	args.ImageFlags
@@ -19,6 +19,8 @@ type diffCmd struct {
 	args.IgnoreFlags
 	args.OutputFormatFlags
 	args.RenderOutputDirFlags
+	args.ApplyConcurrencyFlags
+	args.K8sClientFlags
 }
 
 func (cmd *diffCmd) Help() string {
@@ -31,14 +33,14 @@ After the diff is performed, the command will also search for prunable objects a
 func (cmd *diffCmd) Run(ctx context.Context) error {
 	ptArgs := projectTargetCommandArgs{
 		projectFlags:         cmd.ProjectFlags,
-		targetFlags:          cmd.TargetFlags,
 		argsFlags:            cmd.ArgsFlags,
 		imageFlags:           cmd.ImageFlags,
 		inclusionFlags:       cmd.InclusionFlags,
 		helmCredentials:      cmd.HelmCredentials,
 		renderOutputDirFlags: cmd.RenderOutputDirFlags,
+		k8sClientFlags:       &cmd.K8sClientFlags,
 	}
-	return withProjectCommandContext(ctx, ptArgs, func(cmdCtx *commandCtx) error {
+	return withMultiTargetCommandContext(ctx, ptArgs, cmd.MultiTargetFlags, func(cmdCtx *commandCtx) error {
 		cmd2 := commands.NewDiffCommand(cmdCtx.targetCtx)
 		cmd2.ForceApply = cmd.ForceApply
 		cmd2.ReplaceOnError = cmd.ReplaceOnError
@@ -46,6 +48,7 @@ func (cmd *diffCmd) Run(ctx context.Context) error {
 		cmd2.IgnoreTags = cmd.IgnoreTags
 		cmd2.IgnoreLabels = cmd.IgnoreLabels
 		cmd2.IgnoreAnnotations = cmd.IgnoreAnnotations
+		cmd2.ApplyConcurrency = cmd.ApplyConcurrency
 		result, err := cmd2.Run()
 		if err != nil {
 			return err
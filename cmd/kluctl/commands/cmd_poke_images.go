@@ -18,7 +18,9 @@ type pokeImagesCmd struct {
 	args.YesFlags
 	args.DryRunFlags
 	args.OutputFormatFlags
+	args.ApplyConcurrencyFlags
 	args.RenderOutputDirFlags
+	args.K8sClientFlags
 	args.CommandResultFlags
 }
 
@@ -38,6 +40,7 @@ func (cmd *pokeImagesCmd) Run(ctx context.Context) error {
 		helmCredentials:      cmd.HelmCredentials,
 		dryRunArgs:           &cmd.DryRunFlags,
 		renderOutputDirFlags: cmd.RenderOutputDirFlags,
+		k8sClientFlags:       &cmd.K8sClientFlags,
 		commandResultFlags:   &cmd.CommandResultFlags,
 	}
 	return withProjectCommandContext(ctx, ptArgs, func(cmdCtx *commandCtx) error {
@@ -48,6 +51,7 @@ func (cmd *pokeImagesCmd) Run(ctx context.Context) error {
 		}
 
 		cmd2 := commands.NewPokeImagesCommand(cmdCtx.targetCtx)
+		cmd2.ApplyConcurrency = cmd.ApplyConcurrency
 
 		result, err := cmd2.Run()
 		if err != nil {
@@ -0,0 +1,5 @@
+package commands
+
+type projectCmd struct {
+	Push projectPushCmd `cmd:"" help:"Package the project as OCI artifact and push it to a registry"`
+}
@@ -0,0 +1,87 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"github.com/kluctl/kluctl/v2/cmd/kluctl/args"
+	"github.com/kluctl/kluctl/v2/pkg/deployment/commands"
+	"github.com/kluctl/kluctl/v2/pkg/git"
+	"github.com/kluctl/kluctl/v2/pkg/oci"
+	"github.com/kluctl/kluctl/v2/pkg/registries"
+	"github.com/kluctl/kluctl/v2/pkg/signature"
+	"github.com/kluctl/kluctl/v2/pkg/status"
+	"os"
+)
+
+type projectPushCmd struct {
+	args.ProjectDir
+
+	Url     string                `group:"misc" help:"The OCI url to push the project to, in the form oci://registry/repository:tag."`
+	SignKey args.ExistingFileType `group:"misc" help:"Sign the project with the given unencrypted GPG or SSH private key. The signature can be verified via 'spec.source.verify' of a KluctlDeployment or via '--project-verify-key'."`
+}
+
+func (cmd *projectPushCmd) Help() string {
+	return `This command packages the project directory (excluding .git directories) as OCI artifact and pushes it
+to the given registry. The pushed artifact can then be used via '--project-url' or via 'spec.source.oci' of a
+KluctlDeployment, which is useful for clusters that can only reach a registry but not the Git server.
+
+Registry credentials are read from the same KLUCTL_REGISTRY_XXX environment variables that are used for
+image registries.
+`
+}
+
+func (cmd *projectPushCmd) Run(ctx context.Context) error {
+	if cmd.Url == "" {
+		return fmt.Errorf("--url must be specified")
+	}
+	if !oci.IsOciUrl(cmd.Url) {
+		return fmt.Errorf("--url must start with oci://")
+	}
+
+	projectDir, err := cmd.ProjectDir.GetProjectDir()
+	if err != nil {
+		return err
+	}
+
+	annotations := map[string]string{}
+	repoRoot, err := git.DetectGitRepositoryRoot(projectDir)
+	if err == nil {
+		gitInfo, _, err := commands.BuildGitInfo(repoRoot, projectDir)
+		if err == nil && gitInfo != nil {
+			if gitInfo.Url != nil {
+				annotations["org.opencontainers.image.source"] = gitInfo.Url.String()
+			}
+			annotations["org.opencontainers.image.revision"] = gitInfo.Commit
+		}
+	}
+
+	var signer *signature.Signer
+	if cmd.SignKey != "" {
+		b, err := os.ReadFile(cmd.SignKey.String())
+		if err != nil {
+			return err
+		}
+		signer, err = signature.ParsePrivateKey(b)
+		if err != nil {
+			return err
+		}
+	}
+
+	rh := registries.NewRegistryHelper(ctx)
+	err = rh.ParseAuthEntriesFromEnv()
+	if err != nil {
+		return fmt.Errorf("failed to parse registry auth from environment: %w", err)
+	}
+
+	s := status.Start(ctx, "Pushing project to %s", cmd.Url)
+	defer s.Failed()
+
+	digest, err := oci.PushProject(rh, cmd.Url, projectDir, annotations, signer)
+	if err != nil {
+		return err
+	}
+	s.Success()
+
+	_, err = getStdout(ctx).WriteString(digest + "\n")
+	return err
+}
@@ -10,7 +10,7 @@ import (
 
 type pruneCmd struct {
 	args.ProjectFlags
-	args.TargetFlags
+	args.MultiTargetFlags
 	args.ArgsFlags
 	args.ImageFlags
 	args.InclusionFlags
@@ -19,6 +19,7 @@ type pruneCmd struct {
 	args.DryRunFlags
 	args.OutputFormatFlags
 	args.RenderOutputDirFlags
+	args.K8sClientFlags
 	args.CommandResultFlags
 }
 
@@ -31,18 +32,22 @@ func (cmd *pruneCmd) Help() string {
 }
 
 func (cmd *pruneCmd) Run(ctx context.Context) error {
+	if cmd.IsMultiTarget() && !cmd.Yes && !cmd.DryRun {
+		return fmt.Errorf("--yes or --dry-run must be specified when running for multiple targets")
+	}
+
 	ptArgs := projectTargetCommandArgs{
 		projectFlags:         cmd.ProjectFlags,
-		targetFlags:          cmd.TargetFlags,
 		argsFlags:            cmd.ArgsFlags,
 		imageFlags:           cmd.ImageFlags,
 		inclusionFlags:       cmd.InclusionFlags,
 		helmCredentials:      cmd.HelmCredentials,
 		dryRunArgs:           &cmd.DryRunFlags,
 		renderOutputDirFlags: cmd.RenderOutputDirFlags,
+		k8sClientFlags:       &cmd.K8sClientFlags,
 		commandResultFlags:   &cmd.CommandResultFlags,
 	}
-	return withProjectCommandContext(ctx, ptArgs, func(cmdCtx *commandCtx) error {
+	return withMultiTargetCommandContext(ctx, ptArgs, cmd.MultiTargetFlags, func(cmdCtx *commandCtx) error {
 		return cmd.runCmdPrune(cmdCtx)
 	})
 }
@@ -0,0 +1,53 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"github.com/kluctl/kluctl/v2/cmd/kluctl/args"
+	"github.com/kluctl/kluctl/v2/pkg/results"
+	"github.com/kluctl/kluctl/v2/pkg/status"
+	"github.com/kluctl/kluctl/v2/pkg/types/result"
+	"time"
+)
+
+type resultsCmd struct {
+	List resultsListCmd `cmd:"" help:"List command results"`
+	Show resultsShowCmd `cmd:"" help:"Show a single command result"`
+	Diff resultsDiffCmd `cmd:"" help:"Compare the objects of two command results"`
+}
+
+func withResultsCollector(ctx context.Context, flags *args.CommandResultReadOnlyFlags, cb func(rc *results.ResultsCollector) error) error {
+	stores, _, err := createResultStores(ctx, flags, false)
+	if err != nil {
+		return err
+	}
+
+	collector := results.NewResultsCollector(ctx, stores)
+	collector.Start()
+
+	st := status.Start(ctx, "Collecting results")
+	defer st.Failed()
+	err = collector.WaitForResults(time.Second, time.Second*30)
+	if err != nil {
+		return err
+	}
+	st.Success()
+
+	return cb(collector)
+}
+
+func getCommandResult(rc *results.ResultsCollector, id string) (*result.CommandResult, error) {
+	if id == "" {
+		return nil, fmt.Errorf("missing command result id")
+	}
+	cr, err := rc.GetCommandResult(results.GetCommandResultOptions{
+		Id: id,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if cr == nil {
+		return nil, fmt.Errorf("command result %s not found", id)
+	}
+	return cr, nil
+}
@@ -0,0 +1,56 @@
+package commands
+
+import (
+	"context"
+	"github.com/kluctl/kluctl/v2/cmd/kluctl/args"
+	"github.com/kluctl/kluctl/v2/pkg/diff"
+	"github.com/kluctl/kluctl/v2/pkg/results"
+	"github.com/kluctl/kluctl/v2/pkg/status"
+)
+
+type resultsDiffCmd struct {
+	args.CommandResultReadOnlyFlags
+	args.OutputFormatFlags
+
+	OldResultId string `group:"misc" help:"Id of the older command result to compare."`
+	NewResultId string `group:"misc" help:"Id of the newer command result to compare."`
+}
+
+func (cmd *resultsDiffCmd) Help() string {
+	return `This command compares the objects of two command results, e.g. two historic
+deployments of the same target. Objects that only exist in the newer result are
+shown as new, objects that only exist in the older result are shown as deleted
+and all other objects are compared field by field.
+`
+}
+
+func (cmd *resultsDiffCmd) Run(ctx context.Context) error {
+	return withResultsCollector(ctx, &cmd.CommandResultReadOnlyFlags, func(rc *results.ResultsCollector) error {
+		oldCr, err := getCommandResult(rc, cmd.OldResultId)
+		if err != nil {
+			return err
+		}
+		newCr, err := getCommandResult(rc, cmd.NewResultId)
+		if err != nil {
+			return err
+		}
+
+		cr, err := results.DiffCommandResults(oldCr, newCr)
+		if err != nil {
+			return err
+		}
+
+		if !cmd.NoObfuscate {
+			var obfuscator diff.Obfuscator
+			err = obfuscator.ObfuscateResult(cr)
+			if err != nil {
+				return err
+			}
+		}
+
+		status.Flush(ctx)
+		return outputHelper(ctx, cmd.OutputFormat, func(format string) (string, error) {
+			return formatCommandResult(cr, nil, format, cmd.ShortOutput)
+		})
+	})
+}
@@ -0,0 +1,206 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"github.com/kluctl/kluctl/v2/cmd/kluctl/args"
+	"github.com/kluctl/kluctl/v2/pkg/results"
+	"github.com/kluctl/kluctl/v2/pkg/types"
+	"github.com/kluctl/kluctl/v2/pkg/types/result"
+	"github.com/kluctl/kluctl/v2/pkg/utils"
+	"github.com/kluctl/kluctl/v2/pkg/yaml"
+	"path"
+	"strconv"
+	"time"
+)
+
+type resultsListCmd struct {
+	args.CommandResultReadOnlyFlags
+
+	ProjectRepoKey string `group:"misc" help:"Only list results of projects with the given git repository key, e.g. 'github.com/my-org/my-repo'."`
+	ProjectSubdir  string `group:"misc" help:"Only list results of projects with the given sub-directory. Only used together with --project-repo-key."`
+	Target         string `group:"misc" help:"Only list results of the given target."`
+	Command        string `group:"misc" help:"Only list results of the given command, e.g. 'deploy' or 'diff'."`
+	Initiator      string `group:"misc" help:"Only list results with the given initiator. Can be 'CommandLine' or 'KluctlDeployment'."`
+	Since          string `group:"misc" help:"Only list results started at or after the given time. Can be a RFC3339 timestamp or a duration relative to now, e.g. '24h'."`
+	Until          string `group:"misc" help:"Only list results started before the given time. Can be a RFC3339 timestamp or a duration relative to now, e.g. '24h'."`
+	Status         string `group:"misc" help:"Only list results with the given status. Can be 'success' (no errors), 'warning' (warnings but no errors) or 'error'."`
+	Limit          int    `group:"misc" help:"Limit the number of listed results. Zero means no limit."`
+
+	Output []string `group:"misc" short:"o" help:"Specify output format and target file, in the format 'format=path'. Format can either be 'text' or 'yaml'. Can be specified multiple times."`
+}
+
+func (cmd *resultsListCmd) Help() string {
+	return `This command lists the command results found in the cluster and/or the given
+command result stores, newest first. The list can be filtered by project, target,
+command, initiator, time range and status.
+`
+}
+
+type resultsFilter struct {
+	project   *result.ProjectKey
+	target    string
+	command   string
+	initiator string
+	since     *time.Time
+	until     *time.Time
+	status    string
+}
+
+func parseTimeFilter(s string, now time.Time) (*time.Time, error) {
+	if s == "" {
+		return nil, nil
+	}
+	if d, err := time.ParseDuration(s); err == nil {
+		t := now.Add(-d)
+		return &t, nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid time %s, must be a RFC3339 timestamp or a duration", s)
+	}
+	return &t, nil
+}
+
+func (cmd *resultsListCmd) buildFilter() (*resultsFilter, error) {
+	var err error
+	now := time.Now()
+
+	f := &resultsFilter{
+		target:    cmd.Target,
+		command:   cmd.Command,
+		initiator: cmd.Initiator,
+		status:    cmd.Status,
+	}
+	if cmd.ProjectRepoKey != "" {
+		repoKey, err := types.ParseGitRepoKey(cmd.ProjectRepoKey)
+		if err != nil {
+			return nil, err
+		}
+		f.project = &result.ProjectKey{
+			GitRepoKey: repoKey,
+			SubDir:     cmd.ProjectSubdir,
+		}
+	} else if cmd.ProjectSubdir != "" {
+		return nil, fmt.Errorf("--project-subdir can only be used together with --project-repo-key")
+	}
+	switch cmd.Initiator {
+	case "", string(result.CommandInititiator_CommandLine), string(result.CommandInititiator_KluctlDeployment):
+	default:
+		return nil, fmt.Errorf("invalid initiator %s", cmd.Initiator)
+	}
+	switch cmd.Status {
+	case "", "success", "warning", "error":
+	default:
+		return nil, fmt.Errorf("invalid status %s", cmd.Status)
+	}
+	f.since, err = parseTimeFilter(cmd.Since, now)
+	if err != nil {
+		return nil, err
+	}
+	f.until, err = parseTimeFilter(cmd.Until, now)
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (f *resultsFilter) match(s *result.CommandResultSummary) bool {
+	if f.project != nil && s.ProjectKey != *f.project {
+		return false
+	}
+	if f.target != "" && s.Target.Name != f.target && s.TargetKey.TargetName != f.target {
+		return false
+	}
+	if f.command != "" && s.Command.Command != f.command {
+		return false
+	}
+	if f.initiator != "" && string(s.Command.Initiator) != f.initiator {
+		return false
+	}
+	if f.since != nil && s.Command.StartTime.Time.Before(*f.since) {
+		return false
+	}
+	if f.until != nil && !s.Command.StartTime.Time.Before(*f.until) {
+		return false
+	}
+	switch f.status {
+	case "success":
+		if len(s.Errors) != 0 {
+			return false
+		}
+	case "warning":
+		if len(s.Errors) != 0 || len(s.Warnings) == 0 {
+			return false
+		}
+	case "error":
+		if len(s.Errors) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (cmd *resultsListCmd) Run(ctx context.Context) error {
+	filter, err := cmd.buildFilter()
+	if err != nil {
+		return err
+	}
+
+	return withResultsCollector(ctx, &cmd.CommandResultReadOnlyFlags, func(rc *results.ResultsCollector) error {
+		summaries, err := rc.ListCommandResultSummaries(results.ListCommandResultSummariesOptions{})
+		if err != nil {
+			return err
+		}
+
+		var filtered []result.CommandResultSummary
+		for _, s := range summaries {
+			if !filter.match(&s) {
+				continue
+			}
+			filtered = append(filtered, s)
+			if cmd.Limit != 0 && len(filtered) >= cmd.Limit {
+				break
+			}
+		}
+
+		return outputHelper(ctx, cmd.Output, func(format string) (string, error) {
+			switch format {
+			case "text":
+				return formatCommandResultSummariesText(filtered), nil
+			case "yaml":
+				return yaml.WriteYamlString(filtered)
+			default:
+				return "", fmt.Errorf("invalid format: %s", format)
+			}
+		})
+	})
+}
+
+func formatCommandResultSummariesText(summaries []result.CommandResultSummary) string {
+	var t utils.PrettyTable
+	t.AddRow("Id", "Start time", "Project", "Target", "Command", "Initiator", "Changes", "Errors", "Warnings")
+
+	for _, s := range summaries {
+		project := path.Join(s.ProjectKey.GitRepoKey.String(), s.ProjectKey.SubDir)
+		target := s.TargetKey.TargetName
+		if target == "" {
+			target = "<no-name>"
+		}
+		command := s.Command.Command
+		if s.Command.DryRun {
+			command += " (dry-run)"
+		}
+		t.AddRow(s.Id,
+			s.Command.StartTime.Format(time.RFC3339),
+			project,
+			target,
+			command,
+			string(s.Command.Initiator),
+			strconv.Itoa(s.TotalChanges),
+			strconv.Itoa(len(s.Errors)),
+			strconv.Itoa(len(s.Warnings)),
+		)
+	}
+	return t.Render([]int{-1, -1, 60, -1, -1, -1, -1, -1, -1})
+}
@@ -0,0 +1,44 @@
+package commands
+
+import (
+	"context"
+	"github.com/kluctl/kluctl/v2/cmd/kluctl/args"
+	"github.com/kluctl/kluctl/v2/pkg/diff"
+	"github.com/kluctl/kluctl/v2/pkg/results"
+	"github.com/kluctl/kluctl/v2/pkg/status"
+)
+
+type resultsShowCmd struct {
+	args.CommandResultReadOnlyFlags
+	args.OutputFormatFlags
+
+	ResultId string `group:"misc" help:"Id of the command result to show."`
+}
+
+func (cmd *resultsShowCmd) Help() string {
+	return `This command shows a single command result, in the same format as it was
+shown when the command was run originally.
+`
+}
+
+func (cmd *resultsShowCmd) Run(ctx context.Context) error {
+	return withResultsCollector(ctx, &cmd.CommandResultReadOnlyFlags, func(rc *results.ResultsCollector) error {
+		cr, err := getCommandResult(rc, cmd.ResultId)
+		if err != nil {
+			return err
+		}
+
+		if !cmd.NoObfuscate {
+			var obfuscator diff.Obfuscator
+			err = obfuscator.ObfuscateResult(cr)
+			if err != nil {
+				return err
+			}
+		}
+
+		status.Flush(ctx)
+		return outputHelper(ctx, cmd.OutputFormat, func(format string) (string, error) {
+			return formatCommandResult(cr, nil, format, cmd.ShortOutput)
+		})
+	})
+}
@@ -0,0 +1,188 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"github.com/kluctl/kluctl/v2/cmd/kluctl/args"
+	"github.com/kluctl/kluctl/v2/pkg/deployment/commands"
+	"github.com/kluctl/kluctl/v2/pkg/git"
+	"github.com/kluctl/kluctl/v2/pkg/k8s"
+	"github.com/kluctl/kluctl/v2/pkg/results"
+	"github.com/kluctl/kluctl/v2/pkg/status"
+	"github.com/kluctl/kluctl/v2/pkg/types/result"
+)
+
+type rollbackCmd struct {
+	args.ProjectDir
+	args.TargetFlags
+	args.YesFlags
+	args.DryRunFlags
+	args.ForceApplyFlags
+	args.ReplaceOnErrorFlags
+	args.AbortOnErrorFlags
+	args.ApplyConcurrencyFlags
+	args.OutputFormatFlags
+	args.K8sClientFlags
+	args.CommandResultFlags
+
+	ResultId string `group:"misc" help:"Id of the command result to roll back to."`
+	Previous int    `group:"misc" help:"Roll back to the N-th successful deployment before the most recent deployment of the project and target. The project is determined from the git repository found at --project-dir."`
+	Prune    bool   `group:"misc" help:"Prune objects that are not part of the command result that is rolled back to."`
+}
+
+func (cmd *rollbackCmd) Help() string {
+	return `This command re-applies the rendered objects stored in a previous command result,
+without loading or rendering the project again. This allows to quickly revert a
+deployment, even if the project or external variable sources have changed in the
+meantime. The command result must have been written to the cluster by a previous
+deploy command or by the Kluctl controller.
+
+Either --result-id or --previous must be specified. This command will output a
+diff between the current state and the state after rollback and ask for
+confirmation, the same way as the 'deploy' command does.
+`
+}
+
+func (cmd *rollbackCmd) Run(ctx context.Context) error {
+	if (cmd.ResultId == "") == (cmd.Previous == 0) {
+		return fmt.Errorf("either --result-id or --previous must be specified")
+	}
+
+	var contextName *string
+	if cmd.Context != "" {
+		contextName = &cmd.Context
+	}
+	clientConfig, _, err := clientConfigGetter(false)(contextName)
+	if err != nil {
+		return err
+	}
+	clientConfig.QPS = float32(cmd.KubeQps)
+	clientConfig.Burst = cmd.KubeBurst
+
+	s := status.Start(ctx, "Initializing k8s client")
+	clientFactory, err := k8s.NewClientFactoryFromConfig(ctx, clientConfig)
+	if err != nil {
+		s.Failed()
+		return err
+	}
+	k, err := k8s.NewK8sCluster(ctx, clientFactory, cmd.DryRun)
+	if err != nil {
+		s.Failed()
+		return err
+	}
+	s.Success()
+
+	resultStore, err := buildResultStore(ctx, k.ToClient, &cmd.CommandResultFlags)
+	if err != nil {
+		return err
+	}
+
+	prev, err := cmd.findCommandResult(resultStore, k)
+	if err != nil {
+		return err
+	}
+
+	cmdCtx := &commandCtx{
+		ctx: ctx,
+	}
+	if cmd.WriteCommandResult {
+		cmdCtx.resultStore = resultStore
+	}
+
+	cmd2 := commands.NewRollbackCommand(prev)
+	cmd2.ForceApply = cmd.ForceApply
+	cmd2.ReplaceOnError = cmd.ReplaceOnError
+	cmd2.ForceReplaceOnError = cmd.ForceReplaceOnError
+	cmd2.AbortOnError = cmd.AbortOnError
+	cmd2.Prune = cmd.Prune
+	cmd2.ApplyConcurrency = cmd.ApplyConcurrency
+
+	cb := func(diffResult *result.CommandResult) error {
+		return cmd.diffResultCb(cmdCtx, diffResult)
+	}
+	if cmd.Yes || cmd.DryRun {
+		cb = nil
+	}
+
+	r, err := cmd2.Run(ctx, k, cb)
+	if err != nil {
+		return err
+	}
+	err = outputCommandResult(cmdCtx, cmd.OutputFormatFlags, r, !cmd.DryRun || cmd.ForceWriteCommandResult)
+	if err != nil {
+		return err
+	}
+	if len(r.Errors) != 0 {
+		return fmt.Errorf("command failed")
+	}
+	return nil
+}
+
+func (cmd *rollbackCmd) findCommandResult(store results.ResultStore, k *k8s.K8sCluster) (*result.CommandResult, error) {
+	if cmd.ResultId != "" {
+		cr, err := store.GetCommandResult(results.GetCommandResultOptions{
+			Id: cmd.ResultId,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if cr == nil {
+			return nil, fmt.Errorf("command result %s not found", cmd.ResultId)
+		}
+		return cr, nil
+	}
+
+	projectDir, err := cmd.GetProjectDir()
+	if err != nil {
+		return nil, err
+	}
+	repoRoot, err := git.DetectGitRepositoryRoot(projectDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to detect git project root: %w", err)
+	}
+	_, projectKey, err := commands.BuildGitInfo(repoRoot, projectDir)
+	if err != nil {
+		return nil, err
+	}
+	if projectKey == nil {
+		return nil, fmt.Errorf("%s is not a git repository", repoRoot)
+	}
+	clusterId, err := commands.GetClusterId(k)
+	if err != nil {
+		return nil, err
+	}
+
+	targetName := cmd.Target
+	if cmd.TargetNameOverride != "" {
+		targetName = cmd.TargetNameOverride
+	}
+
+	cr, err := commands.FindPreviousDeployResult(store, *projectKey, targetName, clusterId, cmd.Previous)
+	if err != nil {
+		return nil, err
+	}
+	if cr == nil {
+		return nil, fmt.Errorf("no previous successful deployment found")
+	}
+	return cr, nil
+}
+
+func (cmd *rollbackCmd) diffResultCb(ctx *commandCtx, diffResult *result.CommandResult) error {
+	flags := cmd.OutputFormatFlags
+	flags.OutputFormat = nil // use default output format
+
+	err := outputCommandResult(ctx, flags, diffResult, false)
+	if err != nil {
+		return err
+	}
+	if len(diffResult.Errors) != 0 {
+		if !status.AskForConfirmation(ctx.ctx, "The diff resulted in errors, do you still want to proceed?") {
+			return fmt.Errorf("aborted")
+		}
+	} else {
+		if !status.AskForConfirmation(ctx.ctx, "The diff succeeded, do you want to proceed?") {
+			return fmt.Errorf("aborted")
+		}
+	}
+	return nil
+}
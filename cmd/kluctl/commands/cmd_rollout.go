@@ -0,0 +1,197 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"github.com/kluctl/kluctl/v2/cmd/kluctl/args"
+	"github.com/kluctl/kluctl/v2/pkg/deployment/commands"
+	"github.com/kluctl/kluctl/v2/pkg/kluctl_project"
+	"github.com/kluctl/kluctl/v2/pkg/status"
+	"github.com/kluctl/kluctl/v2/pkg/types"
+	"strings"
+	"time"
+)
+
+type rolloutCmd struct {
+	args.ProjectFlags
+	args.ArgsFlags
+	args.ImageFlags
+	args.InclusionFlags
+	args.HelmCredentials
+	args.YesFlags
+	args.DryRunFlags
+	args.ForceApplyFlags
+	args.ReplaceOnErrorFlags
+	args.AbortOnErrorFlags
+	args.HookFlags
+	args.OutputFormatFlags
+	args.ApplyConcurrencyFlags
+	args.K8sClientFlags
+	args.CommandResultFlags
+
+	Rollout string `group:"misc" help:"Name of the rollout to perform. Can be omitted if the project defines exactly one rollout."`
+
+	NoWait bool `group:"misc" help:"Don't wait for objects readiness'"`
+	Prune  bool `group:"misc" help:"Prune orphaned objects directly after deploying. See the help for the 'prune' sub-command for details.'"`
+}
+
+func (cmd *rolloutCmd) Help() string {
+	return `This command performs a rollout as defined in the 'rollouts' section of .kluctl.yaml.
+A rollout consists of multiple waves, each containing one or more targets. The waves
+are processed in the given order. All targets of a wave are deployed first and then
+validated, the same way as the 'deploy' and 'validate' commands do. A failing
+deployment or validation stops the whole rollout, so that following waves are not
+touched. A wave can optionally define a pause, which is either a fixed duration or
+a confirmation prompt, that has to pass before the next wave is started.
+
+Command results are written for each deployed target.
+`
+}
+
+func (cmd *rolloutCmd) Run(ctx context.Context) error {
+	return withKluctlProjectFromArgs(ctx, cmd.ProjectFlags, &cmd.ArgsFlags, false, true, false, func(ctx context.Context, p *kluctl_project.LoadedKluctlProject) error {
+		rollout, err := cmd.findRollout(p)
+		if err != nil {
+			return err
+		}
+
+		for i, wave := range rollout.Waves {
+			waveName := getWaveName(wave, i)
+
+			status.Info(ctx, "Starting wave %s of rollout %s with targets %s", waveName, rollout.Name, strings.Join(wave.Targets, ", "))
+
+			for _, targetName := range wave.Targets {
+				err = cmd.deployTarget(ctx, p, targetName)
+				if err != nil {
+					return fmt.Errorf("rollout %s failed in wave %s for target %s: %w", rollout.Name, waveName, targetName, err)
+				}
+			}
+
+			// validation happens after the whole wave got deployed, so that targets that are slow to become ready
+			// do not delay the deployment of the other targets of the same wave
+			if !cmd.DryRun {
+				for _, targetName := range wave.Targets {
+					err = cmd.validateTarget(ctx, p, targetName, wave)
+					if err != nil {
+						return fmt.Errorf("rollout %s failed in wave %s for target %s: %w", rollout.Name, waveName, targetName, err)
+					}
+				}
+			}
+
+			if i+1 < len(rollout.Waves) {
+				err = cmd.pause(ctx, wave, waveName, getWaveName(rollout.Waves[i+1], i+1))
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		status.Info(ctx, "Rollout %s finished successfully", rollout.Name)
+		return nil
+	})
+}
+
+func (cmd *rolloutCmd) findRollout(p *kluctl_project.LoadedKluctlProject) (*types.Rollout, error) {
+	if cmd.Rollout != "" {
+		return p.FindRollout(cmd.Rollout)
+	}
+	if len(p.Config.Rollouts) != 1 {
+		return nil, fmt.Errorf("--rollout must be specified when the project does not define exactly one rollout")
+	}
+	return p.FindRollout(p.Config.Rollouts[0].Name)
+}
+
+func (cmd *rolloutCmd) deployTarget(ctx context.Context, p *kluctl_project.LoadedKluctlProject, targetName string) error {
+	ptArgs := projectTargetCommandArgs{
+		projectFlags: cmd.ProjectFlags,
+		targetFlags: args.TargetFlags{
+			Target: targetName,
+		},
+		argsFlags:          cmd.ArgsFlags,
+		imageFlags:         cmd.ImageFlags,
+		inclusionFlags:     cmd.InclusionFlags,
+		helmCredentials:    cmd.HelmCredentials,
+		dryRunArgs:         &cmd.DryRunFlags,
+		k8sClientFlags:     &cmd.K8sClientFlags,
+		commandResultFlags: &cmd.CommandResultFlags,
+	}
+	return withProjectTargetCommandContext(ctx, ptArgs, p, func(cmdCtx *commandCtx) error {
+		deploy := deployCmd{
+			YesFlags:              cmd.YesFlags,
+			DryRunFlags:           cmd.DryRunFlags,
+			ForceApplyFlags:       cmd.ForceApplyFlags,
+			ReplaceOnErrorFlags:   cmd.ReplaceOnErrorFlags,
+			AbortOnErrorFlags:     cmd.AbortOnErrorFlags,
+			HookFlags:             cmd.HookFlags,
+			OutputFormatFlags:     cmd.OutputFormatFlags,
+			ApplyConcurrencyFlags: cmd.ApplyConcurrencyFlags,
+			CommandResultFlags:    cmd.CommandResultFlags,
+			NoWait:                cmd.NoWait,
+			Prune:                 cmd.Prune,
+		}
+		return deploy.runCmdDeploy(cmdCtx)
+	})
+}
+
+func (cmd *rolloutCmd) validateTarget(ctx context.Context, p *kluctl_project.LoadedKluctlProject, targetName string, wave *types.RolloutWave) error {
+	ptArgs := projectTargetCommandArgs{
+		projectFlags: cmd.ProjectFlags,
+		targetFlags: args.TargetFlags{
+			Target: targetName,
+		},
+		argsFlags:       cmd.ArgsFlags,
+		imageFlags:      cmd.ImageFlags,
+		inclusionFlags:  cmd.InclusionFlags,
+		helmCredentials: cmd.HelmCredentials,
+		k8sClientFlags:  &cmd.K8sClientFlags,
+	}
+	return withProjectTargetCommandContext(ctx, ptArgs, p, func(cmdCtx *commandCtx) error {
+		validate := validateCmd{
+			Sleep: 5 * time.Second,
+		}
+		if wave.Validation != nil {
+			if wave.Validation.Wait != nil {
+				validate.Wait = wave.Validation.Wait.Duration
+			}
+			if wave.Validation.Sleep != nil {
+				validate.Sleep = wave.Validation.Sleep.Duration
+			}
+			validate.WarningsAsErrors = wave.Validation.WarningsAsErrors
+		}
+
+		cmd2 := commands.NewValidateCommand(cmdCtx.ctx, cmdCtx.targetCtx.Target.Discriminator, cmdCtx.targetCtx.DeploymentCollection, nil)
+		cmd2.ApplyConcurrency = cmd.ApplyConcurrency
+		return validate.doValidate(cmdCtx.ctx, cmdCtx.targetCtx.SharedContext.K, cmd2, cmdCtx.targetCtx.DeploymentCollection)
+	})
+}
+
+func (cmd *rolloutCmd) pause(ctx context.Context, wave *types.RolloutWave, waveName string, nextWaveName string) error {
+	if wave.Pause == nil || cmd.DryRun {
+		return nil
+	}
+
+	if wave.Pause.Duration != nil && wave.Pause.Duration.Duration > 0 {
+		s := status.Start(ctx, "Pausing for %s before starting wave %s", wave.Pause.Duration.Duration.String(), nextWaveName)
+		defer s.Failed()
+		select {
+		case <-time.After(wave.Pause.Duration.Duration):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		s.Success()
+	}
+
+	if wave.Pause.Confirm && !cmd.Yes {
+		if !status.AskForConfirmation(ctx, fmt.Sprintf("Wave %s finished successfully, do you want to proceed with wave %s?", waveName, nextWaveName)) {
+			return fmt.Errorf("aborted")
+		}
+	}
+	return nil
+}
+
+func getWaveName(wave *types.RolloutWave, idx int) string {
+	if wave.Name != "" {
+		return wave.Name
+	}
+	return fmt.Sprintf("%d", idx+1)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"github.com/kluctl/kluctl/v2/cmd/kluctl/args"
+	"github.com/kluctl/kluctl/v2/pkg/deployment"
 	"github.com/kluctl/kluctl/v2/pkg/deployment/commands"
 	k8s2 "github.com/kluctl/kluctl/v2/pkg/k8s"
 	"github.com/kluctl/kluctl/v2/pkg/types/result"
@@ -13,12 +14,13 @@ import (
 
 type validateCmd struct {
 	args.ProjectFlags
-	args.TargetFlags
+	args.MultiTargetFlags
 	args.ArgsFlags
 	args.InclusionFlags
 	args.HelmCredentials
-	args.OutputFlags
 	args.RenderOutputDirFlags
+	args.ApplyConcurrencyFlags
+	args.ValidateOutputFlags
 
 	CommandResult args.ExistingFileType `group:"misc" help:"Specify a command result to use instead of loading a project. This will also perform drift detection."`
 
@@ -36,7 +38,6 @@ TODO: This needs to be better documented!`
 func (cmd *validateCmd) Run(ctx context.Context) error {
 	ptArgs := projectTargetCommandArgs{
 		projectFlags:         cmd.ProjectFlags,
-		targetFlags:          cmd.TargetFlags,
 		argsFlags:            cmd.ArgsFlags,
 		inclusionFlags:       cmd.InclusionFlags,
 		helmCredentials:      cmd.HelmCredentials,
@@ -67,17 +68,19 @@ func (cmd *validateCmd) Run(ctx context.Context) error {
 		}
 
 		cmd2 := commands.NewValidateCommand(ctx, "", nil, commandResult)
-		return cmd.doValidate(ctx, k, cmd2)
+		cmd2.ApplyConcurrency = cmd.ApplyConcurrency
+		return cmd.doValidate(ctx, k, cmd2, nil)
 
 	} else {
-		return withProjectCommandContext(ctx, ptArgs, func(cmdCtx *commandCtx) error {
+		return withMultiTargetCommandContext(ctx, ptArgs, cmd.MultiTargetFlags, func(cmdCtx *commandCtx) error {
 			cmd2 := commands.NewValidateCommand(cmdCtx.ctx, cmdCtx.targetCtx.Target.Discriminator, cmdCtx.targetCtx.DeploymentCollection, nil)
-			return cmd.doValidate(cmdCtx.ctx, cmdCtx.targetCtx.SharedContext.K, cmd2)
+			cmd2.ApplyConcurrency = cmd.ApplyConcurrency
+			return cmd.doValidate(cmdCtx.ctx, cmdCtx.targetCtx.SharedContext.K, cmd2, cmdCtx.targetCtx.DeploymentCollection)
 		})
 	}
 }
 
-func (cmd *validateCmd) doValidate(ctx context.Context, k *k8s2.K8sCluster, cmd2 *commands.ValidateCommand) error {
+func (cmd *validateCmd) doValidate(ctx context.Context, k *k8s2.K8sCluster, cmd2 *commands.ValidateCommand, c *deployment.DeploymentCollection) error {
 	startTime := time.Now()
 	for true {
 		result, err := cmd2.Run(ctx, k)
@@ -86,7 +89,7 @@ func (cmd *validateCmd) doValidate(ctx context.Context, k *k8s2.K8sCluster, cmd2
 		}
 		failed := len(result.Errors) != 0 || (cmd.WarningsAsErrors && len(result.Warnings) != 0)
 
-		err = outputValidateResult(ctx, cmd.Output, result)
+		err = outputValidateResult(ctx, cmd.Output, result, c)
 		if err != nil {
 			return err
 		}
@@ -2,24 +2,26 @@ package commands
 
 import (
 	"context"
-	"fmt"
+	"github.com/kluctl/kluctl/v2/cmd/kluctl/args"
 	"github.com/kluctl/kluctl/v2/pkg/results"
 	"github.com/kluctl/kluctl/v2/pkg/status"
 	"github.com/kluctl/kluctl/v2/pkg/webui"
-	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"time"
 )
 
 type webuiCmd struct {
-	Port        int      `group:"misc" help:"Port to serve the api and webui." default:"8080"`
-	Context     []string `group:"misc" help:"List of kubernetes contexts to use."`
-	AllContexts bool     `group:"misc" help:"Use all Kubernetes contexts found in the kubeconfig."`
-	StaticPath  string   `group:"misc" help:"Build static webui."`
+	Port       int    `group:"misc" help:"Port to serve the api and webui." default:"8080"`
+	StaticPath string `group:"misc" help:"Build static webui."`
+
+	args.CommandResultReadOnlyFlags
 
 	InCluster        bool   `group:"misc" help:"This enables in-cluster functionality."`
 	InClusterContext string `group:"misc" help:"The context to use fo in-cluster functionality."`
+
+	CommandResultRetentionInterval time.Duration `group:"results" help:"Periodically apply the command result retention policy to all command result stores. Disabled by default."`
+	args.CommandResultRetentionFlags
 }
 
 func (cmd *webuiCmd) Help() string {
@@ -44,7 +46,7 @@ func (cmd *webuiCmd) Run(ctx context.Context) error {
 		}
 	}
 
-	stores, configs, err := cmd.createResultStores(ctx)
+	stores, configs, err := createResultStores(ctx, &cmd.CommandResultReadOnlyFlags, cmd.InCluster)
 	if err != nil {
 		return err
 	}
@@ -52,6 +54,14 @@ func (cmd *webuiCmd) Run(ctx context.Context) error {
 	collector := results.NewResultsCollector(ctx, stores)
 	collector.Start()
 
+	if cmd.CommandResultRetentionInterval != 0 && cmd.StaticPath == "" {
+		policy, err := buildRetentionPolicy(&cmd.CommandResultRetentionFlags)
+		if err != nil {
+			return err
+		}
+		go cmd.runRetention(ctx, stores, policy)
+	}
+
 	if cmd.StaticPath != "" {
 		st := status.Start(ctx, "Collecting results")
 		defer st.Failed()
@@ -68,68 +78,21 @@ func (cmd *webuiCmd) Run(ctx context.Context) error {
 	}
 }
 
-func (cmd *webuiCmd) createResultStores(ctx context.Context) ([]results.ResultStore, []*rest.Config, error) {
-	r := clientcmd.NewDefaultClientConfigLoadingRules()
-
-	kcfg, err := r.Load()
-	if err != nil {
-		return nil, nil, err
-	}
-
-	var stores []results.ResultStore
-	var configs []*rest.Config
-
-	var contexts []string
-	if cmd.AllContexts {
-		for name, _ := range kcfg.Contexts {
-			contexts = append(contexts, name)
-		}
-	} else if cmd.InCluster {
-		// placeholder for current context
-		contexts = append(contexts, "")
-	} else {
-		if len(cmd.Context) == 0 {
-			// placeholder for current context
-			contexts = append(contexts, "")
-		}
-		for _, c := range cmd.Context {
-			found := false
-			for name, _ := range kcfg.Contexts {
-				if c == name {
-					contexts = append(contexts, name)
-					found = true
-					break
-				}
+func (cmd *webuiCmd) runRetention(ctx context.Context, stores []results.ResultStore, policy results.RetentionPolicy) {
+	ticker := time.NewTicker(cmd.CommandResultRetentionInterval)
+	defer ticker.Stop()
+	for {
+		for _, store := range stores {
+			err := results.ApplyRetentionPolicy(ctx, store, policy)
+			if err != nil {
+				status.Warning(ctx, "Failed to apply command result retention policy: %s", err)
 			}
-			if !found {
-				return nil, nil, fmt.Errorf("context '%s' not found in kubeconfig", c)
-			}
-		}
-	}
-
-	for _, c := range contexts {
-		configOverrides := &clientcmd.ConfigOverrides{
-			CurrentContext: c,
-		}
-		config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
-			r,
-			configOverrides).ClientConfig()
-		if err != nil {
-			return nil, nil, err
 		}
 
-		client, err := client.NewWithWatch(config, client.Options{})
-		if err != nil {
-			return nil, nil, err
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
 		}
-
-		store, err := results.NewResultStoreSecrets(ctx, client, "", 0)
-		if err != nil {
-			return nil, nil, err
-		}
-		stores = append(stores, store)
-		configs = append(configs, config)
 	}
-
-	return stores, configs, nil
 }
@@ -5,6 +5,7 @@ import (
 	"context"
 	"fmt"
 	"github.com/kluctl/kluctl/v2/cmd/kluctl/args"
+	"github.com/kluctl/kluctl/v2/pkg/deployment"
 	"github.com/kluctl/kluctl/v2/pkg/diff"
 	"github.com/kluctl/kluctl/v2/pkg/status"
 	"github.com/kluctl/kluctl/v2/pkg/types/k8s"
@@ -16,6 +17,33 @@ import (
 	"strings"
 )
 
+// objectSources maps object refs (without version) to the source file of the deployment item that rendered the object
+type objectSources map[k8s.ObjectRef]string
+
+func buildObjectSources(c *deployment.DeploymentCollection) objectSources {
+	ret := objectSources{}
+	if c == nil {
+		return ret
+	}
+	for _, di := range c.Deployments {
+		f := di.GetRelSourceFile()
+		if f == "" {
+			continue
+		}
+		for _, o := range di.Objects {
+			ref := o.GetK8sRef()
+			ref.Version = ""
+			ret[ref] = f
+		}
+	}
+	return ret
+}
+
+func (s objectSources) get(ref k8s.ObjectRef) string {
+	ref.Version = ""
+	return s[ref]
+}
+
 func formatCommandResultText(cr *result.CommandResult, short bool) string {
 	buf := bytes.NewBuffer(nil)
 
@@ -129,12 +157,18 @@ func formatCommandResultYaml(cr *result.CommandResult) (string, error) {
 	return b, nil
 }
 
-func formatCommandResult(cr *result.CommandResult, format string, short bool) (string, error) {
+func formatCommandResult(cr *result.CommandResult, sources objectSources, format string, short bool) (string, error) {
 	switch format {
 	case "text":
 		return formatCommandResultText(cr, short), nil
 	case "yaml":
 		return formatCommandResultYaml(cr)
+	case "markdown":
+		return formatCommandResultMarkdown(cr), nil
+	case "junit":
+		return formatCommandResultJunit(cr, sources)
+	case "sarif":
+		return formatCommandResultSarif(cr, sources)
 	default:
 		return "", fmt.Errorf("invalid format: %s", format)
 	}
@@ -202,12 +236,16 @@ func formatValidateResultYaml(vr *result.ValidateResult) (string, error) {
 	return string(b), nil
 }
 
-func formatValidateResult(vr *result.ValidateResult, format string) (string, error) {
+func formatValidateResult(vr *result.ValidateResult, sources objectSources, format string) (string, error) {
 	switch format {
 	case "text":
 		return formatValidateResultText(vr), nil
 	case "yaml":
 		return formatValidateResultYaml(vr)
+	case "junit":
+		return formatValidateResultJunit(vr, sources)
+	case "sarif":
+		return formatValidateResultSarif(vr, sources)
 	default:
 		return "", fmt.Errorf("invalid validation result format: %s", format)
 	}
@@ -250,6 +288,8 @@ func outputCommandResult(ctx *commandCtx, flags args.OutputFormatFlags, cr *resu
 		}
 	}
 
+	recordTargetResult(ctx.ctx, cr, nil)
+
 	var resultStoreErr error
 	if writeToResultStore && ctx.resultStore != nil {
 		s := status.Start(ctx.ctx, "Writing command result")
@@ -262,8 +302,13 @@ func outputCommandResult(ctx *commandCtx, flags args.OutputFormatFlags, cr *resu
 		}
 	}
 
+	var sources objectSources
+	if ctx.targetCtx != nil {
+		sources = buildObjectSources(ctx.targetCtx.DeploymentCollection)
+	}
+
 	err := outputHelper(ctx.ctx, flags.OutputFormat, func(format string) (string, error) {
-		return formatCommandResult(cr, format, flags.ShortOutput)
+		return formatCommandResult(cr, sources, format, flags.ShortOutput)
 	})
 	if err == nil && resultStoreErr != nil {
 		return resultStoreErr
@@ -271,11 +316,14 @@ func outputCommandResult(ctx *commandCtx, flags args.OutputFormatFlags, cr *resu
 	return err
 }
 
-func outputValidateResult(ctx context.Context, output []string, vr *result.ValidateResult) error {
+func outputValidateResult(ctx context.Context, output []string, vr *result.ValidateResult, c *deployment.DeploymentCollection) error {
 	status.Flush(ctx)
 
+	recordTargetResult(ctx, nil, vr)
+
+	sources := buildObjectSources(c)
 	return outputHelper(ctx, output, func(format string) (string, error) {
-		return formatValidateResult(vr, format)
+		return formatValidateResult(vr, sources, format)
 	})
 }
 
@@ -0,0 +1,208 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"github.com/kluctl/kluctl/v2/pkg/types/k8s"
+	"github.com/kluctl/kluctl/v2/pkg/types/result"
+	"sort"
+	"strings"
+)
+
+type junitTestSuites struct {
+	XMLName  xml.Name          `xml:"testsuites"`
+	Name     string            `xml:"name,attr"`
+	Tests    int               `xml:"tests,attr"`
+	Failures int               `xml:"failures,attr"`
+	Suites   []*junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Cases    []*junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	File      string        `xml:"file,attr,omitempty"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	SystemOut *junitOutput  `xml:"system-out,omitempty"`
+}
+
+type junitOutput struct {
+	Text string `xml:",cdata"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",cdata"`
+}
+
+// junitBuilder collects test cases per object. Each object becomes a test case, grouped into one test suite per
+// deployment item. Errors, warnings and validation results are reported as failures.
+type junitBuilder struct {
+	sources objectSources
+
+	refs     []k8s.ObjectRef
+	messages map[k8s.ObjectRef][]junitMessage
+	outputs  map[k8s.ObjectRef]string
+}
+
+type junitMessage struct {
+	typ     string
+	message string
+}
+
+func newJunitBuilder(sources objectSources) *junitBuilder {
+	return &junitBuilder{
+		sources:  sources,
+		messages: map[k8s.ObjectRef][]junitMessage{},
+		outputs:  map[k8s.ObjectRef]string{},
+	}
+}
+
+func (b *junitBuilder) addRef(ref k8s.ObjectRef) {
+	// objects are identified without versions, as these might differ between rendered and remote objects
+	ref.Version = ""
+	if _, ok := b.messages[ref]; ok {
+		return
+	}
+	b.refs = append(b.refs, ref)
+	b.messages[ref] = nil
+}
+
+func (b *junitBuilder) addMessage(ref k8s.ObjectRef, typ string, message string) {
+	b.addRef(ref)
+	ref.Version = ""
+	b.messages[ref] = append(b.messages[ref], junitMessage{typ: typ, message: message})
+}
+
+func (b *junitBuilder) addErrors(typ string, errors []result.DeploymentError) {
+	for _, e := range errors {
+		b.addMessage(e.Ref, typ, e.Message)
+	}
+}
+
+func (b *junitBuilder) addChanges(ref k8s.ObjectRef, changes []result.Change) {
+	if len(changes) == 0 {
+		return
+	}
+	b.addRef(ref)
+	buf := bytes.NewBuffer(nil)
+	prettyChanges(buf, ref, changes)
+	ref.Version = ""
+	b.outputs[ref] = buf.String()
+}
+
+func (b *junitBuilder) build(name string) (string, error) {
+	ret := junitTestSuites{
+		Name: name,
+	}
+
+	suites := map[string]*junitTestSuite{}
+	for _, ref := range b.refs {
+		file := b.sources.get(ref)
+		suiteName := "other"
+		if file != "" {
+			suiteName = file
+		}
+
+		suite, ok := suites[suiteName]
+		if !ok {
+			suite = &junitTestSuite{
+				Name: suiteName,
+			}
+			suites[suiteName] = suite
+			ret.Suites = append(ret.Suites, suite)
+		}
+
+		tc := &junitTestCase{
+			Name:      ref.String(),
+			ClassName: suiteName,
+			File:      file,
+		}
+		if o, ok := b.outputs[ref]; ok {
+			tc.SystemOut = &junitOutput{Text: o}
+		}
+		if tc.Name == "" {
+			tc.Name = "<general>"
+		}
+		if msgs := b.messages[ref]; len(msgs) != 0 {
+			var lines []string
+			for _, m := range msgs {
+				lines = append(lines, fmt.Sprintf("%s: %s", m.typ, m.message))
+			}
+			tc.Failure = &junitFailure{
+				Message: msgs[0].message,
+				Type:    msgs[0].typ,
+				Text:    strings.Join(lines, "\n"),
+			}
+			suite.Failures++
+			ret.Failures++
+		}
+		suite.Cases = append(suite.Cases, tc)
+		suite.Tests++
+		ret.Tests++
+	}
+
+	sort.SliceStable(ret.Suites, func(i, j int) bool {
+		return ret.Suites[i].Name < ret.Suites[j].Name
+	})
+
+	x, err := xml.MarshalIndent(&ret, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return xml.Header + string(x) + "\n", nil
+}
+
+func formatCommandResultJunit(cr *result.CommandResult, sources objectSources) (string, error) {
+	b := newJunitBuilder(sources)
+	for _, o := range cr.Objects {
+		b.addRef(o.Ref)
+		b.addChanges(o.Ref, o.Changes)
+	}
+	b.addErrors("error", cr.Errors)
+	b.addErrors("warning", cr.Warnings)
+
+	name := "kluctl"
+	if cr.Command.Command != "" {
+		name = fmt.Sprintf("kluctl %s", cr.Command.Command)
+	}
+	if cr.Command.Target != "" {
+		name = fmt.Sprintf("%s (%s)", name, cr.Command.Target)
+	}
+	return b.build(name)
+}
+
+func formatValidateResultJunit(vr *result.ValidateResult, sources objectSources) (string, error) {
+	b := newJunitBuilder(sources)
+
+	// all rendered objects are reported, so that objects that validated fine are shown as passed tests
+	var refs []k8s.ObjectRef
+	for ref := range sources {
+		refs = append(refs, ref)
+	}
+	sort.Slice(refs, func(i, j int) bool {
+		return refs[i].String() < refs[j].String()
+	})
+	for _, ref := range refs {
+		b.addRef(ref)
+	}
+
+	b.addErrors("error", vr.Errors)
+	b.addErrors("warning", vr.Warnings)
+	for _, r := range vr.Results {
+		b.addMessage(r.Ref, "result", fmt.Sprintf("%s: %s", r.Annotation, r.Message))
+	}
+	for _, d := range vr.Drift {
+		b.addChanges(d.Ref, d.Changes)
+	}
+
+	return b.build("kluctl validate")
+}
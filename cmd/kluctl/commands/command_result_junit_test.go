@@ -0,0 +1,161 @@
+package commands
+
+import (
+	"encoding/xml"
+	"github.com/kluctl/kluctl/v2/pkg/types/k8s"
+	"github.com/kluctl/kluctl/v2/pkg/types/result"
+	"github.com/stretchr/testify/assert"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"strings"
+	"testing"
+)
+
+func buildTestRef(kind string, name string) k8s.ObjectRef {
+	return k8s.ObjectRef{Group: "apps", Version: "v1", Kind: kind, Name: name, Namespace: "ns"}
+}
+
+func buildTestSources() objectSources {
+	s := objectSources{}
+	for _, ref := range []k8s.ObjectRef{buildTestRef("Deployment", "d1"), buildTestRef("Deployment", "d2")} {
+		ref.Version = ""
+		s[ref] = "apps/deployment.yaml"
+	}
+	return s
+}
+
+// parseJunit parses the output and verifies that it is structurally valid, meaning that it has the testsuites root
+// element, that all test cases have names and that the counters match the test cases
+func parseJunit(t *testing.T, s string) *junitTestSuites {
+	assert.True(t, strings.HasPrefix(s, xml.Header))
+
+	var ret junitTestSuites
+	err := xml.Unmarshal([]byte(s), &ret)
+	assert.NoError(t, err)
+	assert.Equal(t, "testsuites", ret.XMLName.Local)
+
+	tests, failures := 0, 0
+	for _, suite := range ret.Suites {
+		assert.NotEmpty(t, suite.Name)
+		suiteFailures := 0
+		for _, tc := range suite.Cases {
+			assert.NotEmpty(t, tc.Name)
+			assert.Equal(t, suite.Name, tc.ClassName)
+			if tc.Failure != nil {
+				assert.NotEmpty(t, tc.Failure.Type)
+				suiteFailures++
+			}
+		}
+		assert.Equal(t, len(suite.Cases), suite.Tests)
+		assert.Equal(t, suiteFailures, suite.Failures)
+		tests += suite.Tests
+		failures += suite.Failures
+	}
+	assert.Equal(t, tests, ret.Tests)
+	assert.Equal(t, failures, ret.Failures)
+	return &ret
+}
+
+func findJunitCase(ts *junitTestSuites, name string) *junitTestCase {
+	for _, suite := range ts.Suites {
+		for _, tc := range suite.Cases {
+			if tc.Name == name {
+				return tc
+			}
+		}
+	}
+	return nil
+}
+
+func TestFormatCommandResultJunit(t *testing.T) {
+	d1 := buildTestRef("Deployment", "d1")
+	d2 := buildTestRef("Deployment", "d2")
+	cm := k8s.ObjectRef{Version: "v1", Kind: "ConfigMap", Name: "cm", Namespace: "ns"}
+
+	cr := &result.CommandResult{
+		Command: result.CommandInfo{
+			Command: "deploy",
+			Target:  "prod",
+		},
+		Objects: []result.ResultObject{
+			{BaseObject: result.BaseObject{Ref: d1, Changes: []result.Change{{
+				Type:        "update",
+				JsonPath:    "spec.replicas",
+				OldValue:    &apiextensionsv1.JSON{Raw: []byte(`1`)},
+				NewValue:    &apiextensionsv1.JSON{Raw: []byte(`2`)},
+				UnifiedDiff: "-1\n+2",
+			}}}},
+			{BaseObject: result.BaseObject{Ref: d2}},
+			{BaseObject: result.BaseObject{Ref: cm}},
+		},
+		Errors:   []result.DeploymentError{{Ref: d2, Message: "apply failed"}},
+		Warnings: []result.DeploymentError{{Ref: cm, Message: "deprecated"}, {Message: "general warning"}},
+	}
+
+	s, err := formatCommandResultJunit(cr, buildTestSources())
+	assert.NoError(t, err)
+	ts := parseJunit(t, s)
+
+	assert.Equal(t, "kluctl deploy (prod)", ts.Name)
+	assert.Equal(t, 4, ts.Tests)
+	assert.Equal(t, 3, ts.Failures)
+
+	// objects are grouped by their source file
+	var suiteNames []string
+	for _, suite := range ts.Suites {
+		suiteNames = append(suiteNames, suite.Name)
+	}
+	assert.Equal(t, []string{"apps/deployment.yaml", "other"}, suiteNames)
+
+	tc := findJunitCase(ts, d1.String())
+	assert.NotNil(t, tc)
+	assert.Equal(t, "apps/deployment.yaml", tc.File)
+	assert.Nil(t, tc.Failure)
+	assert.NotNil(t, tc.SystemOut)
+	assert.Contains(t, tc.SystemOut.Text, "spec.replicas")
+
+	tc = findJunitCase(ts, d2.String())
+	assert.NotNil(t, tc)
+	assert.Equal(t, &junitFailure{Message: "apply failed", Type: "error", Text: "error: apply failed"}, tc.Failure)
+
+	tc = findJunitCase(ts, cm.String())
+	assert.NotNil(t, tc)
+	assert.Empty(t, tc.File)
+	assert.Equal(t, &junitFailure{Message: "deprecated", Type: "warning", Text: "warning: deprecated"}, tc.Failure)
+
+	tc = findJunitCase(ts, "<general>")
+	assert.NotNil(t, tc)
+	assert.Equal(t, "warning", tc.Failure.Type)
+}
+
+func TestFormatValidateResultJunit(t *testing.T) {
+	d1 := buildTestRef("Deployment", "d1")
+	d2 := buildTestRef("Deployment", "d2")
+
+	vr := &result.ValidateResult{
+		Errors:   []result.DeploymentError{{Ref: d2, Message: "not ready"}},
+		Warnings: []result.DeploymentError{{Ref: d2, Message: "slow"}},
+		Results:  []result.ValidateResultEntry{{Ref: d2, Annotation: "validate-result.kluctl.io/check", Message: "check failed"}},
+	}
+
+	s, err := formatValidateResultJunit(vr, buildTestSources())
+	assert.NoError(t, err)
+	ts := parseJunit(t, s)
+
+	assert.Equal(t, "kluctl validate", ts.Name)
+	assert.Equal(t, 2, ts.Tests)
+	assert.Equal(t, 1, ts.Failures)
+
+	// rendered objects without any messages are reported as passed tests
+	tc := findJunitCase(ts, d1.String())
+	assert.NotNil(t, tc)
+	assert.Nil(t, tc.Failure)
+
+	tc = findJunitCase(ts, d2.String())
+	assert.NotNil(t, tc)
+	assert.Equal(t, "apps/deployment.yaml", tc.File)
+	assert.Equal(t, &junitFailure{
+		Message: "not ready",
+		Type:    "error",
+		Text:    "error: not ready\nwarning: slow\nresult: validate-result.kluctl.io/check: check failed",
+	}, tc.Failure)
+}
@@ -0,0 +1,140 @@
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/kluctl/kluctl/v2/pkg/types/k8s"
+	"github.com/kluctl/kluctl/v2/pkg/types/result"
+	"strings"
+)
+
+// formatCommandResultMarkdown renders the command result as a markdown report, suitable to be posted as PR comment.
+// Object lists and diffs are rendered inside collapsible sections so that large changes stay readable.
+func formatCommandResultMarkdown(cr *result.CommandResult) string {
+	buf := bytes.NewBuffer(nil)
+	summary := cr.BuildSummary()
+
+	title := "Kluctl"
+	if cr.Command.Command != "" {
+		title = fmt.Sprintf("Kluctl %s", cr.Command.Command)
+	}
+	if cr.Command.Target != "" {
+		title = fmt.Sprintf("%s for target `%s`", title, cr.Command.Target)
+	}
+	buf.WriteString(fmt.Sprintf("### %s\n\n", title))
+
+	buf.WriteString("| New | Changed | Deleted | Orphan | Applied hooks | Total changes | Errors | Warnings |\n")
+	buf.WriteString("|---|---|---|---|---|---|---|---|\n")
+	buf.WriteString(fmt.Sprintf("| %d | %d | %d | %d | %d | %d | %d | %d |\n",
+		summary.NewObjects, summary.ChangedObjects, summary.DeletedObjects, summary.OrphanObjects,
+		summary.AppliedHookObjects, summary.TotalChanges, len(summary.Errors), len(summary.Warnings)))
+
+	if len(cr.Errors) != 0 {
+		buf.WriteString("\n#### Errors\n\n")
+		markdownErrors(buf, cr.Errors)
+	}
+	if len(cr.Warnings) != 0 {
+		buf.WriteString("\n#### Warnings\n\n")
+		markdownErrors(buf, cr.Warnings)
+	}
+
+	var newObjects []k8s.ObjectRef
+	var changedObjects []result.ResultObject
+	var deletedObjects []k8s.ObjectRef
+	var orphanObjects []k8s.ObjectRef
+	var appliedHookObjects []k8s.ObjectRef
+
+	for _, o := range cr.Objects {
+		if o.New {
+			newObjects = append(newObjects, o.Ref)
+		}
+		if len(o.Changes) != 0 {
+			changedObjects = append(changedObjects, o)
+		}
+		if o.Deleted {
+			deletedObjects = append(deletedObjects, o.Ref)
+		}
+		if o.Orphan {
+			orphanObjects = append(orphanObjects, o.Ref)
+		}
+		if o.Hook {
+			appliedHookObjects = append(appliedHookObjects, o.Ref)
+		}
+	}
+
+	markdownObjectRefs(buf, "New objects", newObjects)
+
+	if len(changedObjects) != 0 {
+		buf.WriteString(fmt.Sprintf("\n<details>\n<summary>Changed objects (%d)</summary>\n\n", len(changedObjects)))
+		for _, o := range changedObjects {
+			buf.WriteString(fmt.Sprintf("<details>\n<summary><code>%s</code> (%d changes)</summary>\n\n", o.Ref.String(), len(o.Changes)))
+			markdownChanges(buf, o.Changes)
+			buf.WriteString("\n</details>\n\n")
+		}
+		buf.WriteString("</details>\n")
+	}
+
+	markdownObjectRefs(buf, "Deleted objects", deletedObjects)
+	markdownObjectRefs(buf, "Applied hooks", appliedHookObjects)
+	markdownObjectRefs(buf, "Orphan objects", orphanObjects)
+
+	return buf.String()
+}
+
+func markdownObjectRefs(buf *bytes.Buffer, title string, refs []k8s.ObjectRef) {
+	if len(refs) == 0 {
+		return
+	}
+	buf.WriteString(fmt.Sprintf("\n<details>\n<summary>%s (%d)</summary>\n\n", title, len(refs)))
+	for _, ref := range refs {
+		buf.WriteString(fmt.Sprintf("- `%s`\n", ref.String()))
+	}
+	buf.WriteString("\n</details>\n")
+}
+
+func markdownErrors(buf *bytes.Buffer, errors []result.DeploymentError) {
+	for _, e := range errors {
+		if s := e.Ref.String(); s != "" {
+			buf.WriteString(fmt.Sprintf("- `%s`: %s\n", s, e.Message))
+		} else {
+			buf.WriteString(fmt.Sprintf("- %s\n", e.Message))
+		}
+	}
+}
+
+func markdownChanges(buf *bytes.Buffer, changes []result.Change) {
+	var diff strings.Builder
+	for i, c := range changes {
+		if i != 0 {
+			diff.WriteString("\n")
+		}
+		diff.WriteString(fmt.Sprintf("# %s\n", c.JsonPath))
+		diff.WriteString(strings.TrimSuffix(c.UnifiedDiff, "\n"))
+		diff.WriteString("\n")
+	}
+
+	fence := markdownFence(diff.String())
+	buf.WriteString(fence + "diff\n")
+	buf.WriteString(diff.String())
+	buf.WriteString(fence + "\n")
+}
+
+// markdownFence returns a code fence that is longer than any sequence of backticks found in s
+func markdownFence(s string) string {
+	longest := 0
+	cur := 0
+	for _, c := range s {
+		if c == '`' {
+			cur++
+			if cur > longest {
+				longest = cur
+			}
+		} else {
+			cur = 0
+		}
+	}
+	if longest < 3 {
+		return "```"
+	}
+	return strings.Repeat("`", longest+1)
+}
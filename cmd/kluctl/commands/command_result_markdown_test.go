@@ -0,0 +1,94 @@
+package commands
+
+import (
+	"github.com/kluctl/kluctl/v2/pkg/diff"
+	"github.com/kluctl/kluctl/v2/pkg/types/k8s"
+	"github.com/kluctl/kluctl/v2/pkg/types/result"
+	"github.com/stretchr/testify/assert"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"strings"
+	"testing"
+)
+
+func buildMarkdownTestResult() *result.CommandResult {
+	secret := k8s.ObjectRef{Version: "v1", Kind: "Secret", Name: "s", Namespace: "ns"}
+	return &result.CommandResult{
+		Command: result.CommandInfo{
+			Command: "deploy",
+			Target:  "prod",
+		},
+		Objects: []result.ResultObject{
+			{BaseObject: result.BaseObject{Ref: buildTestRef("Deployment", "new"), New: true}},
+			{BaseObject: result.BaseObject{Ref: buildTestRef("Deployment", "changed"), Changes: []result.Change{{
+				Type:        "update",
+				JsonPath:    "spec.replicas",
+				OldValue:    &apiextensionsv1.JSON{Raw: []byte(`1`)},
+				NewValue:    &apiextensionsv1.JSON{Raw: []byte(`2`)},
+				UnifiedDiff: "-1\n+2",
+			}}}},
+			{BaseObject: result.BaseObject{Ref: secret, Changes: []result.Change{{
+				Type:        "update",
+				JsonPath:    "data.password",
+				OldValue:    &apiextensionsv1.JSON{Raw: []byte(`"secret-old"`)},
+				NewValue:    &apiextensionsv1.JSON{Raw: []byte(`"secret-new"`)},
+				UnifiedDiff: "-secret-old\n+secret-new",
+			}}}},
+			{BaseObject: result.BaseObject{Ref: buildTestRef("Deployment", "deleted"), Deleted: true}},
+			{BaseObject: result.BaseObject{Ref: buildTestRef("Deployment", "orphan"), Orphan: true}},
+		},
+		Errors:   []result.DeploymentError{{Ref: buildTestRef("Deployment", "changed"), Message: "apply failed"}},
+		Warnings: []result.DeploymentError{{Message: "general warning"}},
+	}
+}
+
+func TestFormatCommandResultMarkdown(t *testing.T) {
+	cr := buildMarkdownTestResult()
+
+	// same as done in outputCommandResult
+	var obfuscator diff.Obfuscator
+	err := obfuscator.ObfuscateResult(cr)
+	assert.NoError(t, err)
+
+	s := formatCommandResultMarkdown(cr)
+
+	assert.True(t, strings.HasPrefix(s, "### Kluctl deploy for target `prod`\n\n"))
+	assert.Contains(t, s, `| New | Changed | Deleted | Orphan | Applied hooks | Total changes | Errors | Warnings |
+|---|---|---|---|---|---|---|---|
+| 1 | 2 | 1 | 1 | 0 | 2 | 1 | 1 |
+`)
+
+	assert.Contains(t, s, "\n#### Errors\n\n- `ns/Deployment/changed`: apply failed\n")
+	assert.Contains(t, s, "\n#### Warnings\n\n- general warning\n")
+
+	assert.Contains(t, s, "<details>\n<summary>New objects (1)</summary>\n\n- `ns/Deployment/new`\n\n</details>\n")
+	assert.Contains(t, s, "<details>\n<summary>Deleted objects (1)</summary>\n\n- `ns/Deployment/deleted`\n\n</details>\n")
+	assert.Contains(t, s, "<details>\n<summary>Orphan objects (1)</summary>\n\n- `ns/Deployment/orphan`\n\n</details>\n")
+	assert.NotContains(t, s, "Applied hooks (")
+
+	assert.Contains(t, s, "<details>\n<summary>Changed objects (2)</summary>\n\n")
+	assert.Contains(t, s, "<details>\n<summary><code>ns/Deployment/changed</code> (1 changes)</summary>\n\n```diff\n# spec.replicas\n-1\n+2\n```\n\n</details>\n")
+
+	// secret values must only appear obfuscated
+	assert.Contains(t, s, "<summary><code>ns/Secret/s</code> (1 changes)</summary>")
+	assert.Contains(t, s, "```diff\n# data.password\n-***** (obfuscated)\n+***** (obfuscated)\n```\n")
+	assert.NotContains(t, s, "secret-old")
+	assert.NotContains(t, s, "secret-new")
+}
+
+func TestMarkdownFence(t *testing.T) {
+	assert.Equal(t, "```", markdownFence("no backticks"))
+	assert.Equal(t, "```", markdownFence("``"))
+	assert.Equal(t, "````", markdownFence("contains ``` fence"))
+
+	// diffs that contain code fences must not break out of the diff block
+	s := formatCommandResultMarkdown(&result.CommandResult{
+		Objects: []result.ResultObject{
+			{BaseObject: result.BaseObject{Ref: buildTestRef("Deployment", "d"), Changes: []result.Change{{
+				Type:        "update",
+				JsonPath:    "metadata.annotations.doc",
+				UnifiedDiff: "-```\n+````",
+			}}}},
+		},
+	})
+	assert.Contains(t, s, "`````diff\n# metadata.annotations.doc\n-```\n+````\n`````\n")
+}
@@ -0,0 +1,166 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/kluctl/kluctl/v2/pkg/types/k8s"
+	"github.com/kluctl/kluctl/v2/pkg/types/result"
+	"github.com/kluctl/kluctl/v2/pkg/version"
+)
+
+// See https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html. Only the parts required by kluctl are modelled.
+
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationUri string      `json:"informationUri"`
+	Version        string      `json:"version,omitempty"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	Id               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleId    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation *sarifPhysicalLocation `json:"physicalLocation,omitempty"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations,omitempty"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+}
+
+type sarifArtifactLocation struct {
+	Uri       string `json:"uri"`
+	UriBaseId string `json:"uriBaseId,omitempty"`
+}
+
+type sarifLogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+var sarifRules = []sarifRule{
+	{Id: "error", ShortDescription: sarifMessage{Text: "Kluctl reported an error for the object"}},
+	{Id: "warning", ShortDescription: sarifMessage{Text: "Kluctl reported a warning for the object"}},
+	{Id: "validation", ShortDescription: sarifMessage{Text: "Validation result reported by the object"}},
+	{Id: "drift", ShortDescription: sarifMessage{Text: "The object drifted from its desired state"}},
+}
+
+type sarifBuilder struct {
+	sources objectSources
+	results []sarifResult
+}
+
+func (b *sarifBuilder) add(ruleId string, level string, ref k8s.ObjectRef, message string) {
+	r := sarifResult{
+		RuleId: ruleId,
+		Level:  level,
+		Message: sarifMessage{
+			Text: message,
+		},
+	}
+	if s := ref.String(); s != "" {
+		r.Message.Text = fmt.Sprintf("%s: %s", s, message)
+
+		l := sarifLocation{
+			LogicalLocations: []sarifLogicalLocation{{
+				FullyQualifiedName: s,
+				Kind:               "object",
+			}},
+		}
+		if file := b.sources.get(ref); file != "" {
+			l.PhysicalLocation = &sarifPhysicalLocation{
+				ArtifactLocation: sarifArtifactLocation{
+					Uri:       file,
+					UriBaseId: "%SRCROOT%",
+				},
+			}
+		}
+		r.Locations = append(r.Locations, l)
+	}
+	b.results = append(b.results, r)
+}
+
+func (b *sarifBuilder) addErrors(ruleId string, level string, errors []result.DeploymentError) {
+	for _, e := range errors {
+		b.add(ruleId, level, e.Ref, e.Message)
+	}
+}
+
+func (b *sarifBuilder) build() (string, error) {
+	l := sarifLog{
+		Version: "2.1.0",
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Runs: []sarifRun{{
+			Tool: sarifTool{
+				Driver: sarifDriver{
+					Name:           "kluctl",
+					InformationUri: "https://kluctl.io",
+					Version:        version.GetVersion(),
+					Rules:          sarifRules,
+				},
+			},
+			Results: b.results,
+		}},
+	}
+	if l.Runs[0].Results == nil {
+		l.Runs[0].Results = []sarifResult{}
+	}
+
+	x, err := json.MarshalIndent(&l, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(x) + "\n", nil
+}
+
+func formatCommandResultSarif(cr *result.CommandResult, sources objectSources) (string, error) {
+	b := sarifBuilder{sources: sources}
+	b.addErrors("error", "error", cr.Errors)
+	b.addErrors("warning", "warning", cr.Warnings)
+	return b.build()
+}
+
+func formatValidateResultSarif(vr *result.ValidateResult, sources objectSources) (string, error) {
+	b := sarifBuilder{sources: sources}
+	b.addErrors("error", "error", vr.Errors)
+	b.addErrors("warning", "warning", vr.Warnings)
+	for _, r := range vr.Results {
+		b.add("validation", "note", r.Ref, fmt.Sprintf("%s: %s", r.Annotation, r.Message))
+	}
+	for _, d := range vr.Drift {
+		if len(d.Changes) == 0 {
+			continue
+		}
+		b.add("drift", "warning", d.Ref, fmt.Sprintf("object has drifted (%d changes)", len(d.Changes)))
+	}
+	return b.build()
+}
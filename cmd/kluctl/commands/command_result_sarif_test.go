@@ -0,0 +1,127 @@
+package commands
+
+import (
+	"encoding/json"
+	"github.com/kluctl/kluctl/v2/pkg/types/k8s"
+	"github.com/kluctl/kluctl/v2/pkg/types/result"
+	"github.com/stretchr/testify/assert"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"testing"
+)
+
+// parseSarif parses the output and verifies the parts of the SARIF 2.1.0 structure that are required by consumers,
+// e.g. that all results reference a known rule and use a valid level
+func parseSarif(t *testing.T, s string) *sarifLog {
+	var ret sarifLog
+	err := json.Unmarshal([]byte(s), &ret)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "2.1.0", ret.Version)
+	assert.NotEmpty(t, ret.Schema)
+	assert.Len(t, ret.Runs, 1)
+
+	// results must be present, even if empty
+	var raw map[string]any
+	err = json.Unmarshal([]byte(s), &raw)
+	assert.NoError(t, err)
+	assert.NotNil(t, raw["runs"].([]any)[0].(map[string]any)["results"])
+
+	run := ret.Runs[0]
+	assert.Equal(t, "kluctl", run.Tool.Driver.Name)
+	ruleIds := map[string]bool{}
+	for _, r := range run.Tool.Driver.Rules {
+		assert.NotEmpty(t, r.ShortDescription.Text)
+		ruleIds[r.Id] = true
+	}
+	for _, r := range run.Results {
+		assert.True(t, ruleIds[r.RuleId], r.RuleId)
+		assert.Contains(t, []string{"none", "note", "warning", "error"}, r.Level)
+		assert.NotEmpty(t, r.Message.Text)
+		for _, l := range r.Locations {
+			if l.PhysicalLocation != nil {
+				assert.NotEmpty(t, l.PhysicalLocation.ArtifactLocation.Uri)
+			}
+		}
+	}
+	return &ret
+}
+
+func TestFormatCommandResultSarif(t *testing.T) {
+	d1 := buildTestRef("Deployment", "d1")
+	cm := k8s.ObjectRef{Version: "v1", Kind: "ConfigMap", Name: "cm", Namespace: "ns"}
+
+	cr := &result.CommandResult{
+		Errors:   []result.DeploymentError{{Ref: d1, Message: "apply failed"}},
+		Warnings: []result.DeploymentError{{Ref: cm, Message: "deprecated"}, {Message: "general warning"}},
+	}
+
+	s, err := formatCommandResultSarif(cr, buildTestSources())
+	assert.NoError(t, err)
+	l := parseSarif(t, s)
+
+	results := l.Runs[0].Results
+	assert.Len(t, results, 3)
+
+	assert.Equal(t, "error", results[0].RuleId)
+	assert.Equal(t, "error", results[0].Level)
+	assert.Equal(t, d1.String()+": apply failed", results[0].Message.Text)
+	assert.Equal(t, []sarifLocation{{
+		PhysicalLocation: &sarifPhysicalLocation{
+			ArtifactLocation: sarifArtifactLocation{Uri: "apps/deployment.yaml", UriBaseId: "%SRCROOT%"},
+		},
+		LogicalLocations: []sarifLogicalLocation{{FullyQualifiedName: d1.String(), Kind: "object"}},
+	}}, results[0].Locations)
+
+	// objects without a known source file only get a logical location
+	assert.Equal(t, "warning", results[1].Level)
+	assert.Len(t, results[1].Locations, 1)
+	assert.Nil(t, results[1].Locations[0].PhysicalLocation)
+	assert.Equal(t, cm.String(), results[1].Locations[0].LogicalLocations[0].FullyQualifiedName)
+
+	// general messages have no location at all
+	assert.Equal(t, "general warning", results[2].Message.Text)
+	assert.Empty(t, results[2].Locations)
+}
+
+func TestFormatValidateResultSarif(t *testing.T) {
+	d1 := buildTestRef("Deployment", "d1")
+	d2 := buildTestRef("Deployment", "d2")
+
+	vr := &result.ValidateResult{
+		Results: []result.ValidateResultEntry{{Ref: d1, Annotation: "validate-result.kluctl.io/check", Message: "check failed"}},
+		Drift: []result.ChangedObject{
+			{Ref: d2, Changes: []result.Change{{
+				Type:     "update",
+				JsonPath: "spec.replicas",
+				OldValue: &apiextensionsv1.JSON{Raw: []byte(`1`)},
+				NewValue: &apiextensionsv1.JSON{Raw: []byte(`2`)},
+			}}},
+			// objects without changes are not reported
+			{Ref: d1},
+		},
+	}
+
+	s, err := formatValidateResultSarif(vr, buildTestSources())
+	assert.NoError(t, err)
+	l := parseSarif(t, s)
+
+	results := l.Runs[0].Results
+	assert.Len(t, results, 2)
+
+	assert.Equal(t, "validation", results[0].RuleId)
+	assert.Equal(t, "note", results[0].Level)
+	assert.Equal(t, d1.String()+": validate-result.kluctl.io/check: check failed", results[0].Message.Text)
+	assert.Equal(t, "apps/deployment.yaml", results[0].Locations[0].PhysicalLocation.ArtifactLocation.Uri)
+
+	assert.Equal(t, "drift", results[1].RuleId)
+	assert.Equal(t, "warning", results[1].Level)
+	assert.Equal(t, d2.String()+": object has drifted (1 changes)", results[1].Message.Text)
+	assert.Equal(t, "apps/deployment.yaml", results[1].Locations[0].PhysicalLocation.ArtifactLocation.Uri)
+}
+
+func TestFormatValidateResultSarif_Empty(t *testing.T) {
+	s, err := formatValidateResultSarif(&result.ValidateResult{}, objectSources{})
+	assert.NoError(t, err)
+	l := parseSarif(t, s)
+	assert.Empty(t, l.Runs[0].Results)
+}
@@ -18,6 +18,9 @@ func RegisterFlagCompletionFuncs(cmdStruct interface{}, ccmd *cobra.Command) err
 	projectFlags := v.FieldByName("ProjectFlags")
 	argsFlags := v.FieldByName("ArgsFlags")
 	targetFlags := v.FieldByName("TargetFlags")
+	if !targetFlags.IsValid() {
+		targetFlags = v.FieldByName("MultiTargetFlags")
+	}
 	inclusionFlags := v.FieldByName("InclusionFlags")
 	imageFlags := v.FieldByName("ImageFlags")
 
@@ -82,6 +85,12 @@ func buildAutocompleteProjectTargetCommandArgs(cmdStruct interface{}) projectTar
 	if cmdV.FieldByName("TargetFlags").IsValid() {
 		ptArgs.targetFlags = cmdV.FieldByName("TargetFlags").Interface().(args.TargetFlags)
 	}
+	if cmdV.FieldByName("MultiTargetFlags").IsValid() {
+		mtFlags := cmdV.FieldByName("MultiTargetFlags").Interface().(args.MultiTargetFlags)
+		if len(mtFlags.Target) == 1 {
+			ptArgs.targetFlags = mtFlags.ForTarget(mtFlags.Target[0])
+		}
+	}
 	if cmdV.FieldByName("ArgsFlags").IsValid() {
 		ptArgs.argsFlags = cmdV.FieldByName("ArgsFlags").Interface().(args.ArgsFlags)
 	}
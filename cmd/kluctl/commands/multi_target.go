@@ -0,0 +1,225 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/kluctl/kluctl/v2/cmd/kluctl/args"
+	"github.com/kluctl/kluctl/v2/pkg/kluctl_project"
+	"github.com/kluctl/kluctl/v2/pkg/status"
+	"github.com/kluctl/kluctl/v2/pkg/types"
+	"github.com/kluctl/kluctl/v2/pkg/types/result"
+	"github.com/kluctl/kluctl/v2/pkg/utils"
+	"strings"
+	"sync"
+)
+
+type targetSummaryKey int
+
+var targetSummaryKeyInst targetSummaryKey
+
+type targetSummary struct {
+	target string
+	stdout bytes.Buffer
+
+	cr  *result.CommandResult
+	vr  *result.ValidateResult
+	err error
+}
+
+// withMultiTargetCommandContext invokes cb for each target selected by mtFlags. If only a single target (or the no-name
+// target) is selected, it behaves exactly like withProjectCommandContext. Otherwise, the project is loaded once and
+// all targets are processed in parallel, each with its own TargetContext. The output of the individual targets is
+// buffered and printed after all targets are done, followed by a summary table.
+func withMultiTargetCommandContext(ctx context.Context, ptArgs projectTargetCommandArgs, mtFlags args.MultiTargetFlags, cb func(cmdCtx *commandCtx) error) error {
+	if !mtFlags.IsMultiTarget() {
+		targetName := ""
+		if len(mtFlags.Target) != 0 {
+			targetName = mtFlags.Target[0]
+		}
+		ptArgs.targetFlags = mtFlags.ForTarget(targetName)
+		return withProjectCommandContext(ctx, ptArgs, cb)
+	}
+
+	if mtFlags.TargetNameOverride != "" {
+		return fmt.Errorf("--target-name-override can not be used with multiple targets")
+	}
+	if ptArgs.renderOutputDirFlags.RenderOutputDir != "" {
+		return fmt.Errorf("--render-output-dir can not be used with multiple targets")
+	}
+
+	return withKluctlProjectFromArgs(ctx, ptArgs.projectFlags, &ptArgs.argsFlags, ptArgs.internalDeploy, true, false, func(ctx context.Context, p *kluctl_project.LoadedKluctlProject) error {
+		targets, err := selectTargets(p, mtFlags)
+		if err != nil {
+			return err
+		}
+		if len(targets) == 0 {
+			return fmt.Errorf("no targets matched")
+		}
+
+		summaries := make([]*targetSummary, len(targets))
+		var wg sync.WaitGroup
+		for i, t := range targets {
+			ts := &targetSummary{
+				target: t.Name,
+			}
+			summaries[i] = ts
+
+			ptArgs := ptArgs
+			ptArgs.targetFlags = mtFlags.ForTarget(t.Name)
+
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, stderr := getStdStreams(ctx)
+				ctx := WithStdStreams(ctx, &ts.stdout, stderr)
+				ctx = context.WithValue(ctx, targetSummaryKeyInst, ts)
+				ts.err = withProjectTargetCommandContext(ctx, ptArgs, p, cb)
+			}()
+		}
+		wg.Wait()
+
+		return outputTargetSummaries(ctx, summaries)
+	})
+}
+
+func selectTargets(p *kluctl_project.LoadedKluctlProject, mtFlags args.MultiTargetFlags) ([]*types.Target, error) {
+	var ret []*types.Target
+	if len(mtFlags.Target) != 0 {
+		for _, n := range mtFlags.Target {
+			t, err := p.FindTarget(n)
+			if err != nil {
+				return nil, err
+			}
+			ret = append(ret, t)
+		}
+	} else {
+		ret = append(ret, p.Targets...)
+	}
+
+	if len(mtFlags.TargetSelector) == 0 {
+		return ret, nil
+	}
+
+	var filtered []*types.Target
+	for _, t := range ret {
+		match := true
+		for _, sel := range mtFlags.TargetSelector {
+			m, err := matchTargetSelector(t, sel)
+			if err != nil {
+				return nil, err
+			}
+			if !m {
+				match = false
+				break
+			}
+		}
+		if match {
+			filtered = append(filtered, t)
+		}
+	}
+	return filtered, nil
+}
+
+func matchTargetSelector(t *types.Target, sel string) (bool, error) {
+	s := strings.SplitN(sel, "=", 2)
+	if len(s) != 2 || s[0] == "" {
+		return false, fmt.Errorf("invalid --target-selector '%s', must be in the form 'name=value'", sel)
+	}
+	if t.Args == nil {
+		return false, nil
+	}
+
+	var keys []any
+	for _, k := range strings.Split(s[0], ".") {
+		keys = append(keys, k)
+	}
+	v, found, err := t.Args.GetNestedField(keys...)
+	if err != nil || !found {
+		return false, nil
+	}
+	return fmt.Sprint(v) == s[1], nil
+}
+
+func recordTargetResult(ctx context.Context, cr *result.CommandResult, vr *result.ValidateResult) {
+	v := ctx.Value(targetSummaryKeyInst)
+	if v == nil {
+		return
+	}
+	ts := v.(*targetSummary)
+	if cr != nil {
+		ts.cr = cr
+	}
+	if vr != nil {
+		ts.vr = vr
+	}
+}
+
+func outputTargetSummaries(ctx context.Context, summaries []*targetSummary) error {
+	status.Flush(ctx)
+
+	stdout := getStdout(ctx)
+	for _, ts := range summaries {
+		if ts.stdout.Len() == 0 {
+			continue
+		}
+		status.Info(ctx, "Output for target %s:", ts.target)
+		status.Flush(ctx)
+		_, err := stdout.Write(ts.stdout.Bytes())
+		if err != nil {
+			return err
+		}
+	}
+
+	var t utils.PrettyTable
+	t.AddRow("Target", "Result", "New", "Changed", "Deleted", "Orphan", "Errors", "Warnings")
+
+	failed := 0
+	for _, ts := range summaries {
+		row := []string{ts.target, "succeeded", "", "", "", "", "", ""}
+		var errCount, warnCount int
+		if ts.cr != nil {
+			var newObjects, changedObjects, deletedObjects, orphanObjects int
+			for _, o := range ts.cr.Objects {
+				if o.New {
+					newObjects++
+				}
+				if len(o.Changes) != 0 {
+					changedObjects++
+				}
+				if o.Deleted {
+					deletedObjects++
+				}
+				if o.Orphan {
+					orphanObjects++
+				}
+			}
+			row[2] = fmt.Sprint(newObjects)
+			row[3] = fmt.Sprint(changedObjects)
+			row[4] = fmt.Sprint(deletedObjects)
+			row[5] = fmt.Sprint(orphanObjects)
+			errCount += len(ts.cr.Errors)
+			warnCount += len(ts.cr.Warnings)
+		}
+		if ts.vr != nil {
+			errCount += len(ts.vr.Errors)
+			warnCount += len(ts.vr.Warnings)
+		}
+		row[6] = fmt.Sprint(errCount)
+		row[7] = fmt.Sprint(warnCount)
+		if ts.err != nil {
+			row[1] = "failed"
+			failed++
+			status.Error(ctx, "Target %s failed: %s", ts.target, ts.err.Error())
+		}
+		t.AddRow(row...)
+	}
+
+	status.Flush(ctx)
+	_, _ = getStderr(ctx).WriteString("\nSummary:\n" + t.Render([]int{40, 10, 10, 10, 10, 10, 10, 10}))
+
+	if failed != 0 {
+		return fmt.Errorf("command failed for %d of %d targets", failed, len(summaries))
+	}
+	return nil
+}
@@ -49,6 +49,8 @@ type GlobalFlags struct {
 	NoUpdateCheck bool `group:"global" help:"Disable update check on startup"`
 	NoColor       bool `group:"global" help:"Disable colored output"`
 
+	StatusFormat string `group:"global" help:"Specify the format of status and progress output. Can be 'text' or 'jsonl'. 'jsonl' emits one JSON event per line, which is useful for CI systems." default:"text"`
+
 	CpuProfile string `group:"global" help:"Enable CPU profiling and write the result to the given path"`
 }
 
@@ -63,8 +65,12 @@ type cli struct {
 	ListImages  listImagesCmd  `cmd:"" help:"Renders the target and outputs all images used via 'images.get_image(...)"`
 	ListTargets listTargetsCmd `cmd:"" help:"Outputs a yaml list with all targets"`
 	PokeImages  pokeImagesCmd  `cmd:"" help:"Replace all images in target"`
+	Project     projectCmd     `cmd:"" help:"Project related sub-commands"`
 	Prune       pruneCmd       `cmd:"" help:"Searches the target cluster for prunable objects and deletes them"`
 	Render      renderCmd      `cmd:"" help:"Renders all resources and configuration files"`
+	Results     resultsCmd     `cmd:"" help:"Query the command result history"`
+	Rollback    rollbackCmd    `cmd:"" help:"Roll back a target to a previous command result"`
+	Rollout     rolloutCmd     `cmd:"" help:"Deploys multiple targets in waves, as defined by a rollout in .kluctl.yaml"`
 	Seal        sealCmd        `cmd:"" help:"Seal secrets based on target's sealingConfig"`
 	Validate    validateCmd    `cmd:"" help:"Validates the already deployed deployment"`
 	Controller  controllerCmd  `cmd:"" help:"Kluctl controller sub-commands"`
@@ -84,11 +90,13 @@ var flagGroups = []groupInfo{
 
 var origStderr = os.Stderr
 
-func initStatusHandler(ctx context.Context, debug bool, noColor bool) context.Context {
+func initStatusHandler(ctx context.Context, debug bool, noColor bool, statusFormat string) context.Context {
 	// we must determine isTerminal before we override os.Stderr
 	isTerminal := isatty.IsTerminal(origStderr.Fd())
 	var sh status.StatusHandler
-	if !debug && isatty.IsTerminal(origStderr.Fd()) {
+	if statusFormat == "jsonl" {
+		sh = status.NewJsonlStatusHandler(origStderr, isTerminal, false)
+	} else if !debug && isatty.IsTerminal(origStderr.Fd()) {
 		sh = status.NewMultiLineStatusHandler(ctx, origStderr, isTerminal, !noColor, false)
 	} else {
 		sh = status.NewSimpleStatusHandler(func(message string) {
@@ -225,7 +233,7 @@ func Main() {
 	colorable.EnableColorsStdout(nil)
 	ctx := context.Background()
 
-	ctx = initStatusHandler(ctx, false, true)
+	ctx = initStatusHandler(ctx, false, true, "text")
 	redirectLogsAndStderr(func() context.Context {
 		// ctx might be replaced later in preRun() of Execute()
 		return ctx
@@ -242,11 +250,14 @@ func Main() {
 		if err != nil {
 			return ctx, err
 		}
+		if flags.StatusFormat != "text" && flags.StatusFormat != "jsonl" {
+			return ctx, fmt.Errorf("invalid --status-format '%s', must be 'text' or 'jsonl'", flags.StatusFormat)
+		}
 		oldSh := status.FromContext(ctxIn)
 		if oldSh != nil {
 			oldSh.Stop()
 		}
-		ctx = initStatusHandler(ctxIn, flags.Debug, flags.NoColor)
+		ctx = initStatusHandler(ctxIn, flags.Debug, flags.NoColor, flags.StatusFormat)
 		if !flags.NoUpdateCheck {
 			if len(os.Args) < 2 || (os.Args[1] != "completion" && os.Args[1] != "__complete") {
 				checkNewVersion(ctx)
@@ -11,8 +11,11 @@ import (
 	ssh_pool "github.com/kluctl/kluctl/v2/pkg/git/ssh-pool"
 	"github.com/kluctl/kluctl/v2/pkg/kluctl_jinja2"
 	"github.com/kluctl/kluctl/v2/pkg/kluctl_project"
+	"github.com/kluctl/kluctl/v2/pkg/oci"
+	"github.com/kluctl/kluctl/v2/pkg/registries"
 	"github.com/kluctl/kluctl/v2/pkg/repocache"
 	"github.com/kluctl/kluctl/v2/pkg/results"
+	"github.com/kluctl/kluctl/v2/pkg/signature"
 	"github.com/kluctl/kluctl/v2/pkg/status"
 	"github.com/kluctl/kluctl/v2/pkg/types"
 	"github.com/kluctl/kluctl/v2/pkg/utils"
@@ -21,6 +24,9 @@ import (
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/client-go/tools/clientcmd/api"
 	"os"
+	"path/filepath"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"strconv"
 	"strings"
 )
 
@@ -37,16 +43,27 @@ func withKluctlProjectFromArgs(ctx context.Context, projectFlags args.ProjectFla
 	}
 	defer j2.Close()
 
-	projectDir, err := projectFlags.ProjectDir.GetProjectDir()
-	if err != nil {
-		return err
-	}
+	ctx, cancel := context.WithTimeout(ctx, projectFlags.Timeout)
+	defer cancel()
 
+	var projectDir string
 	var repoRoot string
-	if !internalDeploy {
-		repoRoot, err = git.DetectGitRepositoryRoot(projectDir)
+	if projectFlags.ProjectUrl != "" {
+		projectDir, err = pullProjectFromUrl(ctx, projectFlags.ProjectUrl, projectFlags.ProjectVerifyKey, tmpDir)
+		if err != nil {
+			return err
+		}
+		repoRoot = projectDir
+	} else {
+		projectDir, err = projectFlags.ProjectDir.GetProjectDir()
 		if err != nil {
-			status.Warning(ctx, "Failed to detect git project root. This might cause follow-up errors")
+			return err
+		}
+		if !internalDeploy {
+			repoRoot, err = git.DetectGitRepositoryRoot(projectDir)
+			if err != nil {
+				status.Warning(ctx, "Failed to detect git project root. This might cause follow-up errors")
+			}
 		}
 	}
 
@@ -54,9 +71,6 @@ func withKluctlProjectFromArgs(ctx context.Context, projectFlags args.ProjectFla
 		repoRoot = projectDir
 	}
 
-	ctx, cancel := context.WithTimeout(ctx, projectFlags.Timeout)
-	defer cancel()
-
 	sshPool := &ssh_pool.SshPool{}
 
 	var repoOverrides []repocache.RepoOverride
@@ -122,6 +136,47 @@ func withKluctlProjectFromArgs(ctx context.Context, projectFlags args.ProjectFla
 	return cb(ctx, p)
 }
 
+func pullProjectFromUrl(ctx context.Context, projectUrl string, verifyKeyFiles []string, tmpDir string) (string, error) {
+	rh := registries.NewRegistryHelper(ctx)
+	err := rh.ParseAuthEntriesFromEnv()
+	if err != nil {
+		return "", fmt.Errorf("failed to parse registry auth from environment: %w", err)
+	}
+
+	var keys *signature.PublicKeys
+	if len(verifyKeyFiles) != 0 {
+		var keysData [][]byte
+		for _, f := range verifyKeyFiles {
+			b, err := os.ReadFile(f)
+			if err != nil {
+				return "", err
+			}
+			keysData = append(keysData, b)
+		}
+		keys, err = signature.ParsePublicKeys(keysData)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	projectDir := filepath.Join(tmpDir, "oci-project")
+	err = os.MkdirAll(projectDir, 0o700)
+	if err != nil {
+		return "", err
+	}
+
+	s := status.Start(ctx, "Pulling project from %s", projectUrl)
+	defer s.Failed()
+
+	digest, err := oci.PullProject(rh, projectUrl, projectDir, keys)
+	if err != nil {
+		return "", err
+	}
+	s.UpdateAndInfoFallback("Pulled project from %s (%s)", projectUrl, digest)
+	s.Success()
+	return projectDir, nil
+}
+
 type projectTargetCommandArgs struct {
 	projectFlags         args.ProjectFlags
 	targetFlags          args.TargetFlags
@@ -132,6 +187,7 @@ type projectTargetCommandArgs struct {
 	dryRunArgs           *args.DryRunFlags
 	renderOutputDirFlags args.RenderOutputDirFlags
 	commandResultFlags   *args.CommandResultFlags
+	k8sClientFlags       *args.K8sClientFlags
 
 	internalDeploy    bool
 	forSeal           bool
@@ -192,6 +248,10 @@ func withProjectTargetCommandContext(ctx context.Context, args projectTargetComm
 		HelmCredentials:    &args.helmCredentials,
 		RenderOutputDir:    renderOutputDir,
 	}
+	if args.k8sClientFlags != nil {
+		targetParams.K8sQPS = args.k8sClientFlags.KubeQps
+		targetParams.K8sBurst = args.k8sClientFlags.KubeBurst
+	}
 
 	targetCtx, err := p.NewTargetContext(ctx, targetParams)
 	if err != nil {
@@ -207,12 +267,7 @@ func withProjectTargetCommandContext(ctx context.Context, args projectTargetComm
 
 	var resultStore results.ResultStore
 	if args.commandResultFlags != nil && args.commandResultFlags.WriteCommandResult {
-		client, err := targetCtx.SharedContext.K.ToClient()
-		if err != nil {
-			return err
-		}
-
-		resultStore, err = results.NewResultStoreSecrets(ctx, client, args.commandResultFlags.CommandResultNamespace, args.commandResultFlags.KeepCommandResultsCount)
+		resultStore, err = buildResultStore(ctx, targetCtx.SharedContext.K.ToClient, args.commandResultFlags)
 		if err != nil {
 			return err
 		}
@@ -228,6 +283,138 @@ func withProjectTargetCommandContext(ctx context.Context, args projectTargetComm
 	return cb(cmdCtx)
 }
 
+func buildRetentionPolicy(flags *args.CommandResultRetentionFlags) (results.RetentionPolicy, error) {
+	policy := results.RetentionPolicy{
+		KeepCount:                flags.KeepCommandResultsCount,
+		MaxAge:                   flags.KeepCommandResultsMaxAge,
+		KeepLastSuccessfulDeploy: flags.KeepLastSuccessfulDeployResult,
+		KeepFailedCount:          flags.KeepFailedCommandResultsCount,
+	}
+	for _, x := range flags.KeepCommandResultsCountPerCommand {
+		s := strings.SplitN(x, "=", 2)
+		if len(s) != 2 {
+			return policy, fmt.Errorf("invalid --keep-command-results-count-per-command value %s, must be in the form command=count", x)
+		}
+		cnt, err := strconv.Atoi(s[1])
+		if err != nil {
+			return policy, fmt.Errorf("invalid count in --keep-command-results-count-per-command value %s: %w", x, err)
+		}
+		if policy.KeepCountPerCommand == nil {
+			policy.KeepCountPerCommand = map[string]int{}
+		}
+		policy.KeepCountPerCommand[s[0]] = cnt
+	}
+	return policy, nil
+}
+
+func buildResultStore(ctx context.Context, getClient func() (client.WithWatch, error), flags *args.CommandResultFlags) (results.ResultStore, error) {
+	retention, err := buildRetentionPolicy(&flags.CommandResultRetentionFlags)
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.HasPrefix(flags.CommandResultStore, "file://") {
+		dir := strings.TrimPrefix(flags.CommandResultStore, "file://")
+		if dir == "" {
+			dir, err = results.DefaultResultStoreFileDir()
+			if err != nil {
+				return nil, err
+			}
+		}
+		return results.NewResultStoreFile(ctx, dir, retention)
+	} else if strings.HasPrefix(flags.CommandResultStore, "s3://") {
+		return results.NewResultStoreS3FromUrl(ctx, flags.CommandResultStore, retention)
+	} else if flags.CommandResultStore != "" {
+		return nil, fmt.Errorf("unsupported command result store %s", flags.CommandResultStore)
+	}
+
+	c, err := getClient()
+	if err != nil {
+		return nil, err
+	}
+	return results.NewResultStoreSecrets(ctx, c, flags.CommandResultNamespace, retention)
+}
+
+func createResultStores(ctx context.Context, flags *args.CommandResultReadOnlyFlags, inCluster bool) ([]results.ResultStore, []*rest.Config, error) {
+	var stores []results.ResultStore
+	var configs []*rest.Config
+
+	for _, rs := range flags.CommandResultStore {
+		if !strings.HasPrefix(rs, "file://") && !strings.HasPrefix(rs, "s3://") {
+			return nil, nil, fmt.Errorf("unsupported command result store %s", rs)
+		}
+		store, err := buildResultStore(ctx, nil, &args.CommandResultFlags{CommandResultStore: rs})
+		if err != nil {
+			return nil, nil, err
+		}
+		stores = append(stores, store)
+	}
+	if len(flags.CommandResultStore) != 0 && !flags.AllContexts && !inCluster && len(flags.Context) == 0 {
+		return stores, configs, nil
+	}
+
+	r := clientcmd.NewDefaultClientConfigLoadingRules()
+
+	kcfg, err := r.Load()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var contexts []string
+	if flags.AllContexts {
+		for name, _ := range kcfg.Contexts {
+			contexts = append(contexts, name)
+		}
+	} else if inCluster {
+		// placeholder for current context
+		contexts = append(contexts, "")
+	} else {
+		if len(flags.Context) == 0 {
+			// placeholder for current context
+			contexts = append(contexts, "")
+		}
+		for _, c := range flags.Context {
+			found := false
+			for name, _ := range kcfg.Contexts {
+				if c == name {
+					contexts = append(contexts, name)
+					found = true
+					break
+				}
+			}
+			if !found {
+				return nil, nil, fmt.Errorf("context '%s' not found in kubeconfig", c)
+			}
+		}
+	}
+
+	for _, c := range contexts {
+		configOverrides := &clientcmd.ConfigOverrides{
+			CurrentContext: c,
+		}
+		config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+			r,
+			configOverrides).ClientConfig()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		client, err := client.NewWithWatch(config, client.Options{})
+		if err != nil {
+			return nil, nil, err
+		}
+
+		store, err := results.NewResultStoreSecrets(ctx, client, "", results.RetentionPolicy{})
+		if err != nil {
+			return nil, nil, err
+		}
+		stores = append(stores, store)
+		configs = append(configs, config)
+	}
+
+	return stores, configs, nil
+}
+
 func clientConfigGetter(forCompletion bool) func(context *string) (*rest.Config, *api.Config, error) {
 	return func(context *string) (*rest.Config, *api.Config, error) {
 		if forCompletion {
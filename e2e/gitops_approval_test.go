@@ -0,0 +1,141 @@
+package e2e
+
+import (
+	"context"
+	kluctlv1 "github.com/kluctl/kluctl/v2/api/v1beta1"
+	test_utils "github.com/kluctl/kluctl/v2/e2e/test-utils"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	corev1 "k8s.io/api/core/v1"
+	meta2 "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func (suite *GitopsTestSuite) TestKluctlDeploymentReconciler_Approval() {
+	g := NewWithT(suite.T())
+
+	p := test_utils.NewTestProject(suite.T())
+	createNamespace(suite.T(), suite.k, p.TestSlug())
+
+	p.UpdateTarget("target1", nil)
+	p.AddKustomizeDeployment("d1", []test_utils.KustomizeResource{
+		{Name: "cm1.yaml", Content: uo.FromStringMust(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm1
+  namespace: "{{ args.namespace }}"
+data:
+  k1: v1
+`)},
+	}, nil)
+
+	key := suite.createKluctlDeployment(p, "target1", map[string]any{
+		"namespace": p.TestSlug(),
+	})
+	suite.waitForCommit(key, getHeadRevision(suite.T(), p))
+
+	suite.updateKluctlDeployment(key, func(kd *kluctlv1.KluctlDeployment) {
+		kd.Spec.Approval = true
+	})
+
+	getKd := func() *kluctlv1.KluctlDeployment {
+		var kd kluctlv1.KluctlDeployment
+		err := suite.k.Client.Get(context.TODO(), key, &kd)
+		g.Expect(err).To(Succeed())
+		return &kd
+	}
+	getCmValue := func() string {
+		cm := &corev1.ConfigMap{}
+		err := suite.k.Client.Get(context.TODO(), client.ObjectKey{
+			Name:      "cm1",
+			Namespace: p.TestSlug(),
+		}, cm)
+		g.Expect(err).To(Succeed())
+		return cm.Data["k1"]
+	}
+
+	p.UpdateYaml("d1/cm1.yaml", func(o *uo.UnstructuredObject) error {
+		_ = o.SetNestedField("v2", "data", "k1")
+		return nil
+	}, "")
+
+	var resultId string
+	suite.Run("diff is waiting for approval", func() {
+		suite.waitForCommit(key, getHeadRevision(suite.T(), p))
+
+		kd := getKd()
+		g.Expect(kd.Status.PendingApproval).ToNot(BeNil())
+		g.Expect(kd.Status.PendingApproval.Commit).To(Equal(getHeadRevision(suite.T(), p)))
+		resultId = kd.Status.PendingApproval.ResultId
+
+		c := meta2.FindStatusCondition(kd.Status.Conditions, kluctlv1.AwaitingApprovalCondition)
+		g.Expect(c).ToNot(BeNil())
+		g.Expect(c.Status).To(Equal(metav1.ConditionTrue))
+		g.Expect(c.Reason).To(Equal(kluctlv1.ApprovalPendingReason))
+
+		readiness := suite.getReadiness(kd)
+		g.Expect(readiness).ToNot(BeNil())
+		g.Expect(readiness.Reason).To(Equal(kluctlv1.ApprovalPendingReason))
+
+		g.Expect(getCmValue()).To(Equal("v1"))
+	})
+
+	suite.Run("wrong approval is ignored", func() {
+		suite.updateKluctlDeployment(key, func(kd *kluctlv1.KluctlDeployment) {
+			metav1.SetMetaDataAnnotation(&kd.ObjectMeta, kluctlv1.KluctlApproveDeployAnnotation, "invalid")
+		})
+		suite.waitForReconcile(key)
+
+		kd := getKd()
+		g.Expect(kd.Status.PendingApproval).ToNot(BeNil())
+		g.Expect(kd.Status.PendingApproval.ResultId).To(Equal(resultId))
+		g.Expect(getCmValue()).To(Equal("v1"))
+	})
+
+	suite.Run("approval deploys the diff", func() {
+		suite.updateKluctlDeployment(key, func(kd *kluctlv1.KluctlDeployment) {
+			metav1.SetMetaDataAnnotation(&kd.ObjectMeta, kluctlv1.KluctlApproveDeployAnnotation, resultId)
+		})
+
+		g.Eventually(func() bool {
+			return getKd().Status.PendingApproval == nil
+		}, timeout, time.Second).Should(BeTrue())
+
+		kd := getKd()
+		c := meta2.FindStatusCondition(kd.Status.Conditions, kluctlv1.AwaitingApprovalCondition)
+		g.Expect(c).ToNot(BeNil())
+		g.Expect(c.Status).To(Equal(metav1.ConditionFalse))
+		g.Expect(c.Reason).To(Equal(kluctlv1.ApprovedReason))
+
+		g.Expect(getCmValue()).To(Equal("v2"))
+	})
+
+	suite.Run("new commit expires pending approval", func() {
+		p.UpdateYaml("d1/cm1.yaml", func(o *uo.UnstructuredObject) error {
+			_ = o.SetNestedField("v3", "data", "k1")
+			return nil
+		}, "")
+		suite.waitForCommit(key, getHeadRevision(suite.T(), p))
+		oldResultId := getKd().Status.PendingApproval.ResultId
+
+		p.UpdateYaml("d1/cm1.yaml", func(o *uo.UnstructuredObject) error {
+			_ = o.SetNestedField("v4", "data", "k1")
+			return nil
+		}, "")
+		suite.waitForCommit(key, getHeadRevision(suite.T(), p))
+
+		kd := getKd()
+		g.Expect(kd.Status.PendingApproval).ToNot(BeNil())
+		g.Expect(kd.Status.PendingApproval.ResultId).ToNot(Equal(oldResultId))
+
+		// approving the expired diff has no effect
+		suite.updateKluctlDeployment(key, func(kd *kluctlv1.KluctlDeployment) {
+			metav1.SetMetaDataAnnotation(&kd.ObjectMeta, kluctlv1.KluctlApproveDeployAnnotation, oldResultId)
+		})
+		suite.waitForReconcile(key)
+		g.Expect(getCmValue()).To(Equal("v2"))
+	})
+}
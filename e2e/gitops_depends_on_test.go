@@ -0,0 +1,97 @@
+package e2e
+
+import (
+	"context"
+	kluctlv1 "github.com/kluctl/kluctl/v2/api/v1beta1"
+	test_utils "github.com/kluctl/kluctl/v2/e2e/test-utils"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func (suite *GitopsTestSuite) TestKluctlDeploymentReconciler_DependsOn() {
+	g := NewWithT(suite.T())
+
+	createProject := func() *test_utils.TestProject {
+		p := test_utils.NewTestProject(suite.T())
+		createNamespace(suite.T(), suite.k, p.TestSlug())
+
+		p.UpdateTarget("target1", nil)
+		p.AddKustomizeDeployment("d1", []test_utils.KustomizeResource{
+			{Name: "cm1.yaml", Content: uo.FromStringMust(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm1
+  namespace: "{{ args.namespace }}"
+data:
+  k1: v1
+`)},
+		}, nil)
+		return p
+	}
+
+	getReadiness := func(key client.ObjectKey) (*kluctlv1.KluctlDeployment, *metav1.Condition) {
+		var kd kluctlv1.KluctlDeployment
+		err := suite.k.Client.Get(context.TODO(), key, &kd)
+		g.Expect(err).To(Succeed())
+		return &kd, suite.getReadiness(&kd)
+	}
+
+	pDep := createProject()
+	p := createProject()
+
+	key := suite.createKluctlDeployment(p, "target1", map[string]any{
+		"namespace": p.TestSlug(),
+	})
+	suite.updateKluctlDeployment(key, func(kd *kluctlv1.KluctlDeployment) {
+		kd.Spec.DependsOn = []kluctlv1.DependencyReference{
+			{Name: pDep.TestSlug(), Namespace: pDep.TestSlug() + "-gitops"},
+		}
+	})
+
+	suite.Run("missing dependency", func() {
+		g.Eventually(func() string {
+			kd, c := getReadiness(key)
+			if c == nil || c.ObservedGeneration != kd.Generation {
+				return ""
+			}
+			return c.Reason
+		}, timeout, time.Second).Should(Equal(kluctlv1.DependencyNotReadyReason))
+	})
+
+	suite.Run("dependency becomes ready", func() {
+		keyDep := suite.createKluctlDeployment(pDep, "target1", map[string]any{
+			"namespace": pDep.TestSlug(),
+		})
+		suite.waitForReconcile(keyDep)
+
+		g.Eventually(func() string {
+			_, c := getReadiness(key)
+			if c == nil || c.Status != metav1.ConditionTrue {
+				return ""
+			}
+			return c.Reason
+		}, timeout, time.Second).Should(Equal(kluctlv1.ReconciliationSucceededReason))
+
+		assertConfigMapExists(suite.T(), suite.k, p.TestSlug(), "cm1")
+
+		suite.Run("dependency cycle", func() {
+			suite.updateKluctlDeployment(keyDep, func(kd *kluctlv1.KluctlDeployment) {
+				kd.Spec.DependsOn = []kluctlv1.DependencyReference{
+					{Name: key.Name, Namespace: key.Namespace},
+				}
+			})
+
+			g.Eventually(func() string {
+				kd, c := getReadiness(keyDep)
+				if c == nil || c.ObservedGeneration != kd.Generation || c.Reason != kluctlv1.DependencyNotReadyReason {
+					return ""
+				}
+				return c.Message
+			}, timeout, time.Second).Should(ContainSubstring("dependency cycle detected"))
+		})
+	})
+}
@@ -0,0 +1,99 @@
+package e2e
+
+import (
+	"context"
+	kluctlv1 "github.com/kluctl/kluctl/v2/api/v1beta1"
+	test_utils "github.com/kluctl/kluctl/v2/e2e/test-utils"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func (suite *GitopsTestSuite) TestKluctlDeploymentReconciler_DeployWindows() {
+	g := NewWithT(suite.T())
+
+	p := test_utils.NewTestProject(suite.T())
+	createNamespace(suite.T(), suite.k, p.TestSlug())
+
+	p.UpdateTarget("target1", nil)
+	p.AddKustomizeDeployment("d1", []test_utils.KustomizeResource{
+		{Name: "cm1.yaml", Content: uo.FromStringMust(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm1
+  namespace: "{{ args.namespace }}"
+data:
+  k1: v1
+`)},
+	}, nil)
+
+	key := suite.createKluctlDeployment(p, "target1", map[string]any{
+		"namespace": p.TestSlug(),
+	})
+	suite.waitForCommit(key, getHeadRevision(suite.T(), p))
+
+	getKd := func() *kluctlv1.KluctlDeployment {
+		var kd kluctlv1.KluctlDeployment
+		err := suite.k.Client.Get(context.TODO(), key, &kd)
+		g.Expect(err).To(Succeed())
+		return &kd
+	}
+	getCmValue := func() string {
+		cm := &corev1.ConfigMap{}
+		err := suite.k.Client.Get(context.TODO(), client.ObjectKey{
+			Name:      "cm1",
+			Namespace: p.TestSlug(),
+		}, cm)
+		g.Expect(err).To(Succeed())
+		return cm.Data["k1"]
+	}
+
+	// deny deployments at all times
+	suite.updateKluctlDeployment(key, func(kd *kluctlv1.KluctlDeployment) {
+		kd.Spec.DeployWindows = &kluctlv1.DeployWindows{
+			Deny: []kluctlv1.DeployWindow{
+				{Schedule: "* * * * *", Duration: metav1.Duration{Duration: time.Hour}},
+			},
+		}
+	})
+
+	p.UpdateYaml("d1/cm1.yaml", func(o *uo.UnstructuredObject) error {
+		_ = o.SetNestedField("v2", "data", "k1")
+		return nil
+	}, "")
+
+	suite.Run("deployment is deferred", func() {
+		suite.waitForCommit(key, getHeadRevision(suite.T(), p))
+
+		kd := getKd()
+		g.Expect(kd.Status.DeployDeferred).To(BeTrue())
+
+		readiness := suite.getReadiness(kd)
+		g.Expect(readiness).ToNot(BeNil())
+		g.Expect(readiness.Status).To(Equal(metav1.ConditionFalse))
+		g.Expect(readiness.Reason).To(Equal(kluctlv1.OutsideDeployWindowReason))
+		g.Expect(readiness.Message).To(ContainSubstring("1 changed objects"))
+
+		g.Expect(getCmValue()).To(Equal("v1"))
+	})
+
+	suite.Run("deferred deployment is performed when allowed", func() {
+		suite.updateKluctlDeployment(key, func(kd *kluctlv1.KluctlDeployment) {
+			kd.Spec.DeployWindows = nil
+		})
+
+		g.Eventually(func() bool {
+			return getCmValue() == "v2"
+		}, timeout, time.Second).Should(BeTrue())
+
+		g.Eventually(func() bool {
+			kd := getKd()
+			readiness := suite.getReadiness(kd)
+			return !kd.Status.DeployDeferred && readiness != nil && readiness.Status == metav1.ConditionTrue
+		}, timeout, time.Second).Should(BeTrue())
+	})
+}
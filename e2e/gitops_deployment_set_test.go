@@ -0,0 +1,169 @@
+package e2e
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/go-git/go-git/v5/plumbing"
+	kluctlv1 "github.com/kluctl/kluctl/v2/api/v1beta1"
+	test_utils "github.com/kluctl/kluctl/v2/e2e/test-utils"
+	types2 "github.com/kluctl/kluctl/v2/pkg/types"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"math/rand"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sort"
+	"strconv"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func (suite *GitopsTestSuite) TestKluctlDeploymentSetReconciler_GitBranches() {
+	g := NewWithT(suite.T())
+
+	p := test_utils.NewTestProject(suite.T())
+	createNamespace(suite.T(), suite.k, p.TestSlug())
+	gitopsNs := p.TestSlug() + "-gitops"
+	createNamespace(suite.T(), suite.k, gitopsNs)
+
+	p.UpdateTarget("target1", nil)
+	p.AddKustomizeDeployment("d1", []test_utils.KustomizeResource{
+		{Name: "cm1.yaml", Content: uo.FromStringMust(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: "cm-{{ args.name }}"
+  namespace: "{{ args.namespace }}"
+data:
+  k1: v1
+`)},
+	}, nil)
+
+	repo := p.GetGitRepo()
+	head, err := repo.Head()
+	g.Expect(err).To(Succeed())
+	for _, b := range []string{"preview/a", "preview/b", "other"} {
+		err = repo.Storer.SetReference(plumbing.NewHashReference(plumbing.NewBranchReferenceName(b), head.Hash()))
+		g.Expect(err).To(Succeed())
+	}
+
+	templateSpec, err := json.Marshal(map[string]any{
+		"interval": interval.String(),
+		"timeout":  timeout.String(),
+		"target":   "target1",
+		"delete":   true,
+		"source": map[string]any{
+			"url": p.GitUrl(),
+			"ref": map[string]any{
+				"branch": "{{ git.branch }}",
+			},
+		},
+		"args": map[string]any{
+			"namespace": p.TestSlug(),
+			"name":      "{{ git.branchSlug }}",
+		},
+	})
+	g.Expect(err).To(Succeed())
+
+	set := &kluctlv1.KluctlDeploymentSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "previews",
+			Namespace: gitopsNs,
+		},
+		Spec: kluctlv1.KluctlDeploymentSetSpec{
+			Interval: metav1.Duration{Duration: interval},
+			Generators: []kluctlv1.KluctlDeploymentSetGenerator{
+				{Git: &kluctlv1.GitGenerator{
+					URL:      *types2.ParseGitUrlMust(p.GitUrl()),
+					Branches: &kluctlv1.GitBranchesGenerator{Pattern: "preview/.*"},
+				}},
+			},
+			Template: kluctlv1.KluctlDeploymentTemplate{
+				Metadata: kluctlv1.KluctlDeploymentTemplateMetadata{
+					Name: "{{ git.branchSlug }}",
+				},
+				Spec: runtime.RawExtension{Raw: templateSpec},
+			},
+		},
+	}
+	err = suite.k.Client.Create(context.Background(), set)
+	g.Expect(err).To(Succeed())
+
+	// the envtest cluster has no garbage collector, so we must delete the set and the generated deployments manually
+	defer func() {
+		_ = suite.k.Client.Delete(context.Background(), set)
+	}()
+	for _, n := range []string{"preview-a", "preview-b"} {
+		suite.deployments = append(suite.deployments, client.ObjectKey{Name: n, Namespace: gitopsNs})
+	}
+
+	getGenerated := func() []string {
+		var s kluctlv1.KluctlDeploymentSet
+		err := suite.k.Client.Get(context.Background(), client.ObjectKeyFromObject(set), &s)
+		g.Expect(err).To(Succeed())
+		if s.Status.ObservedGeneration != s.Generation {
+			return nil
+		}
+		ret := append([]string{}, s.Status.Deployments...)
+		sort.Strings(ret)
+		return ret
+	}
+
+	suite.Run("deployments are generated", func() {
+		g.Eventually(getGenerated, timeout, time.Second).Should(Equal([]string{"preview-a", "preview-b"}))
+
+		var kd kluctlv1.KluctlDeployment
+		err := suite.k.Client.Get(context.Background(), client.ObjectKey{Name: "preview-a", Namespace: gitopsNs}, &kd)
+		g.Expect(err).To(Succeed())
+		g.Expect(kd.Spec.Source.Ref.Branch).To(Equal("preview/a"))
+		g.Expect(kd.GetLabels()[kluctlv1.KluctlDeploymentSetLabel]).To(Equal("previews"))
+		g.Expect(metav1.IsControlledBy(&kd, set)).To(BeTrue())
+
+		g.Eventually(func() bool {
+			for _, n := range []string{"cm-preview-a", "cm-preview-b"} {
+				if _, err := suite.k.Get(corev1.SchemeGroupVersion.WithResource("configmaps"), p.TestSlug(), n); err != nil {
+					return false
+				}
+			}
+			return true
+		}, timeout, time.Second).Should(BeTrue())
+	})
+
+	suite.Run("deployment is pruned when the branch disappears", func() {
+		err := repo.Storer.RemoveReference(plumbing.NewBranchReferenceName("preview/b"))
+		g.Expect(err).To(Succeed())
+
+		suite.triggerSetReconcile(client.ObjectKeyFromObject(set))
+
+		g.Eventually(getGenerated, timeout, time.Second).Should(Equal([]string{"preview-a"}))
+		g.Eventually(func() bool {
+			var kd kluctlv1.KluctlDeployment
+			err := suite.k.Client.Get(context.Background(), client.ObjectKey{Name: "preview-b", Namespace: gitopsNs}, &kd)
+			return errors.IsNotFound(err)
+		}, timeout, time.Second).Should(BeTrue())
+
+		// delete: true in the template causes the deployed objects to be deleted as well
+		assertConfigMapNotExists(suite.T(), suite.k, p.TestSlug(), "cm-preview-b")
+		assertConfigMapExists(suite.T(), suite.k, p.TestSlug(), "cm-preview-a")
+	})
+}
+
+func (suite *GitopsTestSuite) triggerSetReconcile(key client.ObjectKey) {
+	g := NewWithT(suite.T())
+
+	var s kluctlv1.KluctlDeploymentSet
+	err := suite.k.Client.Get(context.Background(), key, &s)
+	g.Expect(err).To(Succeed())
+
+	patch := client.MergeFrom(s.DeepCopy())
+	a := s.GetAnnotations()
+	if a == nil {
+		a = map[string]string{}
+	}
+	a[kluctlv1.KluctlRequestReconcileAnnotation] = strconv.FormatInt(rand.Int63(), 10)
+	s.SetAnnotations(a)
+	err = suite.k.Client.Patch(context.Background(), &s, patch)
+	g.Expect(err).To(Succeed())
+}
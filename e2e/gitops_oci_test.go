@@ -0,0 +1,106 @@
+package e2e
+
+import (
+	"context"
+	"github.com/google/go-containerregistry/pkg/registry"
+	kluctlv1 "github.com/kluctl/kluctl/v2/api/v1beta1"
+	test_utils "github.com/kluctl/kluctl/v2/e2e/test-utils"
+	"github.com/kluctl/kluctl/v2/pkg/oci"
+	"github.com/kluctl/kluctl/v2/pkg/registries"
+	types2 "github.com/kluctl/kluctl/v2/pkg/types"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	corev1 "k8s.io/api/core/v1"
+	"net/http/httptest"
+	"net/url"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	. "github.com/onsi/gomega"
+)
+
+func (suite *GitopsTestSuite) TestKluctlDeploymentReconciler_OciSource() {
+	g := NewWithT(suite.T())
+
+	s := httptest.NewServer(registry.New())
+	defer s.Close()
+	u, err := url.Parse(s.URL)
+	g.Expect(err).To(Succeed())
+
+	rh := registries.NewRegistryHelper(context.TODO())
+	rh.AddAuthEntry(registries.AuthEntry{
+		Registry: u.Host,
+		Insecure: true,
+	})
+
+	p := test_utils.NewTestProject(suite.T())
+	createNamespace(suite.T(), suite.k, p.TestSlug())
+
+	p.UpdateTarget("target1", nil)
+	p.AddKustomizeDeployment("d1", []test_utils.KustomizeResource{
+		{Name: "cm1.yaml", Content: uo.FromStringMust(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm1
+  namespace: "{{ args.namespace }}"
+data:
+  k1: v1
+`)},
+	}, nil)
+
+	ociUrl := "oci://" + u.Host + "/" + p.TestSlug()
+	digest, err := oci.PushProject(rh, oci.BuildOciUrl(ociUrl, "v1", ""), p.LocalProjectDir(), nil, nil)
+	g.Expect(err).To(Succeed())
+
+	secret := &corev1.Secret{}
+	secret.Name = "oci-secret"
+
+	key := suite.createKluctlDeployment(p, "target1", map[string]any{
+		"namespace": p.TestSlug(),
+	})
+
+	secret.Namespace = key.Namespace
+	secret.StringData = map[string]string{
+		"insecure": "true",
+	}
+	err = suite.k.Client.Create(context.TODO(), secret)
+	g.Expect(err).To(Succeed())
+
+	suite.updateKluctlDeployment(key, func(kd *kluctlv1.KluctlDeployment) {
+		kd.Spec.Source.URL = types2.GitUrl{}
+		kd.Spec.Source.Oci = &kluctlv1.ProjectSourceOci{
+			URL: ociUrl,
+			Tag: "v1",
+			SecretRef: &kluctlv1.LocalObjectReference{
+				Name: secret.Name,
+			},
+		}
+	})
+
+	suite.waitForCommit(key, digest)
+
+	cm := &corev1.ConfigMap{}
+	err = suite.k.Client.Get(context.TODO(), client.ObjectKey{
+		Name:      "cm1",
+		Namespace: p.TestSlug(),
+	}, cm)
+	g.Expect(err).To(Succeed())
+	g.Expect(cm.Data).To(HaveKeyWithValue("k1", "v1"))
+
+	suite.Run("pull by digest", func() {
+		p.UpdateYaml("d1/cm1.yaml", func(o *uo.UnstructuredObject) error {
+			_ = o.SetNestedField("v2", "data", "k1")
+			return nil
+		}, "")
+		digest2, err := oci.PushProject(rh, oci.BuildOciUrl(ociUrl, "v2", ""), p.LocalProjectDir(), nil, nil)
+		g.Expect(err).To(Succeed())
+		g.Expect(digest2).ToNot(Equal(digest))
+
+		suite.updateKluctlDeployment(key, func(kd *kluctlv1.KluctlDeployment) {
+			kd.Spec.Source.Oci.Digest = digest2
+		})
+		suite.waitForCommit(key, digest2)
+
+		err = suite.k.Client.Get(context.TODO(), client.ObjectKeyFromObject(cm), cm)
+		g.Expect(err).To(Succeed())
+		g.Expect(cm.Data).To(HaveKeyWithValue("k1", "v2"))
+	})
+}
@@ -7,11 +7,13 @@ import (
 	kluctlv1 "github.com/kluctl/kluctl/v2/api/v1beta1"
 	test_utils "github.com/kluctl/kluctl/v2/e2e/test-utils"
 	types2 "github.com/kluctl/kluctl/v2/pkg/types"
+	"github.com/kluctl/kluctl/v2/pkg/types/result"
 	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
 	"github.com/onsi/gomega"
 	"github.com/stretchr/testify/suite"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	meta2 "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"math/rand"
@@ -604,6 +606,99 @@ data:
 	})
 }
 
+func (suite *GitopsTestSuite) TestKluctlDeploymentReconciler_DriftDetection() {
+	g := NewWithT(suite.T())
+
+	p := test_utils.NewTestProject(suite.T())
+	createNamespace(suite.T(), suite.k, p.TestSlug())
+
+	p.UpdateTarget("target1", nil)
+
+	p.AddKustomizeDeployment("d1", []test_utils.KustomizeResource{
+		{Name: "cm1.yaml", Content: uo.FromStringMust(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm1
+  namespace: "{{ args.namespace }}"
+data:
+  k1: v1
+`)},
+	}, nil)
+
+	key := suite.createKluctlDeployment(p, "target1", map[string]any{
+		"namespace": p.TestSlug(),
+	})
+
+	suite.updateKluctlDeployment(key, func(kd *kluctlv1.KluctlDeployment) {
+		kd.Spec.DriftDetection = &kluctlv1.DriftDetection{
+			Interval: metav1.Duration{Duration: interval},
+		}
+	})
+
+	suite.waitForCommit(key, getHeadRevision(suite.T(), p))
+
+	getDriftedCondition := func() (*metav1.Condition, *result.DriftDetectionResult) {
+		var kd kluctlv1.KluctlDeployment
+		err := suite.k.Client.Get(context.TODO(), key, &kd)
+		g.Expect(err).To(Succeed())
+		dr, err := kd.Status.GetLastDriftDetectionResult()
+		g.Expect(err).To(Succeed())
+		return meta2.FindStatusCondition(kd.Status.Conditions, kluctlv1.DriftedCondition), dr
+	}
+
+	suite.Run("no drift detected", func() {
+		g.Eventually(func() bool {
+			c, _ := getDriftedCondition()
+			return c != nil && c.Reason == kluctlv1.NoDriftReason
+		}, timeout, time.Second).Should(BeTrue())
+	})
+
+	cm := &corev1.ConfigMap{}
+	err := suite.k.Client.Get(context.TODO(), client.ObjectKey{
+		Name:      "cm1",
+		Namespace: p.TestSlug(),
+	}, cm)
+	g.Expect(err).To(Succeed())
+
+	cm.Data["k1"] = "v2"
+	err = suite.k.Client.Update(context.TODO(), cm, client.FieldOwner("kubectl"))
+	g.Expect(err).To(Succeed())
+
+	suite.Run("drift detected", func() {
+		g.Eventually(func() bool {
+			c, _ := getDriftedCondition()
+			return c != nil && c.Status == metav1.ConditionTrue
+		}, timeout, time.Second).Should(BeTrue())
+
+		c, dr := getDriftedCondition()
+		g.Expect(c.Reason).To(Equal(kluctlv1.DriftDetectedReason))
+		g.Expect(dr.Objects).To(HaveLen(1))
+		g.Expect(dr.Objects[0].Ref.Name).To(Equal("cm1"))
+
+		// drift is not corrected without autoCorrect
+		err := suite.k.Client.Get(context.TODO(), client.ObjectKeyFromObject(cm), cm)
+		g.Expect(err).To(Succeed())
+		g.Expect(cm.Data).To(HaveKeyWithValue("k1", "v2"))
+	})
+
+	suite.updateKluctlDeployment(key, func(kd *kluctlv1.KluctlDeployment) {
+		kd.Spec.DriftDetection.AutoCorrect = true
+	})
+
+	suite.Run("drift is corrected", func() {
+		g.Eventually(func() bool {
+			err := suite.k.Client.Get(context.TODO(), client.ObjectKeyFromObject(cm), cm)
+			g.Expect(err).To(Succeed())
+			return cm.Data["k1"] == "v1"
+		}, timeout, time.Second).Should(BeTrue())
+
+		g.Eventually(func() bool {
+			c, _ := getDriftedCondition()
+			return c != nil && c.Status == metav1.ConditionFalse
+		}, timeout, time.Second).Should(BeTrue())
+	})
+}
+
 func (suite *GitopsTestSuite) doTestDelete(delete bool) {
 	g := NewWithT(suite.T())
 
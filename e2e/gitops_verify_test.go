@@ -0,0 +1,96 @@
+package e2e
+
+import (
+	"bytes"
+	"context"
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	kluctlv1 "github.com/kluctl/kluctl/v2/api/v1beta1"
+	test_utils "github.com/kluctl/kluctl/v2/e2e/test-utils"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	corev1 "k8s.io/api/core/v1"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func (suite *GitopsTestSuite) TestKluctlDeploymentReconciler_SourceVerification() {
+	g := NewWithT(suite.T())
+
+	e, err := openpgp.NewEntity("Test User", "", "no@mail.com", nil)
+	g.Expect(err).To(Succeed())
+	pubKey := bytes.NewBuffer(nil)
+	w, err := armor.Encode(pubKey, openpgp.PublicKeyType, nil)
+	g.Expect(err).To(Succeed())
+	g.Expect(e.Serialize(w)).To(Succeed())
+	g.Expect(w.Close()).To(Succeed())
+
+	p := test_utils.NewTestProject(suite.T())
+	createNamespace(suite.T(), suite.k, p.TestSlug())
+
+	p.UpdateTarget("target1", nil)
+	p.AddKustomizeDeployment("d1", []test_utils.KustomizeResource{
+		{Name: "cm1.yaml", Content: uo.FromStringMust(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm1
+  namespace: "{{ args.namespace }}"
+data:
+  k1: v1
+`)},
+	}, nil)
+
+	key := suite.createKluctlDeployment(p, "target1", map[string]any{
+		"namespace": p.TestSlug(),
+	})
+
+	secret := &corev1.Secret{}
+	secret.Name = "verify-keys"
+	secret.Namespace = key.Namespace
+	secret.Data = map[string][]byte{
+		"key.asc": pubKey.Bytes(),
+	}
+	err = suite.k.Client.Create(context.TODO(), secret)
+	g.Expect(err).To(Succeed())
+
+	suite.updateKluctlDeployment(key, func(kd *kluctlv1.KluctlDeployment) {
+		kd.Spec.Source.Verify = &kluctlv1.ProjectSourceVerify{
+			SecretRef: kluctlv1.LocalObjectReference{Name: secret.Name},
+		}
+	})
+
+	suite.Run("unsigned commit is rejected", func() {
+		suite.waitForReconcile(key)
+
+		var kd kluctlv1.KluctlDeployment
+		err := suite.k.Client.Get(context.TODO(), key, &kd)
+		g.Expect(err).To(Succeed())
+
+		c := suite.getReadiness(&kd)
+		g.Expect(c).ToNot(BeNil())
+		g.Expect(c.Reason).To(Equal(kluctlv1.SourceVerificationFailedReason))
+		g.Expect(c.Message).To(ContainSubstring("no signature found"))
+		g.Expect(kd.Status.LastDeployResult).To(BeNil())
+	})
+
+	suite.Run("signed commit is deployed", func() {
+		wt, err := p.GetGitRepo().Worktree()
+		g.Expect(err).To(Succeed())
+		_, err = wt.Commit("signed", &git.CommitOptions{
+			SignKey:           e,
+			AllowEmptyCommits: true,
+			Author:            &object.Signature{Name: "Test User", Email: "no@mail.com", When: time.Now()},
+		})
+		g.Expect(err).To(Succeed())
+
+		suite.waitForCommit(key, getHeadRevision(suite.T(), p))
+
+		var kd kluctlv1.KluctlDeployment
+		err = suite.k.Client.Get(context.TODO(), key, &kd)
+		g.Expect(err).To(Succeed())
+		g.Expect(suite.getReadiness(&kd).Reason).To(Equal(kluctlv1.ReconciliationSucceededReason))
+		g.Expect(kd.Status.LastDeployResult).ToNot(BeNil())
+	})
+}
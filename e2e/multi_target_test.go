@@ -0,0 +1,52 @@
+package e2e
+
+import (
+	"github.com/kluctl/kluctl/v2/e2e/test-utils"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"testing"
+)
+
+func prepareMultiTargetTest(t *testing.T) *test_utils.TestProject {
+	p := test_utils.NewTestProject(t)
+
+	createNamespace(t, defaultCluster1, p.TestSlug())
+	createNamespace(t, defaultCluster2, p.TestSlug())
+
+	addConfigMapDeployment(p, "cm", nil, resourceOpts{
+		name:      "cm",
+		namespace: p.TestSlug(),
+	})
+
+	p.UpdateTarget("test1", func(target *uo.UnstructuredObject) {
+		_ = target.SetNestedField(defaultCluster1.Context, "context")
+		_ = target.SetNestedField("a", "args", "group")
+	})
+	p.UpdateTarget("test2", func(target *uo.UnstructuredObject) {
+		_ = target.SetNestedField(defaultCluster2.Context, "context")
+		_ = target.SetNestedField("b", "args", "group")
+	})
+
+	return p
+}
+
+func TestDeployMultipleTargets(t *testing.T) {
+	t.Parallel()
+
+	p := prepareMultiTargetTest(t)
+
+	p.KluctlMust("deploy", "--yes", "-t", "test1", "-t", "test2")
+	assertConfigMapExists(t, defaultCluster1, p.TestSlug(), "cm")
+	assertConfigMapExists(t, defaultCluster2, p.TestSlug(), "cm")
+
+	p.KluctlMust("validate", "--all-targets")
+}
+
+func TestDeployTargetSelector(t *testing.T) {
+	t.Parallel()
+
+	p := prepareMultiTargetTest(t)
+
+	p.KluctlMust("deploy", "--yes", "--target-selector", "group=b")
+	assertConfigMapNotExists(t, defaultCluster1, p.TestSlug(), "cm")
+	assertConfigMapExists(t, defaultCluster2, p.TestSlug(), "cm")
+}
@@ -2,13 +2,22 @@ package e2e
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"github.com/google/uuid"
 	test_utils "github.com/kluctl/kluctl/v2/e2e/test-utils"
 	"github.com/kluctl/kluctl/v2/pkg/results"
 	"github.com/kluctl/kluctl/v2/pkg/types"
 	"github.com/kluctl/kluctl/v2/pkg/types/result"
+	"github.com/kluctl/kluctl/v2/pkg/utils"
 	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
 	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"strings"
 	"testing"
+	"time"
 )
 
 func assertSummary(t *testing.T, expected result.CommandResultSummary, actual result.CommandResultSummary) {
@@ -42,7 +51,7 @@ func TestWriteResult(t *testing.T) {
 	p.KluctlMust("deploy", "--yes", "-t", "test")
 	assertConfigMapExists(t, k, p.TestSlug(), "cm")
 
-	rs, err := results.NewResultStoreSecrets(context.Background(), k.Client, "kluctl-results", 0)
+	rs, err := results.NewResultStoreSecrets(context.Background(), k.Client, "kluctl-results", results.RetentionPolicy{})
 	assert.NoError(t, err)
 
 	opts := results.ListCommandResultSummariesOptions{
@@ -105,3 +114,81 @@ func TestWriteResult(t *testing.T) {
 		DeletedObjects: 1,
 	}, summaries[0])
 }
+
+func TestWriteLargeResult(t *testing.T) {
+	t.Parallel()
+
+	k := defaultCluster1
+
+	ns := "kluctl-results-" + strings.ToLower(utils.RandomString(8))
+	rs, err := results.NewResultStoreSecrets(context.Background(), k.Client, ns, results.RetentionPolicy{KeepCount: 1})
+	assert.NoError(t, err)
+
+	buildResult := func() *result.CommandResult {
+		// random data does not compress, so this results in more than 1MiB of data
+		randomData := make([]byte, 1024*1024)
+		_, _ = rand.Read(randomData)
+
+		o := uo.FromMap(map[string]any{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata": map[string]any{
+				"name":      "cm",
+				"namespace": "default",
+			},
+			"data": map[string]any{
+				"large": base64.StdEncoding.EncodeToString(randomData),
+			},
+		})
+		return &result.CommandResult{
+			Id: uuid.New().String(),
+			ProjectKey: result.ProjectKey{
+				SubDir: ns,
+			},
+			Command: result.CommandInfo{
+				Initiator: result.CommandInititiator_CommandLine,
+				Command:   "deploy",
+				StartTime: metav1.Now(),
+				EndTime:   metav1.Now(),
+			},
+			Objects: []result.ResultObject{
+				{
+					BaseObject: result.BaseObject{Ref: o.GetK8sRef(), New: true},
+					Rendered:   o,
+				},
+			},
+		}
+	}
+
+	cr1 := buildResult()
+	err = rs.WriteCommandResult(cr1)
+	assert.NoError(t, err)
+
+	cr, err := rs.GetCommandResult(results.GetCommandResultOptions{Id: cr1.Id})
+	assert.NoError(t, err)
+	assert.NotNil(t, cr)
+	assert.Equal(t, cr1.Objects[0].Rendered, cr.Objects[0].Rendered)
+
+	cr, err = rs.GetCommandResult(results.GetCommandResultOptions{Id: cr1.Id, Reduced: true})
+	assert.NoError(t, err)
+	assert.NotNil(t, cr)
+
+	var chunks corev1.SecretList
+	err = k.Client.List(context.Background(), &chunks, client.InNamespace(ns), client.MatchingLabels{"kluctl.io/result-chunk-of": cr1.Id})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, chunks.Items)
+
+	// writing a second result causes the first one to be cleaned up, including all its chunks
+	time.Sleep(1 * time.Second)
+	cr2 := buildResult()
+	err = rs.WriteCommandResult(cr2)
+	assert.NoError(t, err)
+
+	has, err := rs.HasCommandResult(cr1.Id)
+	assert.NoError(t, err)
+	assert.False(t, has)
+
+	err = k.Client.List(context.Background(), &chunks, client.InNamespace(ns), client.MatchingLabels{"kluctl.io/result-chunk-of": cr1.Id})
+	assert.NoError(t, err)
+	assert.Empty(t, chunks.Items)
+}
@@ -0,0 +1,111 @@
+package e2e
+
+import (
+	"context"
+	"github.com/kluctl/kluctl/v2/e2e/test-utils"
+	"github.com/kluctl/kluctl/v2/pkg/results"
+	"github.com/kluctl/kluctl/v2/pkg/types"
+	"github.com/kluctl/kluctl/v2/pkg/types/result"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestRollbackOnError(t *testing.T) {
+	t.Parallel()
+
+	k := defaultCluster1
+
+	p := test_utils.NewTestProject(t)
+
+	createNamespace(t, k, p.TestSlug())
+
+	p.UpdateTarget("test", nil)
+
+	addConfigMapDeployment(p, "cm", map[string]string{
+		"d1": "v1",
+	}, resourceOpts{
+		name:      "cm",
+		namespace: p.TestSlug(),
+	})
+	p.KluctlMust("deploy", "--yes", "-t", "test")
+	cm := assertConfigMapExists(t, k, p.TestSlug(), "cm")
+	assertNestedFieldEquals(t, cm, "v1", "data", "d1")
+
+	p.UpdateYaml("cm/configmap-cm.yml", func(o *uo.UnstructuredObject) error {
+		_ = o.SetNestedField("v2", "data", "d1")
+		return nil
+	}, "")
+	addConfigMapDeployment(p, "cm2", nil, resourceOpts{
+		name:      "cm2",
+		namespace: p.TestSlug(),
+	})
+	addConfigMapDeployment(p, "cm3", nil, resourceOpts{
+		name:      "cm3",
+		namespace: p.TestSlug() + "-does-not-exist",
+	})
+
+	// cm3 fails to deploy, so cm is restored and the newly created cm2 gets deleted
+	_, _, err := p.Kluctl("deploy", "--yes", "-t", "test", "--rollback-on-error")
+	assert.Error(t, err)
+	cm = assertConfigMapExists(t, k, p.TestSlug(), "cm")
+	assertNestedFieldEquals(t, cm, "v1", "data", "d1")
+	assertConfigMapNotExists(t, k, p.TestSlug(), "cm2")
+}
+
+func TestRollbackCommand(t *testing.T) {
+	t.Parallel()
+
+	k := defaultCluster1
+
+	p := test_utils.NewTestProject(t)
+
+	createNamespace(t, k, p.TestSlug())
+
+	p.UpdateTarget("test", nil)
+
+	addConfigMapDeployment(p, "cm", map[string]string{
+		"d1": "v1",
+	}, resourceOpts{
+		name:      "cm",
+		namespace: p.TestSlug(),
+	})
+	p.KluctlMust("deploy", "--yes", "-t", "test")
+
+	rs, err := results.NewResultStoreSecrets(context.Background(), k.Client, "kluctl-results", results.RetentionPolicy{})
+	assert.NoError(t, err)
+	summaries, err := rs.ListCommandResultSummaries(results.ListCommandResultSummariesOptions{
+		ProjectFilter: &result.ProjectKey{
+			GitRepoKey: types.ParseGitUrlMust(p.GitUrl()).RepoKey(),
+		},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, summaries, 1)
+	firstId := summaries[0].Id
+
+	p.UpdateYaml("cm/configmap-cm.yml", func(o *uo.UnstructuredObject) error {
+		_ = o.SetNestedField("v2", "data", "d1")
+		return nil
+	}, "")
+	p.KluctlMust("deploy", "--yes", "-t", "test")
+	cm := assertConfigMapExists(t, k, p.TestSlug(), "cm")
+	assertNestedFieldEquals(t, cm, "v2", "data", "d1")
+
+	p.KluctlMust("rollback", "--yes", "--result-id", firstId)
+	cm = assertConfigMapExists(t, k, p.TestSlug(), "cm")
+	assertNestedFieldEquals(t, cm, "v1", "data", "d1")
+
+	p.KluctlMust("deploy", "--yes", "-t", "test")
+	cm = assertConfigMapExists(t, k, p.TestSlug(), "cm")
+	assertNestedFieldEquals(t, cm, "v2", "data", "d1")
+
+	// the most recent deployment is skipped, so this rolls back to the deployment with v2, which is the same as the
+	// current state
+	p.KluctlMust("rollback", "--yes", "-t", "test", "--previous", "1")
+	cm = assertConfigMapExists(t, k, p.TestSlug(), "cm")
+	assertNestedFieldEquals(t, cm, "v2", "data", "d1")
+
+	p.KluctlMust("rollback", "--yes", "-t", "test", "--previous", "2")
+	cm = assertConfigMapExists(t, k, p.TestSlug(), "cm")
+	assertNestedFieldEquals(t, cm, "v1", "data", "d1")
+}
@@ -0,0 +1,79 @@
+package e2e
+
+import (
+	"github.com/kluctl/kluctl/v2/e2e/test-utils"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func prepareRolloutTest(t *testing.T) *test_utils.TestProject {
+	p := test_utils.NewTestProject(t)
+
+	addConfigMapDeployment(p, "cm", nil, resourceOpts{
+		name:      "cm",
+		namespace: p.TestSlug(),
+	})
+
+	p.UpdateTarget("test1", func(target *uo.UnstructuredObject) {
+		_ = target.SetNestedField(defaultCluster1.Context, "context")
+	})
+	p.UpdateTarget("test2", func(target *uo.UnstructuredObject) {
+		_ = target.SetNestedField(defaultCluster2.Context, "context")
+	})
+
+	return p
+}
+
+func TestRollout(t *testing.T) {
+	t.Parallel()
+
+	p := prepareRolloutTest(t)
+
+	createNamespace(t, defaultCluster1, p.TestSlug())
+	createNamespace(t, defaultCluster2, p.TestSlug())
+
+	p.UpdateRollout("r1", func(rollout *uo.UnstructuredObject) {
+		_ = rollout.SetNestedField([]any{
+			map[string]any{
+				"name":    "canary",
+				"targets": []any{"test1"},
+				"pause": map[string]any{
+					"duration": "1s",
+				},
+			},
+			map[string]any{
+				"targets": []any{"test2"},
+			},
+		}, "waves")
+	})
+
+	p.KluctlMust("rollout", "--yes", "--rollout", "r1")
+	assertConfigMapExists(t, defaultCluster1, p.TestSlug(), "cm")
+	assertConfigMapExists(t, defaultCluster2, p.TestSlug(), "cm")
+}
+
+func TestRolloutStopsOnFailure(t *testing.T) {
+	t.Parallel()
+
+	p := prepareRolloutTest(t)
+
+	// the namespace is missing in cluster2, so deploying test2 fails
+	createNamespace(t, defaultCluster1, p.TestSlug())
+
+	p.UpdateRollout("r1", func(rollout *uo.UnstructuredObject) {
+		_ = rollout.SetNestedField([]any{
+			map[string]any{
+				"targets": []any{"test2"},
+			},
+			map[string]any{
+				"targets": []any{"test1"},
+			},
+		}, "waves")
+	})
+
+	_, _, err := p.Kluctl("rollout", "--yes")
+	assert.Error(t, err)
+	assertConfigMapNotExists(t, defaultCluster2, p.TestSlug(), "cm")
+	assertConfigMapNotExists(t, defaultCluster1, p.TestSlug(), "cm")
+}
@@ -185,6 +185,10 @@ func (p *TestProject) UpdateTarget(name string, cb func(target *uo.UnstructuredO
 	p.UpdateNamedListItem(uo.KeyPath{"targets"}, name, cb)
 }
 
+func (p *TestProject) UpdateRollout(name string, cb func(rollout *uo.UnstructuredObject)) {
+	p.UpdateNamedListItem(uo.KeyPath{"rollouts"}, name, cb)
+}
+
 func (p *TestProject) UpdateSecretSet(name string, cb func(secretSet *uo.UnstructuredObject)) {
 	p.UpdateNamedListItem(uo.KeyPath{"secretsConfig", "secretSets"}, name, cb)
 }
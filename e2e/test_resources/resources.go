@@ -72,7 +72,7 @@ func waitReadiness(k *test_utils.EnvTestCluster, x *uo.UnstructuredObject) {
 		if err != nil {
 			panic(err)
 		}
-		vr := validation.ValidateObject(nil, uo.FromUnstructured(u), true, true)
+		vr := validation.ValidateObject(nil, uo.FromUnstructured(u), true, true, nil)
 		if vr.Ready {
 			break
 		} else {
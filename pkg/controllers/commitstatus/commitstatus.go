@@ -0,0 +1,177 @@
+package commitstatus
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/hashicorp/go-retryablehttp"
+	kluctlv1 "github.com/kluctl/kluctl/v2/api/v1beta1"
+	"github.com/kluctl/kluctl/v2/pkg/types"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+)
+
+type State string
+
+const (
+	StatePending State = "pending"
+	StateSuccess State = "success"
+	StateFailure State = "failure"
+	StateError   State = "error"
+)
+
+// maxDescriptionLength is the maximum length in characters of descriptions accepted by GitHub. Longer descriptions are
+// truncated.
+const maxDescriptionLength = 140
+
+// Status is a single commit status to be reported to the Git forge
+type Status struct {
+	Context     string
+	State       State
+	Description string
+	TargetUrl   string
+}
+
+// GetAddress returns the configured API address or derives it from the source url
+func GetAddress(cs *kluctlv1.CommitStatus, sourceUrl *types.GitUrl) (string, error) {
+	if cs.Address != "" {
+		return strings.TrimSuffix(cs.Address, "/"), nil
+	}
+	if sourceUrl == nil || sourceUrl.Hostname() == "" {
+		return "", fmt.Errorf("can not determine %s API address without a source url", cs.Provider)
+	}
+
+	host := sourceUrl.Host
+	if sourceUrl.IsSsh() {
+		// the ssh port has no meaning for the API
+		host = sourceUrl.Hostname()
+	}
+
+	if cs.Provider == kluctlv1.CommitStatusProviderGitHub {
+		if strings.ToLower(host) == "github.com" {
+			return "https://api.github.com", nil
+		}
+		return fmt.Sprintf("https://%s/api/v3", host), nil
+	}
+	return fmt.Sprintf("https://%s", host), nil
+}
+
+// GetRepository returns the configured repository or derives it from the path of the source url
+func GetRepository(cs *kluctlv1.CommitStatus, sourceUrl *types.GitUrl) (string, error) {
+	repo := cs.Repository
+	if repo == "" && sourceUrl != nil {
+		repo = sourceUrl.Path
+		repo = strings.TrimSuffix(repo, "/")
+		repo = strings.TrimSuffix(repo, ".git")
+	}
+	repo = strings.Trim(repo, "/")
+	if repo == "" {
+		return "", fmt.Errorf("can not determine repository for %s commit status", cs.Provider)
+	}
+	if cs.Provider != kluctlv1.CommitStatusProviderGitLab && strings.Count(repo, "/") != 1 {
+		return "", fmt.Errorf("invalid repository '%s', expected 'owner/repo'", repo)
+	}
+	return repo, nil
+}
+
+// truncateDescription truncates the description on a rune boundary, so that multibyte characters are not split
+func truncateDescription(description string) string {
+	if utf8.RuneCountInString(description) <= maxDescriptionLength {
+		return description
+	}
+	return string([]rune(description)[:maxDescriptionLength-3]) + "..."
+}
+
+func buildRequest(ctx context.Context, cs *kluctlv1.CommitStatus, address string, repo string, token string, sha string, s *Status) (*retryablehttp.Request, error) {
+	description := truncateDescription(s.Description)
+
+	var reqUrl string
+	var payload map[string]any
+	var authHeader, authValue string
+	switch cs.Provider {
+	case kluctlv1.CommitStatusProviderGitHub:
+		reqUrl = fmt.Sprintf("%s/repos/%s/statuses/%s", address, repo, sha)
+		payload = map[string]any{
+			"state":       s.State,
+			"context":     s.Context,
+			"description": description,
+		}
+		authHeader, authValue = "Authorization", "Bearer "+token
+	case kluctlv1.CommitStatusProviderGitea:
+		reqUrl = fmt.Sprintf("%s/api/v1/repos/%s/statuses/%s", address, repo, sha)
+		payload = map[string]any{
+			"state":       s.State,
+			"context":     s.Context,
+			"description": description,
+		}
+		authHeader, authValue = "Authorization", "token "+token
+	case kluctlv1.CommitStatusProviderGitLab:
+		state := string(s.State)
+		if s.State == StateFailure || s.State == StateError {
+			state = "failed"
+		}
+		reqUrl = fmt.Sprintf("%s/api/v4/projects/%s/statuses/%s", address, url.PathEscape(repo), sha)
+		payload = map[string]any{
+			"state":       state,
+			"name":        s.Context,
+			"description": description,
+		}
+		authHeader, authValue = "PRIVATE-TOKEN", token
+	default:
+		return nil, fmt.Errorf("unsupported commit status provider '%s'", cs.Provider)
+	}
+	if s.TargetUrl != "" {
+		payload["target_url"] = s.TargetUrl
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := retryablehttp.NewRequestWithContext(ctx, http.MethodPost, reqUrl, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set(authHeader, authValue)
+	return req, nil
+}
+
+// Send reports the status for the given commit
+func Send(ctx context.Context, client *retryablehttp.Client, cs *kluctlv1.CommitStatus, sourceUrl *types.GitUrl, token string, sha string, s *Status) error {
+	address, err := GetAddress(cs, sourceUrl)
+	if err != nil {
+		return err
+	}
+	repo, err := GetRepository(cs, sourceUrl)
+	if err != nil {
+		return err
+	}
+
+	req, err := buildRequest(ctx, cs, address, repo, token, sha, s)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send %s commit status: %w", cs.Provider, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		msg := strings.TrimSpace(string(b))
+		if cs.Provider == kluctlv1.CommitStatusProviderGitLab && resp.StatusCode == http.StatusBadRequest && strings.Contains(msg, "Cannot transition status") {
+			// GitLab refuses to set the same state twice in a row, which is fine for us
+			return nil
+		}
+		return fmt.Errorf("failed to send %s commit status: %s: %s", cs.Provider, resp.Status, msg)
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package commitstatus
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/hashicorp/go-retryablehttp"
+	kluctlv1 "github.com/kluctl/kluctl/v2/api/v1beta1"
+	"github.com/kluctl/kluctl/v2/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestGetAddress(t *testing.T) {
+	tests := []struct {
+		provider string
+		address  string
+		url      string
+		expected string
+	}{
+		{provider: kluctlv1.CommitStatusProviderGitHub, url: "https://github.com/org/repo.git", expected: "https://api.github.com"},
+		{provider: kluctlv1.CommitStatusProviderGitHub, url: "git@github.com:org/repo.git", expected: "https://api.github.com"},
+		{provider: kluctlv1.CommitStatusProviderGitHub, url: "https://ghe.example.com/org/repo", expected: "https://ghe.example.com/api/v3"},
+		{provider: kluctlv1.CommitStatusProviderGitLab, url: "ssh://git@gitlab.example.com:2222/group/sub/repo.git", expected: "https://gitlab.example.com"},
+		{provider: kluctlv1.CommitStatusProviderGitea, url: "https://gitea.example.com:3000/org/repo", expected: "https://gitea.example.com:3000"},
+		{provider: kluctlv1.CommitStatusProviderGitea, address: "http://gitea:3000/", url: "https://gitea.example.com/org/repo", expected: "http://gitea:3000"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.url, func(t *testing.T) {
+			cs := &kluctlv1.CommitStatus{Provider: tc.provider, Address: tc.address}
+			a, err := GetAddress(cs, types.ParseGitUrlMust(tc.url))
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, a)
+		})
+	}
+}
+
+func TestGetRepository(t *testing.T) {
+	cs := &kluctlv1.CommitStatus{Provider: kluctlv1.CommitStatusProviderGitHub}
+	r, err := GetRepository(cs, types.ParseGitUrlMust("git@github.com:org/repo.git"))
+	assert.NoError(t, err)
+	assert.Equal(t, "org/repo", r)
+
+	_, err = GetRepository(cs, types.ParseGitUrlMust("https://github.com/org/sub/repo.git"))
+	assert.ErrorContains(t, err, "expected 'owner/repo'")
+
+	cs.Repository = "other/repo"
+	r, err = GetRepository(cs, types.ParseGitUrlMust("https://github.com/org/repo.git"))
+	assert.NoError(t, err)
+	assert.Equal(t, "other/repo", r)
+
+	cs = &kluctlv1.CommitStatus{Provider: kluctlv1.CommitStatusProviderGitLab}
+	r, err = GetRepository(cs, types.ParseGitUrlMust("https://gitlab.com/group/sub/repo.git/"))
+	assert.NoError(t, err)
+	assert.Equal(t, "group/sub/repo", r)
+}
+
+func TestSend(t *testing.T) {
+	var path string
+	var header http.Header
+	var body map[string]any
+	status := http.StatusCreated
+	responseBody := ""
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.EscapedPath()
+		header = r.Header
+		b, _ := io.ReadAll(r.Body)
+		body = nil
+		_ = json.Unmarshal(b, &body)
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(responseBody))
+	}))
+	defer server.Close()
+
+	client := retryablehttp.NewClient()
+	client.RetryMax = 0
+	client.Logger = nil
+
+	s := &Status{
+		Context:     "kluctl/ns/kd/deploy",
+		State:       StateFailure,
+		Description: "deploy failed",
+		TargetUrl:   "http://webui/#/results/id",
+	}
+
+	cs := &kluctlv1.CommitStatus{Provider: kluctlv1.CommitStatusProviderGitHub, Address: server.URL}
+	assert.NoError(t, Send(context.Background(), client, cs, types.ParseGitUrlMust("https://github.com/org/repo.git"), "secret", "abcdef", s))
+	assert.Equal(t, "/repos/org/repo/statuses/abcdef", path)
+	assert.Equal(t, "Bearer secret", header.Get("Authorization"))
+	assert.Equal(t, map[string]any{
+		"state":       "failure",
+		"context":     "kluctl/ns/kd/deploy",
+		"description": "deploy failed",
+		"target_url":  "http://webui/#/results/id",
+	}, body)
+
+	cs = &kluctlv1.CommitStatus{Provider: kluctlv1.CommitStatusProviderGitea, Address: server.URL}
+	assert.NoError(t, Send(context.Background(), client, cs, types.ParseGitUrlMust("https://gitea.example.com/org/repo.git"), "secret", "abcdef", s))
+	assert.Equal(t, "/api/v1/repos/org/repo/statuses/abcdef", path)
+	assert.Equal(t, "token secret", header.Get("Authorization"))
+	assert.Equal(t, "failure", body["state"])
+	assert.Equal(t, "kluctl/ns/kd/deploy", body["context"])
+
+	cs = &kluctlv1.CommitStatus{Provider: kluctlv1.CommitStatusProviderGitLab, Address: server.URL}
+	assert.NoError(t, Send(context.Background(), client, cs, types.ParseGitUrlMust("https://gitlab.example.com/group/sub/repo.git"), "secret", "abcdef", s))
+	assert.Equal(t, "/api/v4/projects/group%2Fsub%2Frepo/statuses/abcdef", path)
+	assert.Equal(t, "secret", header.Get("PRIVATE-TOKEN"))
+	assert.Equal(t, "failed", body["state"])
+	assert.Equal(t, "kluctl/ns/kd/deploy", body["name"])
+
+	status = http.StatusBadRequest
+	responseBody = `{"message":"Cannot transition status via :drop from :failed"}`
+	assert.NoError(t, Send(context.Background(), client, cs, types.ParseGitUrlMust("https://gitlab.example.com/group/repo.git"), "secret", "abcdef", s))
+
+	responseBody = `{"message":"bad request"}`
+	assert.ErrorContains(t, Send(context.Background(), client, cs, types.ParseGitUrlMust("https://gitlab.example.com/group/repo.git"), "secret", "abcdef", s), "400 Bad Request")
+
+	s.Description = string(make([]byte, 200))
+	status = http.StatusCreated
+	cs = &kluctlv1.CommitStatus{Provider: kluctlv1.CommitStatusProviderGitHub, Address: server.URL}
+	assert.NoError(t, Send(context.Background(), client, cs, types.ParseGitUrlMust("https://github.com/org/repo.git"), "secret", "abcdef", s))
+	assert.Len(t, body["description"], maxDescriptionLength)
+}
+
+func TestTruncateDescription(t *testing.T) {
+	assert.Equal(t, "short", truncateDescription("short"))
+
+	s := strings.Repeat("x", maxDescriptionLength)
+	assert.Equal(t, s, truncateDescription(s))
+
+	// multibyte characters must not be split and are counted as single characters
+	s = strings.Repeat("ä", maxDescriptionLength+1)
+	d := truncateDescription(s)
+	assert.True(t, utf8.ValidString(d))
+	assert.Equal(t, maxDescriptionLength, utf8.RuneCountInString(d))
+	assert.Equal(t, strings.Repeat("ä", maxDescriptionLength-3)+"...", d)
+
+	s = strings.Repeat("ä", maxDescriptionLength)
+	assert.Equal(t, s, truncateDescription(s))
+}
@@ -0,0 +1,122 @@
+package deploymentset
+
+import (
+	"fmt"
+	"github.com/go-git/go-git/v5/plumbing"
+	kluctlv1 "github.com/kluctl/kluctl/v2/api/v1beta1"
+	"github.com/kluctl/kluctl/v2/pkg/types"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// maxSlugLength leaves room for a prefix while keeping generated names within the limits of DNS labels
+const maxSlugLength = 50
+
+var slugInvalidChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// Slugify converts the given string into a string that can be used as part of object names
+func Slugify(s string) string {
+	s = strings.ToLower(s)
+	s = slugInvalidChars.ReplaceAllString(s, "-")
+	s = strings.Trim(s, "-")
+	if len(s) > maxSlugLength {
+		s = strings.TrimRight(s[:maxSlugLength], "-")
+	}
+	return s
+}
+
+// ListParams returns one parameter set per element of the list generator
+func ListParams(g *kluctlv1.ListGenerator) []map[string]any {
+	ret := make([]map[string]any, 0, len(g.Elements))
+	for _, e := range g.Elements {
+		params := map[string]any{}
+		for k, v := range e {
+			params[k] = v
+		}
+		ret = append(ret, params)
+	}
+	return ret
+}
+
+// BranchParams returns one parameter set per branch found in refs that matches the pattern of the branches generator
+func BranchParams(url types.GitUrl, g *kluctlv1.GitBranchesGenerator, refs []*plumbing.Reference) ([]map[string]any, error) {
+	var pattern *regexp.Regexp
+	if g.Pattern != "" {
+		var err error
+		pattern, err = regexp.Compile(fmt.Sprintf("^%s$", g.Pattern))
+		if err != nil {
+			return nil, fmt.Errorf("invalid branch pattern '%s': %w", g.Pattern, err)
+		}
+	}
+
+	var ret []map[string]any
+	for _, ref := range refs {
+		if !ref.Name().IsBranch() || ref.Type() != plumbing.HashReference {
+			continue
+		}
+		branch := ref.Name().Short()
+		if pattern != nil && !pattern.MatchString(branch) {
+			continue
+		}
+		ret = append(ret, map[string]any{
+			"git": map[string]any{
+				"url":        url.String(),
+				"branch":     branch,
+				"branchSlug": Slugify(branch),
+				"commit":     ref.Hash().String(),
+			},
+		})
+	}
+	sort.SliceStable(ret, func(i, j int) bool {
+		return ret[i]["git"].(map[string]any)["branch"].(string) < ret[j]["git"].(map[string]any)["branch"].(string)
+	})
+	return ret, nil
+}
+
+// DirectoryParams returns one parameter set per directory inside repoDir that matches the path pattern of the
+// directories generator
+func DirectoryParams(url types.GitUrl, g *kluctlv1.GitDirectoriesGenerator, repoDir string, ref string, commit string) ([]map[string]any, error) {
+	pattern := filepath.Clean(filepath.FromSlash(g.Path))
+	if filepath.IsAbs(pattern) || pattern == ".." || strings.HasPrefix(pattern, ".."+string(filepath.Separator)) {
+		return nil, fmt.Errorf("invalid directories path '%s', must be relative to the repository root", g.Path)
+	}
+
+	matches, err := filepath.Glob(filepath.Join(repoDir, pattern))
+	if err != nil {
+		return nil, fmt.Errorf("invalid directories path '%s': %w", g.Path, err)
+	}
+	sort.Strings(matches)
+
+	var ret []map[string]any
+	for _, m := range matches {
+		st, err := os.Stat(m)
+		if err != nil {
+			return nil, err
+		}
+		if !st.IsDir() {
+			continue
+		}
+		rel, err := filepath.Rel(repoDir, m)
+		if err != nil {
+			return nil, err
+		}
+		rel = filepath.ToSlash(rel)
+		if rel == ".git" || strings.HasPrefix(rel, ".git/") {
+			continue
+		}
+		ret = append(ret, map[string]any{
+			"git": map[string]any{
+				"url":          url.String(),
+				"ref":          ref,
+				"commit":       commit,
+				"path":         rel,
+				"pathBasename": filepath.Base(m),
+				"pathSlug":     Slugify(rel),
+			},
+		})
+	}
+	return ret, nil
+}
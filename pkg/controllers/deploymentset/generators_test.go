@@ -0,0 +1,113 @@
+package deploymentset
+
+import (
+	"github.com/go-git/go-git/v5/plumbing"
+	kluctlv1 "github.com/kluctl/kluctl/v2/api/v1beta1"
+	"github.com/kluctl/kluctl/v2/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSlugify(t *testing.T) {
+	assert.Equal(t, "feature-my-branch", Slugify("feature/My_Branch"))
+	assert.Equal(t, "fix-1-2", Slugify("--fix--1.2--"))
+	assert.Equal(t, "", Slugify("/"))
+
+	long := ""
+	for i := 0; i < 10; i++ {
+		long += "abcdefghi-"
+	}
+	assert.Len(t, Slugify(long), 49)
+}
+
+func TestListParams(t *testing.T) {
+	params := ListParams(&kluctlv1.ListGenerator{
+		Elements: []map[string]string{
+			{"target": "dev"},
+			{"target": "prod", "cluster": "c1"},
+		},
+	})
+	assert.Equal(t, []map[string]any{
+		{"target": "dev"},
+		{"target": "prod", "cluster": "c1"},
+	}, params)
+}
+
+func TestBranchParams(t *testing.T) {
+	url := types.ParseGitUrlMust("https://example.com/org/repo.git")
+	refs := []*plumbing.Reference{
+		plumbing.NewSymbolicReference(plumbing.HEAD, "refs/heads/main"),
+		plumbing.NewHashReference("refs/heads/main", plumbing.NewHash("1111111111111111111111111111111111111111")),
+		plumbing.NewHashReference("refs/heads/preview/b", plumbing.NewHash("2222222222222222222222222222222222222222")),
+		plumbing.NewHashReference("refs/heads/preview/a", plumbing.NewHash("3333333333333333333333333333333333333333")),
+		plumbing.NewHashReference("refs/tags/preview/c", plumbing.NewHash("4444444444444444444444444444444444444444")),
+	}
+
+	params, err := BranchParams(*url, &kluctlv1.GitBranchesGenerator{}, refs)
+	assert.NoError(t, err)
+	assert.Len(t, params, 3)
+
+	params, err = BranchParams(*url, &kluctlv1.GitBranchesGenerator{Pattern: "preview/.*"}, refs)
+	assert.NoError(t, err)
+	assert.Equal(t, []map[string]any{
+		{"git": map[string]any{
+			"url":        "https://example.com/org/repo.git",
+			"branch":     "preview/a",
+			"branchSlug": "preview-a",
+			"commit":     "3333333333333333333333333333333333333333",
+		}},
+		{"git": map[string]any{
+			"url":        "https://example.com/org/repo.git",
+			"branch":     "preview/b",
+			"branchSlug": "preview-b",
+			"commit":     "2222222222222222222222222222222222222222",
+		}},
+	}, params)
+
+	// the pattern must match the whole branch name
+	params, err = BranchParams(*url, &kluctlv1.GitBranchesGenerator{Pattern: "preview"}, refs)
+	assert.NoError(t, err)
+	assert.Len(t, params, 0)
+
+	_, err = BranchParams(*url, &kluctlv1.GitBranchesGenerator{Pattern: "("}, refs)
+	assert.ErrorContains(t, err, "invalid branch pattern")
+}
+
+func TestDirectoryParams(t *testing.T) {
+	url := types.ParseGitUrlMust("https://example.com/org/repo.git")
+	dir := t.TempDir()
+	for _, p := range []string{".git/objects", "apps/app1", "apps/App_2", "other"} {
+		assert.NoError(t, os.MkdirAll(filepath.Join(dir, p), 0o700))
+	}
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "apps/file.yaml"), nil, 0o600))
+
+	params, err := DirectoryParams(*url, &kluctlv1.GitDirectoriesGenerator{Path: "apps/*"}, dir, "refs/heads/main", "1111")
+	assert.NoError(t, err)
+	assert.Equal(t, []map[string]any{
+		{"git": map[string]any{
+			"url":          "https://example.com/org/repo.git",
+			"ref":          "refs/heads/main",
+			"commit":       "1111",
+			"path":         "apps/App_2",
+			"pathBasename": "App_2",
+			"pathSlug":     "apps-app-2",
+		}},
+		{"git": map[string]any{
+			"url":          "https://example.com/org/repo.git",
+			"ref":          "refs/heads/main",
+			"commit":       "1111",
+			"path":         "apps/app1",
+			"pathBasename": "app1",
+			"pathSlug":     "apps-app1",
+		}},
+	}, params)
+
+	params, err = DirectoryParams(*url, &kluctlv1.GitDirectoriesGenerator{Path: "*"}, dir, "", "1111")
+	assert.NoError(t, err)
+	assert.Len(t, params, 2)
+
+	_, err = DirectoryParams(*url, &kluctlv1.GitDirectoriesGenerator{Path: "../*"}, dir, "", "1111")
+	assert.ErrorContains(t, err, "must be relative to the repository root")
+}
@@ -0,0 +1,106 @@
+package deploymentset
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/hashicorp/go-multierror"
+	"github.com/kluctl/go-jinja2"
+	kluctlv1 "github.com/kluctl/kluctl/v2/api/v1beta1"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"strings"
+)
+
+func isTemplate(s string) bool {
+	return strings.Contains(s, "{{") || strings.Contains(s, "{%")
+}
+
+// Render renders the template of the KluctlDeploymentSet with the given parameters. All string values of the
+// template are rendered, while keys are left untouched. The result is a KluctlDeployment in the namespace of the
+// KluctlDeploymentSet.
+func Render(j2 *jinja2.Jinja2, obj *kluctlv1.KluctlDeploymentSet, params map[string]any) (*unstructured.Unstructured, error) {
+	tmpl := &obj.Spec.Template
+
+	spec := map[string]any{}
+	if len(tmpl.Spec.Raw) != 0 {
+		err := json.Unmarshal(tmpl.Spec.Raw, &spec)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse template spec: %w", err)
+		}
+	}
+
+	labels := map[string]any{}
+	for k, v := range tmpl.Metadata.Labels {
+		labels[k] = v
+	}
+	annotations := map[string]any{}
+	for k, v := range tmpl.Metadata.Annotations {
+		annotations[k] = v
+	}
+
+	o := map[string]any{
+		"metadata": map[string]any{
+			"name":        tmpl.Metadata.Name,
+			"labels":      labels,
+			"annotations": annotations,
+		},
+		"spec": spec,
+	}
+
+	var jobs []*jinja2.RenderJob
+	var setters []func(v string) error
+	err := uo.NewObjectIterator(o).IterateLeafs(func(it *uo.ObjectIterator) error {
+		s, ok := it.Value().(string)
+		if !ok || !isTemplate(s) {
+			return nil
+		}
+		jobs = append(jobs, &jinja2.RenderJob{Template: s})
+		parent, key := it.Parent(), it.Key()
+		setters = append(setters, func(v string) error {
+			return uo.SetChild(parent, key, v)
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(jobs) != 0 {
+		err = j2.RenderStrings(jobs, jinja2.WithGlobals(params))
+		if err != nil {
+			return nil, err
+		}
+		for i, job := range jobs {
+			if job.Error != nil {
+				err = multierror.Append(err, fmt.Errorf("failed to render template '%s': %w", job.Template, job.Error))
+			} else if err2 := setters[i](*job.Result); err2 != nil {
+				err = multierror.Append(err, err2)
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	ret := &unstructured.Unstructured{Object: o}
+	ret.SetAPIVersion(kluctlv1.GroupVersion.String())
+	ret.SetKind(kluctlv1.KluctlDeploymentKind)
+	ret.SetNamespace(obj.GetNamespace())
+
+	if errs := validation.IsDNS1123Subdomain(ret.GetName()); len(errs) != 0 {
+		return nil, fmt.Errorf("invalid name '%s' rendered from template: %s", ret.GetName(), strings.Join(errs, ", "))
+	}
+
+	labels2 := ret.GetLabels()
+	if labels2 == nil {
+		labels2 = map[string]string{}
+	}
+	labels2[kluctlv1.KluctlDeploymentSetLabel] = obj.GetName()
+	ret.SetLabels(labels2)
+	if len(ret.GetAnnotations()) == 0 {
+		unstructured.RemoveNestedField(ret.Object, "metadata", "annotations")
+	}
+
+	return ret, nil
+}
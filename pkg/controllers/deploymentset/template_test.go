@@ -0,0 +1,85 @@
+package deploymentset
+
+import (
+	kluctlv1 "github.com/kluctl/kluctl/v2/api/v1beta1"
+	"github.com/kluctl/kluctl/v2/pkg/kluctl_jinja2"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"testing"
+)
+
+func buildTestSet(name string, spec string) *kluctlv1.KluctlDeploymentSet {
+	return &kluctlv1.KluctlDeploymentSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "set",
+			Namespace: "ns",
+		},
+		Spec: kluctlv1.KluctlDeploymentSetSpec{
+			Template: kluctlv1.KluctlDeploymentTemplate{
+				Metadata: kluctlv1.KluctlDeploymentTemplateMetadata{
+					Name:   name,
+					Labels: map[string]string{"branch": "{{ git.branchSlug }}"},
+				},
+				Spec: runtime.RawExtension{Raw: []byte(spec)},
+			},
+		},
+	}
+}
+
+func TestRender(t *testing.T) {
+	j2, err := kluctl_jinja2.NewKluctlJinja2(true)
+	assert.NoError(t, err)
+	defer j2.Close()
+
+	params := map[string]any{
+		"git": map[string]any{
+			"branch":     "feature/x",
+			"branchSlug": "feature-x",
+		},
+	}
+
+	set := buildTestSet("preview-{{ git.branchSlug }}", `{
+		"interval": "5m",
+		"delete": true,
+		"source": {"url": "https://example.com/repo.git", "ref": {"branch": "{{ git.branch }}"}},
+		"args": {"{{ not_rendered }}": "{{ git.branchSlug }}", "list": ["a", "{% if true %}b{% endif %}"]}
+	}`)
+	o, err := Render(j2, set, params)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"apiVersion": "gitops.kluctl.io/v1beta1",
+		"kind":       "KluctlDeployment",
+		"metadata": map[string]any{
+			"name":      "preview-feature-x",
+			"namespace": "ns",
+			"labels": map[string]any{
+				"branch":                          "feature-x",
+				"gitops.kluctl.io/deployment-set": "set",
+			},
+		},
+		"spec": map[string]any{
+			"interval": "5m",
+			"delete":   true,
+			"source": map[string]any{
+				"url": "https://example.com/repo.git",
+				"ref": map[string]any{"branch": "feature/x"},
+			},
+			"args": map[string]any{
+				"{{ not_rendered }}": "feature-x",
+				"list":               []any{"a", "b"},
+			},
+		},
+	}, o.Object)
+
+	// the template itself must not be modified
+	assert.Equal(t, "preview-{{ git.branchSlug }}", set.Spec.Template.Metadata.Name)
+
+	set = buildTestSet("preview-{{ git.branch }}", `{}`)
+	_, err = Render(j2, set, params)
+	assert.ErrorContains(t, err, "invalid name 'preview-feature/x'")
+
+	set = buildTestSet("preview-{{ git.branchSlug ", `{}`)
+	_, err = Render(j2, set, params)
+	assert.ErrorContains(t, err, "failed to render template")
+}
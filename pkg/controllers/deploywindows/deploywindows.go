@@ -0,0 +1,127 @@
+package deploywindows
+
+import (
+	"fmt"
+	kluctlv1 "github.com/kluctl/kluctl/v2/api/v1beta1"
+	"github.com/robfig/cron/v3"
+	"time"
+)
+
+// maxIterations limits the number of window boundaries inspected when searching for the next allowed time
+const maxIterations = 1000
+
+type window struct {
+	schedule cron.Schedule
+	duration time.Duration
+}
+
+// DeployWindows evaluates the allow and deny windows of a KluctlDeployment
+type DeployWindows struct {
+	allow []window
+	deny  []window
+}
+
+// New parses the cron schedules and the time zone of the given deploy windows
+func New(spec *kluctlv1.DeployWindows) (*DeployWindows, error) {
+	loc := time.UTC
+	if spec.TimeZone != "" {
+		var err error
+		loc, err = time.LoadLocation(spec.TimeZone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid time zone '%s': %w", spec.TimeZone, err)
+		}
+	}
+
+	parse := func(windows []kluctlv1.DeployWindow) ([]window, error) {
+		var ret []window
+		for _, w := range windows {
+			s, err := cron.ParseStandard(w.Schedule)
+			if err != nil {
+				return nil, fmt.Errorf("invalid deploy window schedule '%s': %w", w.Schedule, err)
+			}
+			ss, ok := s.(*cron.SpecSchedule)
+			if !ok {
+				return nil, fmt.Errorf("invalid deploy window schedule '%s': only cron expressions are supported", w.Schedule)
+			}
+			ss.Location = loc
+			if w.Duration.Duration <= 0 {
+				return nil, fmt.Errorf("invalid duration for deploy window '%s'", w.Schedule)
+			}
+			ret = append(ret, window{schedule: ss, duration: w.Duration.Duration})
+		}
+		return ret, nil
+	}
+
+	var ret DeployWindows
+	var err error
+	ret.allow, err = parse(spec.Allow)
+	if err != nil {
+		return nil, err
+	}
+	ret.deny, err = parse(spec.Deny)
+	if err != nil {
+		return nil, err
+	}
+	return &ret, nil
+}
+
+// activeUntil returns the time at which the window closes if it is open at the given time
+func (w *window) activeUntil(t time.Time) *time.Time {
+	// the first activation after t-duration is the only one that can cover t
+	start := w.schedule.Next(t.Add(-w.duration))
+	if start.IsZero() || start.After(t) {
+		return nil
+	}
+	end := start.Add(w.duration)
+	return &end
+}
+
+// IsAllowed returns true if deployments are allowed at the given time
+func (dw *DeployWindows) IsAllowed(t time.Time) bool {
+	for _, w := range dw.deny {
+		if w.activeUntil(t) != nil {
+			return false
+		}
+	}
+	if len(dw.allow) == 0 {
+		return true
+	}
+	for _, w := range dw.allow {
+		if w.activeUntil(t) != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// NextAllowed returns the earliest time at or after the given time at which deployments are allowed. It returns nil
+// if no such time can be found, e.g. because the deny windows cover all allow windows.
+func (dw *DeployWindows) NextAllowed(t time.Time) *time.Time {
+	cur := t
+	for i := 0; i < maxIterations; i++ {
+		if dw.IsAllowed(cur) {
+			return &cur
+		}
+
+		// find the next point in time at which the result of IsAllowed might change
+		var next time.Time
+		update := func(x time.Time) {
+			if x.After(cur) && (next.IsZero() || x.Before(next)) {
+				next = x
+			}
+		}
+		for _, w := range dw.deny {
+			if end := w.activeUntil(cur); end != nil {
+				update(*end)
+			}
+		}
+		for _, w := range dw.allow {
+			update(w.schedule.Next(cur))
+		}
+		if next.IsZero() {
+			return nil
+		}
+		cur = next
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package deploywindows
+
+import (
+	kluctlv1 "github.com/kluctl/kluctl/v2/api/v1beta1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"testing"
+	"time"
+)
+
+func mustParseTime(t *testing.T, loc *time.Location, s string) time.Time {
+	x, err := time.ParseInLocation("2006-01-02 15:04", s, loc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return x
+}
+
+func buildWindows(t *testing.T, tz string, allow []kluctlv1.DeployWindow, deny []kluctlv1.DeployWindow) *DeployWindows {
+	dw, err := New(&kluctlv1.DeployWindows{
+		TimeZone: tz,
+		Allow:    allow,
+		Deny:     deny,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dw
+}
+
+func TestDeployWindowsNoWindows(t *testing.T) {
+	dw := buildWindows(t, "", nil, nil)
+	now := time.Now()
+	assert.True(t, dw.IsAllowed(now))
+	assert.Equal(t, now, *dw.NextAllowed(now))
+}
+
+func TestDeployWindowsAllow(t *testing.T) {
+	// weekdays from 18:00 to 22:00
+	dw := buildWindows(t, "", []kluctlv1.DeployWindow{
+		{Schedule: "0 18 * * 1-5", Duration: metav1.Duration{Duration: 4 * time.Hour}},
+	}, nil)
+
+	// 2023-06-05 is a Monday
+	assert.False(t, dw.IsAllowed(mustParseTime(t, time.UTC, "2023-06-05 12:00")))
+	assert.True(t, dw.IsAllowed(mustParseTime(t, time.UTC, "2023-06-05 18:00")))
+	assert.True(t, dw.IsAllowed(mustParseTime(t, time.UTC, "2023-06-05 21:59")))
+	assert.False(t, dw.IsAllowed(mustParseTime(t, time.UTC, "2023-06-05 22:00")))
+	assert.False(t, dw.IsAllowed(mustParseTime(t, time.UTC, "2023-06-10 19:00")))
+
+	assert.Equal(t, mustParseTime(t, time.UTC, "2023-06-05 18:00"), *dw.NextAllowed(mustParseTime(t, time.UTC, "2023-06-05 12:00")))
+	assert.Equal(t, mustParseTime(t, time.UTC, "2023-06-12 18:00"), *dw.NextAllowed(mustParseTime(t, time.UTC, "2023-06-09 23:00")))
+}
+
+func TestDeployWindowsDeny(t *testing.T) {
+	// no deployments during business hours
+	dw := buildWindows(t, "", nil, []kluctlv1.DeployWindow{
+		{Schedule: "0 8 * * 1-5", Duration: metav1.Duration{Duration: 10 * time.Hour}},
+	})
+
+	assert.True(t, dw.IsAllowed(mustParseTime(t, time.UTC, "2023-06-05 07:59")))
+	assert.False(t, dw.IsAllowed(mustParseTime(t, time.UTC, "2023-06-05 08:00")))
+	assert.False(t, dw.IsAllowed(mustParseTime(t, time.UTC, "2023-06-05 17:59")))
+	assert.True(t, dw.IsAllowed(mustParseTime(t, time.UTC, "2023-06-05 18:00")))
+	assert.True(t, dw.IsAllowed(mustParseTime(t, time.UTC, "2023-06-10 12:00")))
+
+	assert.Equal(t, mustParseTime(t, time.UTC, "2023-06-05 18:00"), *dw.NextAllowed(mustParseTime(t, time.UTC, "2023-06-05 09:30")))
+}
+
+func TestDeployWindowsAllowAndDeny(t *testing.T) {
+	// allowed every day from 16:00 to 22:00, but never on Fridays
+	dw := buildWindows(t, "", []kluctlv1.DeployWindow{
+		{Schedule: "0 16 * * *", Duration: metav1.Duration{Duration: 6 * time.Hour}},
+	}, []kluctlv1.DeployWindow{
+		{Schedule: "0 0 * * 5", Duration: metav1.Duration{Duration: 24 * time.Hour}},
+	})
+
+	// 2023-06-09 is a Friday
+	assert.True(t, dw.IsAllowed(mustParseTime(t, time.UTC, "2023-06-08 17:00")))
+	assert.False(t, dw.IsAllowed(mustParseTime(t, time.UTC, "2023-06-09 17:00")))
+	assert.Equal(t, mustParseTime(t, time.UTC, "2023-06-10 16:00"), *dw.NextAllowed(mustParseTime(t, time.UTC, "2023-06-09 10:00")))
+
+	// deny covering all allowed windows
+	dw = buildWindows(t, "", []kluctlv1.DeployWindow{
+		{Schedule: "0 16 * * 5", Duration: metav1.Duration{Duration: time.Hour}},
+	}, []kluctlv1.DeployWindow{
+		{Schedule: "0 0 * * 5", Duration: metav1.Duration{Duration: 24 * time.Hour}},
+	})
+	assert.Nil(t, dw.NextAllowed(mustParseTime(t, time.UTC, "2023-06-09 10:00")))
+}
+
+func TestDeployWindowsTimeZone(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip("time zone database not available")
+	}
+
+	dw := buildWindows(t, "Europe/Berlin", []kluctlv1.DeployWindow{
+		{Schedule: "0 18 * * *", Duration: metav1.Duration{Duration: time.Hour}},
+	}, nil)
+
+	// 18:30 in Berlin is 16:30 UTC in summer
+	assert.True(t, dw.IsAllowed(mustParseTime(t, loc, "2023-06-05 18:30")))
+	assert.True(t, dw.IsAllowed(mustParseTime(t, time.UTC, "2023-06-05 16:30")))
+	assert.False(t, dw.IsAllowed(mustParseTime(t, time.UTC, "2023-06-05 18:30")))
+}
+
+func TestDeployWindowsInvalid(t *testing.T) {
+	_, err := New(&kluctlv1.DeployWindows{TimeZone: "Invalid/Zone"})
+	assert.ErrorContains(t, err, "invalid time zone")
+
+	_, err = New(&kluctlv1.DeployWindows{Allow: []kluctlv1.DeployWindow{
+		{Schedule: "invalid", Duration: metav1.Duration{Duration: time.Hour}},
+	}})
+	assert.ErrorContains(t, err, "invalid deploy window schedule")
+
+	_, err = New(&kluctlv1.DeployWindows{Allow: []kluctlv1.DeployWindow{
+		{Schedule: "@every 1h", Duration: metav1.Duration{Duration: time.Hour}},
+	}})
+	assert.ErrorContains(t, err, "only cron expressions are supported")
+
+	_, err = New(&kluctlv1.DeployWindows{Deny: []kluctlv1.DeployWindow{
+		{Schedule: "0 8 * * *"},
+	}})
+	assert.ErrorContains(t, err, "invalid duration")
+}
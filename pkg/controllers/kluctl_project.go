@@ -2,11 +2,14 @@ package controllers
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/kluctl/go-jinja2"
 	"github.com/kluctl/kluctl/v2/pkg/controllers/internal/sops"
 	internal_metrics "github.com/kluctl/kluctl/v2/pkg/controllers/metrics"
+	"github.com/kluctl/kluctl/v2/pkg/git"
 	"github.com/kluctl/kluctl/v2/pkg/helm"
+	"github.com/kluctl/kluctl/v2/pkg/oci"
 	"github.com/kluctl/kluctl/v2/pkg/repocache"
 	"github.com/kluctl/kluctl/v2/pkg/sops/decryptor"
 	"github.com/kluctl/kluctl/v2/pkg/types/result"
@@ -29,6 +32,7 @@ import (
 	"github.com/kluctl/kluctl/v2/pkg/types/k8s"
 	"github.com/kluctl/kluctl/v2/pkg/utils"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
@@ -85,12 +89,20 @@ func prepareProject(ctx context.Context,
 		return nil, err
 	}
 
-	pp.rp, err = r.buildRepoCache(ctx, gitSecret)
+	pp.rp, err = buildRepoCache(ctx, r.SshPool, gitSecret)
 	if err != nil {
 		return nil, err
 	}
 
-	if doCloneSource {
+	if doCloneSource && pp.obj.Spec.Source.Oci != nil {
+		err = pp.pullOciSource(ctx)
+		if err != nil {
+			return nil, err
+		}
+	} else if doCloneSource {
+		if pp.obj.Spec.Source.URL.String() == "" {
+			return nil, fmt.Errorf("either source.url or source.oci must be specified")
+		}
 		rpEntry, err := pp.rp.GetEntry(pp.obj.Spec.Source.URL)
 		if err != nil {
 			return nil, fmt.Errorf("failed clone source: %w", err)
@@ -101,6 +113,11 @@ func prepareProject(ctx context.Context,
 			return nil, err
 		}
 
+		err = pp.verifyGitSource(ctx, rpEntry, gi)
+		if err != nil {
+			return nil, err
+		}
+
 		pp.commit = gi.CheckedOutCommit
 		pp.repoDir = clonedDir
 
@@ -118,6 +135,85 @@ func prepareProject(ctx context.Context,
 	return pp, nil
 }
 
+func (pp *preparedProject) verifyGitSource(ctx context.Context, rpEntry *repocache.CacheEntry, gi git.CheckoutInfo) error {
+	log := ctrl.LoggerFrom(ctx)
+
+	verify := pp.obj.Spec.Source.Verify
+	if verify == nil {
+		return nil
+	}
+
+	keys, err := pp.r.getVerifyKeys(ctx, &pp.obj.Spec.Source, pp.obj.GetNamespace())
+	if err != nil {
+		return err
+	}
+
+	mode := verify.Mode
+	if mode == "" {
+		mode = kluctlv1.VerifyModeHEAD
+	}
+
+	if mode == kluctlv1.VerifyModeTag || mode == kluctlv1.VerifyModeTagAndHEAD {
+		if !strings.HasPrefix(gi.CheckedOutRef, "refs/tags/") {
+			return &sourceVerificationError{err: fmt.Errorf("verify mode %s requires the source ref to be a tag, got %s", mode, gi.CheckedOutRef)}
+		}
+		fp, err := rpEntry.VerifyTag(gi.CheckedOutRef, keys)
+		if err != nil {
+			return &sourceVerificationError{err: err}
+		}
+		log.Info(fmt.Sprintf("Verified tag %s, signed by %s", gi.CheckedOutRef, fp))
+	}
+	if mode == kluctlv1.VerifyModeHEAD || mode == kluctlv1.VerifyModeTagAndHEAD {
+		fp, err := rpEntry.VerifyCommit(gi.CheckedOutCommit, keys)
+		if err != nil {
+			return &sourceVerificationError{err: err}
+		}
+		log.Info(fmt.Sprintf("Verified commit %s, signed by %s", gi.CheckedOutCommit, fp))
+	}
+	return nil
+}
+
+func (pp *preparedProject) pullOciSource(ctx context.Context) error {
+	source := pp.obj.Spec.Source.Oci
+
+	secret, err := pp.r.getOciSecret(ctx, source, pp.obj.GetNamespace())
+	if err != nil {
+		return err
+	}
+	keys, err := pp.r.getVerifyKeys(ctx, &pp.obj.Spec.Source, pp.obj.GetNamespace())
+	if err != nil {
+		return err
+	}
+	rh, err := pp.r.buildOciRegistryHelper(utils.WithTmpBaseDir(ctx, pp.tmpDir), source, secret)
+	if err != nil {
+		return err
+	}
+
+	pp.repoDir = filepath.Join(pp.tmpDir, "oci-source")
+	err = os.MkdirAll(pp.repoDir, 0o700)
+	if err != nil {
+		return err
+	}
+
+	digest, err := oci.PullProject(rh, oci.BuildOciUrl(source.URL, source.Tag, source.Digest), pp.repoDir, keys)
+	if err != nil {
+		if errors.Is(err, oci.ErrVerificationFailed) {
+			return &sourceVerificationError{err: err}
+		}
+		return fmt.Errorf("failed to pull source: %w", err)
+	}
+	pp.commit = digest
+
+	pp.projectDir, err = securejoin.SecureJoin(pp.repoDir, pp.obj.Spec.Source.Path)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(pp.projectDir); err != nil {
+		return fmt.Errorf("kluctlDeployment path not found: %w", err)
+	}
+	return nil
+}
+
 func (pp *preparedProject) cleanup() {
 	_ = os.RemoveAll(pp.tmpDir)
 	if pp.rp != nil {
@@ -645,15 +741,41 @@ func (pt *preparedTarget) handleCommandResult(ctx context.Context, cmdErr error,
 		return cmdErr
 	}
 
+	err := pt.writeCommandResult(ctx, cmdResult)
+
+	summary := cmdResult.BuildSummary()
+
+	log.Info(fmt.Sprintf("command finished with err=%v", cmdErr))
+	defer pt.exportCommandResultMetricsToProm(summary)
+
+	msg := buildCommandResultMessage(commandName, summary)
+
+	warning := false
+	if len(cmdResult.Errors) != 0 {
+		warning = true
+		err = fmt.Errorf("%s failed with %d errors", commandName, len(cmdResult.Errors))
+	}
+	pt.pp.r.event(ctx, pt.pp.obj, warning, msg, nil)
+
+	return err
+}
+
+// writeCommandResult fills in the KluctlDeployment specific command infos and writes the command result to the
+// result store, if one is configured.
+func (pt *preparedTarget) writeCommandResult(ctx context.Context, cmdResult *result.CommandResult) error {
+	log := ctrl.LoggerFrom(ctx)
+
 	cmdResult.Command.Initiator = result.CommandInititiator_KluctlDeployment
 	cmdResult.Command.KluctlDeployment = &result.KluctlDeploymentInfo{
 		Name:      pt.pp.obj.Name,
 		Namespace: pt.pp.obj.Namespace,
 	}
 
-	cmdResult.GitInfo.Url = &pt.pp.obj.Spec.Source.URL
-	cmdResult.GitInfo.Ref = pt.pp.obj.Spec.Source.Ref.String()
-	cmdResult.ProjectKey.GitRepoKey = pt.pp.obj.Spec.Source.URL.RepoKey()
+	if pt.pp.obj.Spec.Source.Oci == nil {
+		cmdResult.GitInfo.Url = &pt.pp.obj.Spec.Source.URL
+		cmdResult.GitInfo.Ref = pt.pp.obj.Spec.Source.Ref.String()
+	}
+	cmdResult.ProjectKey.GitRepoKey = buildSourceRepoKey(&pt.pp.obj.Spec.Source)
 
 	var err error
 	if pt.pp.r.ResultStore != nil {
@@ -663,12 +785,10 @@ func (pt *preparedTarget) handleCommandResult(ctx context.Context, cmdErr error,
 			log.Error(err, "Writing command result failed")
 		}
 	}
+	return err
+}
 
-	summary := cmdResult.BuildSummary()
-
-	log.Info(fmt.Sprintf("command finished with err=%v", cmdErr))
-	defer pt.exportCommandResultMetricsToProm(summary)
-
+func buildCommandResultMessage(commandName string, summary *result.CommandResultSummary) string {
 	msg := fmt.Sprintf("%s succeeded.", commandName)
 	if summary.NewObjects != 0 {
 		msg += fmt.Sprintf(" %d new objects.", summary.NewObjects)
@@ -685,21 +805,13 @@ func (pt *preparedTarget) handleCommandResult(ctx context.Context, cmdErr error,
 	if summary.OrphanObjects != 0 {
 		msg += fmt.Sprintf(" %d orphan objects.", summary.OrphanObjects)
 	}
-	if len(cmdResult.Errors) != 0 {
-		msg += fmt.Sprintf(" %d errors.", len(cmdResult.Errors))
+	if len(summary.Errors) != 0 {
+		msg += fmt.Sprintf(" %d errors.", len(summary.Errors))
 	}
-	if len(cmdResult.Warnings) != 0 {
-		msg += fmt.Sprintf(" %d warnings.", len(cmdResult.Warnings))
+	if len(summary.Warnings) != 0 {
+		msg += fmt.Sprintf(" %d warnings.", len(summary.Warnings))
 	}
-
-	warning := false
-	if len(cmdResult.Errors) != 0 {
-		warning = true
-		err = fmt.Errorf("%s failed with %d errors", commandName, len(cmdResult.Errors))
-	}
-	pt.pp.r.event(ctx, pt.pp.obj, warning, msg, nil)
-
-	return err
+	return msg
 }
 
 func (pt *preparedTarget) kluctlDeploy(ctx context.Context, targetContext *kluctl_project.TargetContext) (*result.CommandResult, error) {
@@ -711,6 +823,10 @@ func (pt *preparedTarget) kluctlDeploy(ctx context.Context, targetContext *kluct
 	cmd.ForceReplaceOnError = pt.pp.obj.Spec.ForceReplaceOnError
 	cmd.AbortOnError = pt.pp.obj.Spec.AbortOnError
 	cmd.ReadinessTimeout = time.Minute * 10
+	cmd.ApplyConcurrency = pt.pp.obj.Spec.ApplyConcurrency
+	cmd.RollbackOnError = pt.pp.obj.Spec.Rollback == kluctlv1.KluctlRollbackOnError
+	cmd.ResultStore = pt.pp.r.ResultStore
+	cmd.ProjectKey = pt.pp.obj.Status.ProjectKey
 	cmd.NoWait = pt.pp.obj.Spec.NoWait
 	cmd.Prune = pt.pp.obj.Spec.Prune
 	cmd.WaitPrune = false
@@ -724,6 +840,7 @@ func (pt *preparedTarget) kluctlPokeImages(ctx context.Context, targetContext *k
 	timer := prometheus.NewTimer(internal_metrics.NewKluctlDeploymentDuration(pt.pp.obj.ObjectMeta.Namespace, pt.pp.obj.ObjectMeta.Name, pt.pp.obj.Spec.DeployMode))
 	defer timer.ObserveDuration()
 	cmd := commands.NewPokeImagesCommand(targetContext)
+	cmd.ApplyConcurrency = pt.pp.obj.Spec.ApplyConcurrency
 
 	cmdResult, err := cmd.Run()
 	err = pt.handleCommandResult(ctx, err, cmdResult, "poke-images")
@@ -739,11 +856,67 @@ func (pt *preparedTarget) kluctlValidate(ctx context.Context, targetContext *klu
 		c = nil
 	}
 	cmd := commands.NewValidateCommand(ctx, targetContext.Target.Discriminator, c, cmdResult)
+	cmd.ApplyConcurrency = pt.pp.obj.Spec.ApplyConcurrency
 
 	validateResult, err := cmd.Run(ctx, targetContext.SharedContext.K)
 	return validateResult, err
 }
 
+func (pt *preparedTarget) kluctlDriftDetection(ctx context.Context, targetContext *kluctl_project.TargetContext) (*result.DriftDetectionResult, error) {
+	timer := prometheus.NewTimer(internal_metrics.NewKluctlDriftDetectionDuration(pt.pp.obj.ObjectMeta.Namespace, pt.pp.obj.ObjectMeta.Name))
+	defer timer.ObserveDuration()
+
+	cmd := commands.NewDiffCommand(targetContext)
+	cmd.ForceApply = pt.pp.obj.Spec.ForceApply
+	cmd.ReplaceOnError = pt.pp.obj.Spec.ReplaceOnError
+	cmd.ForceReplaceOnError = pt.pp.obj.Spec.ForceReplaceOnError
+	cmd.ApplyConcurrency = pt.pp.obj.Spec.ApplyConcurrency
+
+	startTime := metav1.Now()
+	cmdResult, err := cmd.Run()
+	if err != nil {
+		pt.pp.r.event(ctx, pt.pp.obj, true, fmt.Sprintf("drift detection failed. %s", err.Error()), nil)
+		// return an empty result so that the next drift detection is scheduled properly
+		return &result.DriftDetectionResult{
+			StartTime: startTime,
+			EndTime:   metav1.Now(),
+		}, err
+	}
+
+	driftResult := cmdResult.BuildDriftDetectionResult()
+	pt.exportDriftDetectionMetricsToProm(driftResult)
+
+	for _, o := range driftResult.Objects {
+		pt.pp.r.event(ctx, pt.pp.obj, true, fmt.Sprintf("drift detected for %s: %d changes", o.Ref.String(), o.Changes), nil)
+	}
+	for _, ref := range driftResult.MissingObjects {
+		pt.pp.r.event(ctx, pt.pp.obj, true, fmt.Sprintf("drift detected for %s: object does not exist", ref.String()), nil)
+	}
+
+	if len(driftResult.Errors) != 0 {
+		return driftResult, fmt.Errorf("drift detection failed with %d errors", len(driftResult.Errors))
+	}
+	return driftResult, nil
+}
+
+func (pt *preparedTarget) kluctlDiff(ctx context.Context, targetContext *kluctl_project.TargetContext) (*result.CommandResult, error) {
+	cmd := commands.NewDiffCommand(targetContext)
+	cmd.ForceApply = pt.pp.obj.Spec.ForceApply
+	cmd.ReplaceOnError = pt.pp.obj.Spec.ReplaceOnError
+	cmd.ForceReplaceOnError = pt.pp.obj.Spec.ForceReplaceOnError
+	cmd.ApplyConcurrency = pt.pp.obj.Spec.ApplyConcurrency
+
+	cmdResult, err := cmd.Run()
+	if err != nil {
+		pt.pp.r.event(ctx, pt.pp.obj, true, fmt.Sprintf("diff failed. %s", err.Error()), nil)
+		return nil, err
+	}
+	if len(cmdResult.Errors) != 0 {
+		return cmdResult, fmt.Errorf("diff failed with %d errors", len(cmdResult.Errors))
+	}
+	return cmdResult, nil
+}
+
 func (pt *preparedTarget) kluctlDelete(ctx context.Context, discriminator string) (*result.CommandResult, error) {
 	if !pt.pp.obj.Spec.Delete {
 		return nil, nil
@@ -810,3 +983,15 @@ func (pt *preparedTarget) exportCommandResultMetricsToProm(summary *result.Comma
 	internal_metrics.NewKluctlNumberOfWarnings(pt.pp.obj.Namespace, pt.pp.obj.Name, summary.Command.Command).Set(float64(len(summary.Warnings)))
 	internal_metrics.NewKluctlNumberOfErrors(pt.pp.obj.Namespace, pt.pp.obj.Name, summary.Command.Command).Set(float64(len(summary.Errors)))
 }
+
+func (pt *preparedTarget) exportDriftDetectionMetricsToProm(dr *result.DriftDetectionResult) {
+	ns, name := pt.pp.obj.Namespace, pt.pp.obj.Name
+	internal_metrics.NewKluctlNumberOfDriftedObjects(ns, name).Set(float64(len(dr.Objects) + len(dr.MissingObjects)))
+	internal_metrics.DeleteKluctlDriftedObjects(ns, name)
+	for _, o := range dr.Objects {
+		internal_metrics.NewKluctlDriftedObject(ns, name, o.Ref.Group, o.Ref.Kind, o.Ref.Namespace, o.Ref.Name).Set(1.0)
+	}
+	for _, ref := range dr.MissingObjects {
+		internal_metrics.NewKluctlDriftedObject(ns, name, ref.Group, ref.Kind, ref.Namespace, ref.Name).Set(1.0)
+	}
+}
@@ -45,6 +45,9 @@ type KluctlDeploymentReconciler struct {
 	DefaultServiceAccount string
 	DryRun                bool
 
+	// WebuiUrl is the base URL of the webui, used to build links to command results in notifications
+	WebuiUrl string
+
 	SshPool *ssh_pool.SshPool
 
 	ResultStore results.ResultStore
@@ -52,7 +55,8 @@ type KluctlDeploymentReconciler struct {
 
 // KluctlDeploymentReconcilerOpts contains options for the BaseReconciler.
 type KluctlDeploymentReconcilerOpts struct {
-	HTTPRetry int
+	HTTPRetry                 int
+	DependencyRequeueInterval time.Duration
 }
 
 // +kubebuilder:rbac:groups=gitops.kluctl.io,resources=kluctldeployments,verbs=get;list;watch;create;update;patch;delete
@@ -116,6 +120,23 @@ func (r *KluctlDeploymentReconciler) Reconcile(ctx context.Context, req ctrl.Req
 	}
 
 	patch := client.MergeFrom(obj.DeepCopy())
+
+	// check dependencies and requeue without failing if any of them is not ready yet
+	if len(obj.Spec.DependsOn) != 0 {
+		if err := r.checkDependencies(ctx, obj); err != nil {
+			setReadiness(obj, metav1.ConditionFalse, kluctlv1.DependencyNotReadyReason, err.Error())
+			if err := r.Status().Patch(ctx, obj, patch, client.FieldOwner(r.ControllerName)); err != nil {
+				return ctrl.Result{Requeue: true}, err
+			}
+			r.recordReadiness(ctx, obj)
+
+			log.Info(fmt.Sprintf("Dependencies do not meet ready condition, retrying in %s: %s",
+				r.requeueDependency.String(), err.Error()))
+			return ctrl.Result{RequeueAfter: r.requeueDependency}, nil
+		}
+		log.Info("All dependencies are ready, proceeding with reconciliation")
+	}
+
 	// reconcile kluctlDeployment by applying the latest revision
 	ctrlResult, reconcileErr := r.doReconcile(ctx, obj)
 	if err := r.Status().Patch(ctx, obj, patch, client.FieldOwner(r.ControllerName)); err != nil {
@@ -213,8 +234,17 @@ func (r *KluctlDeploymentReconciler) doReconcile(
 	obj.Status.ObservedGeneration = obj.GetGeneration()
 	obj.Status.LastHandledDeployAt = curDeployRequest
 
+	deployWindows, err := buildDeployWindows(obj)
+	if err != nil {
+		return doFailPrepare(err)
+	}
+
 	pp, err := prepareProject(ctx, r, obj, true)
 	if err != nil {
+		var verr *sourceVerificationError
+		if errors2.As(err, &verr) {
+			return doFail(kluctlv1.SourceVerificationFailedReason, err)
+		}
 		return doFailPrepare(err)
 	}
 	defer pp.cleanup()
@@ -240,7 +270,7 @@ func (r *KluctlDeploymentReconciler) doReconcile(
 	}
 
 	obj.Status.ProjectKey = &result.ProjectKey{
-		GitRepoKey: obj.Spec.Source.URL.RepoKey(),
+		GitRepoKey: buildSourceRepoKey(&obj.Spec.Source),
 		SubDir:     path.Clean(obj.Spec.Source.Path),
 	}
 	obj.Status.TargetKey = &result.TargetKey{
@@ -294,8 +324,69 @@ func (r *KluctlDeploymentReconciler) doReconcile(
 
 	obj.Status.LastObjectsHash = objectsHash
 
+	if obj.Spec.DriftDetection != nil {
+		// either never performed drift detection before or it's time for the next one
+		nextDriftDetectionTime := r.nextDriftDetectionTime(obj)
+		needDriftDetection := nextDriftDetectionTime == nil || nextDriftDetectionTime.Before(time.Now())
+		if needDeploy {
+			// the deployment will correct drift anyway
+			needDriftDetection = false
+		}
+
+		if needDriftDetection {
+			driftResult, err := pt.kluctlDriftDetection(ctx, targetContext)
+			r.updateDriftStatus(ctx, obj, driftResult, err)
+			r.notifyDriftDetectionResult(ctx, obj, j2, driftResult, err)
+			if err == nil && driftResult.HasDrift() && obj.Spec.DriftDetection.AutoCorrect {
+				log.Info("Drift detected, performing a deployment to correct it")
+				needDeploy = true
+				if obj.Spec.Validate {
+					needValidate = true
+				}
+			}
+		}
+	} else {
+		obj.Status.LastDriftDetectionResult = nil
+		obj.Status.LastDriftDetectionError = ""
+		meta2.RemoveStatusCondition(&obj.Status.Conditions, kluctlv1.DriftedCondition)
+		internal_metrics.DeleteKluctlDriftedObjects(obj.Namespace, obj.Name)
+	}
+
+	if obj.Status.DeployDeferred {
+		// a previous deployment was deferred due to the deploy windows
+		needDeploy = true
+	}
+
+	if obj.Spec.Approval {
+		if obj.Status.PendingApproval != nil {
+			// a previous diff is still waiting for approval
+			needDeploy = true
+		}
+		if needDeploy {
+			approved, err := r.checkApproval(ctx, obj, pt, targetContext, objectsHash)
+			if err != nil {
+				// forget the objects hash so that the diff is retried on the next reconciliation
+				obj.Status.LastObjectsHash = ""
+				return doFail(kluctlv1.ApprovalFailedReason, err)
+			}
+			needDeploy = approved
+		}
+	} else {
+		obj.Status.PendingApproval = nil
+		meta2.RemoveStatusCondition(&obj.Status.Conditions, kluctlv1.AwaitingApprovalCondition)
+	}
+
+	deferredMsg := ""
+	if needDeploy && deployWindows != nil && !deployWindows.IsAllowed(time.Now()) {
+		log.Info("Deployment is outside of the deploy windows, deferring it")
+		needDeploy = false
+		deferredMsg = r.deferDeployment(ctx, obj, pt, targetContext, deployWindows)
+	}
+
 	var deployResult *result.CommandResult
 	if needDeploy {
+		obj.Status.DeployDeferred = false
+
 		// deploy the kluctl project
 		if obj.Spec.DeployMode == kluctlv1.KluctlDeployModeFull {
 			deployResult, err = pt.kluctlDeploy(ctx, targetContext)
@@ -304,14 +395,24 @@ func (r *KluctlDeploymentReconciler) doReconcile(
 		} else {
 			err = fmt.Errorf("deployMode '%s' not supported", obj.Spec.DeployMode)
 		}
+		deployErr := err
+		obj.Status.LastDeployedObjectsHash = objectsHash
 		err = obj.Status.SetLastDeployResult(deployResult.BuildSummary(), err)
 		if err != nil {
 			log.Error(err, "Failed to write deploy result")
 		}
+		if deployErr == nil {
+			r.clearApproval(obj)
+			r.checkDriftCorrected(obj)
+		}
+		r.notifyDeployResult(ctx, obj, j2, deployResult, deployErr)
+		r.reportDeployCommitStatus(ctx, obj, deployResult, deployErr)
 	}
 
 	if needValidate {
 		validateResult, err := pt.kluctlValidate(ctx, targetContext, deployResult)
+		r.notifyValidateResult(ctx, obj, j2, validateResult, err)
+		r.reportValidateCommitStatus(ctx, obj, deployResult, validateResult, err)
 		err = obj.Status.SetLastValidateResult(validateResult, err)
 		if err != nil {
 			log.Error(err, "Failed to write validate result")
@@ -331,6 +432,17 @@ func (r *KluctlDeploymentReconciler) doReconcile(
 		internal_metrics.NewKluctlLastObjectStatus(obj.Namespace, obj.Name).Set(0.0)
 		return &ctrlResult, fmt.Errorf(finalStatus)
 	}
+	if deferredMsg != "" {
+		setReadiness(obj, metav1.ConditionFalse, kluctlv1.OutsideDeployWindowReason, deferredMsg)
+		internal_metrics.NewKluctlLastObjectStatus(obj.Namespace, obj.Name).Set(0.0)
+		return &ctrlResult, nil
+	}
+	if obj.Status.PendingApproval != nil {
+		setReadiness(obj, metav1.ConditionFalse, kluctlv1.ApprovalPendingReason,
+			fmt.Sprintf("waiting for approval of diff %s", obj.Status.PendingApproval.ResultId))
+		internal_metrics.NewKluctlLastObjectStatus(obj.Namespace, obj.Name).Set(0.0)
+		return &ctrlResult, nil
+	}
 	setReadiness(obj, metav1.ConditionTrue, reason, finalStatus)
 	internal_metrics.NewKluctlLastObjectStatus(obj.Namespace, obj.Name).Set(1.0)
 	return &ctrlResult, nil
@@ -430,10 +542,34 @@ func (r *KluctlDeploymentReconciler) nextReconcileTime(obj *kluctlv1.KluctlDeplo
 	if obj.Spec.Validate && t3 != nil && t3.Before(t1) {
 		t1 = *t3
 	}
+	t4 := r.nextDriftDetectionTime(obj)
+	if t4 != nil && t4.Before(t1) {
+		t1 = *t4
+	}
 	return t1
 }
 
 func (r *KluctlDeploymentReconciler) nextDeployTime(obj *kluctlv1.KluctlDeployment) *time.Time {
+	var t *time.Time
+	if obj.Status.DeployDeferred {
+		// a deferred deployment must be performed as soon as the deploy windows allow it
+		now := time.Now()
+		t = &now
+	} else {
+		t = r.nextPeriodicDeployTime(obj)
+	}
+	if t == nil {
+		return nil
+	}
+
+	deployWindows, err := buildDeployWindows(obj)
+	if err != nil || deployWindows == nil {
+		return t
+	}
+	return deployWindows.NextAllowed(*t)
+}
+
+func (r *KluctlDeploymentReconciler) nextPeriodicDeployTime(obj *kluctlv1.KluctlDeployment) *time.Time {
 	if obj.Status.LastDeployResult == nil {
 		// was never deployed before. Return early.
 		return nil
@@ -473,6 +609,54 @@ func (r *KluctlDeploymentReconciler) nextValidateTime(obj *kluctlv1.KluctlDeploy
 	return &t
 }
 
+func (r *KluctlDeploymentReconciler) nextDriftDetectionTime(obj *kluctlv1.KluctlDeployment) *time.Time {
+	if obj.Spec.DriftDetection == nil {
+		// drift detection disabled
+		return nil
+	}
+
+	lastDriftResult, err := obj.Status.GetLastDriftDetectionResult()
+	if err != nil || lastDriftResult == nil {
+		// no drift detection performed before. Return early.
+		return nil
+	}
+
+	t := lastDriftResult.EndTime.Add(obj.Spec.DriftDetection.Interval.Duration)
+	return &t
+}
+
+func (r *KluctlDeploymentReconciler) updateDriftStatus(ctx context.Context, obj *kluctlv1.KluctlDeployment, driftResult *result.DriftDetectionResult, driftErr error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	err := obj.Status.SetLastDriftDetectionResult(driftResult, driftErr)
+	if err != nil {
+		log.Error(err, "Failed to write drift detection result")
+	}
+
+	n := len(driftResult.Objects) + len(driftResult.MissingObjects)
+	if driftErr != nil {
+		setDrifted(obj, metav1.ConditionUnknown, kluctlv1.DriftDetectionFailedReason, driftErr.Error())
+	} else if n != 0 {
+		setDrifted(obj, metav1.ConditionTrue, kluctlv1.DriftDetectedReason, fmt.Sprintf("%d drifted objects", n))
+	} else {
+		setDrifted(obj, metav1.ConditionFalse, kluctlv1.NoDriftReason, "no drift detected")
+	}
+}
+
+// checkDriftCorrected marks previously detected drift as corrected after a successful full deployment
+func (r *KluctlDeploymentReconciler) checkDriftCorrected(obj *kluctlv1.KluctlDeployment) {
+	if obj.Spec.DriftDetection == nil || obj.Spec.DeployMode != kluctlv1.KluctlDeployModeFull || obj.Spec.DryRun || r.DryRun {
+		return
+	}
+	c := meta2.FindStatusCondition(obj.Status.Conditions, kluctlv1.DriftedCondition)
+	if c == nil || c.Status != metav1.ConditionTrue {
+		return
+	}
+	setDrifted(obj, metav1.ConditionFalse, kluctlv1.DriftCorrectedReason, "drift corrected by deployment")
+	internal_metrics.NewKluctlNumberOfDriftedObjects(obj.Namespace, obj.Name).Set(0)
+	internal_metrics.DeleteKluctlDriftedObjects(obj.Namespace, obj.Name)
+}
+
 func (r *KluctlDeploymentReconciler) finalize(ctx context.Context, obj *kluctlv1.KluctlDeployment) (ctrl.Result, error) {
 	r.doFinalize(ctx, obj)
 
@@ -581,5 +765,13 @@ func (r *KluctlDeploymentReconciler) exportDeploymentObjectToProm(obj *kluctlv1.
 	internal_metrics.NewKluctlDeleteEnabled(obj.Namespace, obj.Name).Set(deleteEnabled)
 	internal_metrics.NewKluctlDryRunEnabled(obj.Namespace, obj.Name).Set(dryRunEnabled)
 	internal_metrics.NewKluctlDeploymentInterval(obj.Namespace, obj.Name).Set(deploymentInterval)
-	internal_metrics.NewKluctlSourceSpec(obj.Namespace, obj.Name, obj.Spec.Source.URL.String(), obj.Spec.Source.Path, obj.Spec.Source.Ref.String()).Set(0.0)
+	if obj.Spec.Source.Oci != nil {
+		ref := obj.Spec.Source.Oci.Tag
+		if obj.Spec.Source.Oci.Digest != "" {
+			ref = obj.Spec.Source.Oci.Digest
+		}
+		internal_metrics.NewKluctlSourceSpec(obj.Namespace, obj.Name, obj.Spec.Source.Oci.URL, obj.Spec.Source.Path, ref).Set(0.0)
+	} else {
+		internal_metrics.NewKluctlSourceSpec(obj.Namespace, obj.Name, obj.Spec.Source.URL.String(), obj.Spec.Source.Path, obj.Spec.Source.Ref.String()).Set(0.0)
+	}
 }
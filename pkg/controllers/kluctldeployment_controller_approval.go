@@ -0,0 +1,81 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	kluctlv1 "github.com/kluctl/kluctl/v2/api/v1beta1"
+	"github.com/kluctl/kluctl/v2/pkg/kluctl_project"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// clearApproval is called after an approved diff has been deployed successfully. A failed deployment keeps the
+// approval, so that the deployment is retried on the next reconciliation without requiring another approval.
+func (r *KluctlDeploymentReconciler) clearApproval(obj *kluctlv1.KluctlDeployment) {
+	pa := obj.Status.PendingApproval
+	if pa == nil {
+		return
+	}
+	obj.Status.PendingApproval = nil
+	setAwaitingApproval(obj, metav1.ConditionFalse, kluctlv1.ApprovedReason, fmt.Sprintf("diff %s has been approved", pa.ResultId))
+}
+
+// checkApproval decides if a pending deployment may be performed when the approval mode is enabled. If no
+// approved diff exists, a new diff is performed and stored as pending approval. Deployments are only allowed after
+// the pending diff got approved via the approve annotation or if the diff is empty.
+func (r *KluctlDeploymentReconciler) checkApproval(ctx context.Context, obj *kluctlv1.KluctlDeployment, pt *preparedTarget, targetContext *kluctl_project.TargetContext, objectsHash string) (bool, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	pa := obj.Status.PendingApproval
+	if pa != nil && (pa.Commit != obj.Status.ObservedCommit || pa.ObjectsHash != objectsHash) {
+		r.event(ctx, obj, false, fmt.Sprintf("Pending approval for diff %s expired as the source has changed", pa.ResultId), nil)
+		obj.Status.PendingApproval = nil
+		pa = nil
+	}
+
+	if pa != nil {
+		if obj.GetAnnotations()[kluctlv1.KluctlApproveDeployAnnotation] != pa.ResultId {
+			log.Info(fmt.Sprintf("Diff %s is still waiting for approval", pa.ResultId))
+			return false, nil
+		}
+		// the pending approval is only cleared after the deployment has succeeded, see clearApproval
+		log.Info(fmt.Sprintf("Diff %s has been approved", pa.ResultId))
+		return true, nil
+	}
+
+	cmdResult, err := pt.kluctlDiff(ctx, targetContext)
+	if cmdResult != nil {
+		// failing to write the result is not fatal, as the approval only requires the result id
+		_ = pt.writeCommandResult(ctx, cmdResult)
+	}
+	if err != nil {
+		setAwaitingApproval(obj, metav1.ConditionUnknown, kluctlv1.ApprovalFailedReason, err.Error())
+		return false, err
+	}
+
+	summary := cmdResult.BuildSummary()
+	changes := summary.NewObjects + summary.ChangedObjects + summary.DeletedObjects
+	if obj.Spec.Prune {
+		changes += summary.OrphanObjects
+	}
+	if changes == 0 {
+		setAwaitingApproval(obj, metav1.ConditionFalse, kluctlv1.NoChangesReason, "diff is empty, no approval required")
+		return true, nil
+	}
+
+	obj.Status.PendingApproval = &kluctlv1.PendingApproval{
+		ResultId:    cmdResult.Id,
+		Commit:      obj.Status.ObservedCommit,
+		ObjectsHash: objectsHash,
+		RequestedAt: metav1.Now(),
+	}
+
+	msg := fmt.Sprintf("diff %s is waiting for approval: %d new objects, %d changed objects, %d deleted objects",
+		cmdResult.Id, summary.NewObjects, summary.ChangedObjects, summary.DeletedObjects)
+	if obj.Spec.Prune {
+		msg += fmt.Sprintf(", %d orphan objects", summary.OrphanObjects)
+	}
+	setAwaitingApproval(obj, metav1.ConditionTrue, kluctlv1.ApprovalPendingReason, msg)
+	r.event(ctx, obj, false, msg, nil)
+	return false, nil
+}
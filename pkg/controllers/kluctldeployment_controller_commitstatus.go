@@ -0,0 +1,131 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	kluctlv1 "github.com/kluctl/kluctl/v2/api/v1beta1"
+	"github.com/kluctl/kluctl/v2/pkg/controllers/commitstatus"
+	"github.com/kluctl/kluctl/v2/pkg/types/result"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"strings"
+)
+
+func (r *KluctlDeploymentReconciler) getCommitStatusToken(ctx context.Context, obj *kluctlv1.KluctlDeployment) (string, error) {
+	cs := obj.Spec.CommitStatus
+	name := types.NamespacedName{
+		Namespace: obj.GetNamespace(),
+		Name:      cs.SecretRef.Name,
+	}
+	var secret corev1.Secret
+	if err := r.Get(ctx, name, &secret); err != nil {
+		return "", fmt.Errorf("failed to get secret '%s': %w", name.String(), err)
+	}
+
+	key := cs.SecretRef.Key
+	if key == "" {
+		key = "token"
+	}
+	token, ok := secret.Data[key]
+	if !ok {
+		return "", fmt.Errorf("secret '%s' does not contain a '%s' key", name.String(), key)
+	}
+	return strings.TrimSpace(string(token)), nil
+}
+
+// sendCommitStatus reports the status for the observed commit. Failures are only logged and reported as events, as
+// they should not fail the reconciliation. The status is sent in the background, as the retrying http client might
+// wait for minutes if the Git forge is not reachable.
+func (r *KluctlDeploymentReconciler) sendCommitStatus(ctx context.Context, obj *kluctlv1.KluctlDeployment, kind string, s *commitstatus.Status) {
+	log := ctrl.LoggerFrom(ctx)
+
+	cs := obj.Spec.CommitStatus
+	if cs == nil || obj.Spec.Source.Oci != nil || obj.Status.ObservedCommit == "" {
+		return
+	}
+
+	prefix := cs.Context
+	if prefix == "" {
+		prefix = fmt.Sprintf("kluctl/%s/%s", obj.GetNamespace(), obj.GetName())
+	}
+	s.Context = fmt.Sprintf("%s/%s", prefix, kind)
+
+	token, err := r.getCommitStatusToken(ctx, obj)
+	if err != nil {
+		log.Error(err, "Failed to send commit status")
+		r.event(ctx, obj, true, fmt.Sprintf("failed to send %s commit status. %s", cs.Provider, err.Error()), nil)
+		return
+	}
+
+	// the object is modified by the reconciliation while the commit status is being sent
+	objCopy := obj.DeepCopy()
+	runInBackground(ctx, func(ctx context.Context) {
+		cs := objCopy.Spec.CommitStatus
+		err := commitstatus.Send(ctx, r.httpClient, cs, &objCopy.Spec.Source.URL, token, objCopy.Status.ObservedCommit, s)
+		if err != nil {
+			ctrl.LoggerFrom(ctx).Error(err, "Failed to send commit status")
+			r.event(ctx, objCopy, true, fmt.Sprintf("failed to send %s commit status. %s", cs.Provider, err.Error()), nil)
+		}
+	})
+}
+
+func (r *KluctlDeploymentReconciler) reportDeployCommitStatus(ctx context.Context, obj *kluctlv1.KluctlDeployment, deployResult *result.CommandResult, deployErr error) {
+	if obj.Spec.CommitStatus == nil {
+		return
+	}
+
+	summary := deployResult.BuildSummary()
+	s := &commitstatus.Status{}
+	if summary != nil {
+		s.TargetUrl = r.buildResultUrl(summary.Id)
+	}
+	if deployErr != nil {
+		s.State = commitstatus.StateFailure
+		s.Description = deployErr.Error()
+	} else if summary != nil {
+		s.State = commitstatus.StateSuccess
+		s.Description = buildCommandResultMessage(summary.Command.Command, summary)
+	} else {
+		return
+	}
+	r.sendCommitStatus(ctx, obj, "deploy", s)
+}
+
+func (r *KluctlDeploymentReconciler) reportValidateCommitStatus(ctx context.Context, obj *kluctlv1.KluctlDeployment, deployResult *result.CommandResult, validateResult *result.ValidateResult, validateErr error) {
+	if obj.Spec.CommitStatus == nil {
+		return
+	}
+	if obj.Status.DeployDeferred || obj.Status.PendingApproval != nil {
+		// the validated objects do not belong to the observed commit yet
+		return
+	}
+
+	s := &commitstatus.Status{}
+
+	// validate results are not stored in the result store, so we link to the deploy result of the validated commit
+	var summary *result.CommandResultSummary
+	if deployResult != nil {
+		summary = deployResult.BuildSummary()
+	} else {
+		summary, _ = obj.Status.GetLastDeployResult()
+	}
+	if summary != nil {
+		s.TargetUrl = r.buildResultUrl(summary.Id)
+	}
+
+	if validateErr != nil {
+		s.State = commitstatus.StateError
+		s.Description = validateErr.Error()
+	} else if len(validateResult.Errors) != 0 {
+		s.State = commitstatus.StateFailure
+		s.Description = fmt.Sprintf("validate failed with %d errors.", len(validateResult.Errors))
+	} else if !validateResult.Ready {
+		s.State = commitstatus.StateFailure
+		s.Description = "validate failed. Not all objects are ready."
+	} else {
+		s.State = commitstatus.StateSuccess
+		s.Description = "validate succeeded."
+	}
+	r.sendCommitStatus(ctx, obj, "validate", s)
+}
@@ -0,0 +1,126 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	kluctlv1 "github.com/kluctl/kluctl/v2/api/v1beta1"
+	"github.com/kluctl/kluctl/v2/pkg/utils/flux_utils/meta"
+	"k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"strings"
+)
+
+// checkDependencies verifies that all deployments listed in dependsOn are ready for their current generation and
+// have deployed their latest commit. It returns an error describing the first dependency that is not ready or the
+// dependency cycle the KluctlDeployment is part of.
+func (r *KluctlDeploymentReconciler) checkDependencies(ctx context.Context, obj *kluctlv1.KluctlDeployment) error {
+	objKey := types.NamespacedName{Name: obj.Name, Namespace: obj.Namespace}
+
+	for _, d := range obj.Spec.DependsOn {
+		key := buildDependencyKey(obj, d)
+		if key == objKey {
+			return fmt.Errorf("KluctlDeployment can not depend on itself")
+		}
+
+		var dep kluctlv1.KluctlDeployment
+		err := r.Get(ctx, key, &dep)
+		if err != nil {
+			return fmt.Errorf("unable to get dependency '%s': %w", key.String(), err)
+		}
+
+		cycle, err := r.findDependencyCycle(ctx, objKey, &dep, []types.NamespacedName{objKey, key}, map[types.NamespacedName]bool{})
+		if err != nil {
+			return err
+		}
+		if cycle != nil {
+			var s []string
+			for _, k := range cycle {
+				s = append(s, k.String())
+			}
+			return fmt.Errorf("dependency cycle detected: %s", strings.Join(s, " -> "))
+		}
+
+		if !isDependencyReady(&dep) {
+			return fmt.Errorf("dependency '%s' is not ready", key.String())
+		}
+		if !isDependencyDeployed(&dep) {
+			return fmt.Errorf("dependency '%s' has not deployed its latest commit yet", key.String())
+		}
+	}
+	return nil
+}
+
+func buildDependencyKey(obj *kluctlv1.KluctlDeployment, d kluctlv1.DependencyReference) types.NamespacedName {
+	key := types.NamespacedName{
+		Name:      d.Name,
+		Namespace: d.Namespace,
+	}
+	if key.Namespace == "" {
+		key.Namespace = obj.Namespace
+	}
+	return key
+}
+
+// findDependencyCycle walks the dependencies of dep and returns the path that leads back to start, if any.
+// Dependencies that do not exist are ignored here, as these are reported by the reconciliation of the
+// KluctlDeployment that depends on them.
+func (r *KluctlDeploymentReconciler) findDependencyCycle(ctx context.Context, start types.NamespacedName, dep *kluctlv1.KluctlDeployment, path []types.NamespacedName, visited map[types.NamespacedName]bool) ([]types.NamespacedName, error) {
+	for _, d := range dep.Spec.DependsOn {
+		key := buildDependencyKey(dep, d)
+		if key == start {
+			return append(path, key), nil
+		}
+		if visited[key] {
+			continue
+		}
+		visited[key] = true
+
+		var next kluctlv1.KluctlDeployment
+		err := r.Get(ctx, key, &next)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return nil, fmt.Errorf("unable to get dependency '%s': %w", key.String(), err)
+		}
+
+		cycle, err := r.findDependencyCycle(ctx, start, &next, append(path, key), visited)
+		if err != nil || cycle != nil {
+			return cycle, err
+		}
+	}
+	return nil, nil
+}
+
+func isDependencyReady(dep *kluctlv1.KluctlDeployment) bool {
+	if dep.Spec.Suspend {
+		// a suspended dependency is considered ready as long as its last reconciliation was successful
+		c := apimeta.FindStatusCondition(dep.Status.Conditions, meta.ReadyCondition)
+		return c != nil && c.Status == metav1.ConditionTrue
+	}
+	if dep.Generation != dep.Status.ObservedGeneration {
+		return false
+	}
+	c := apimeta.FindStatusCondition(dep.Status.Conditions, meta.ReadyCondition)
+	if c == nil || c.Status != metav1.ConditionTrue {
+		return false
+	}
+	return c.ObservedGeneration == dep.Generation
+}
+
+// isDependencyDeployed checks that the last deployment of dep was performed with the last observed commit. As no
+// deployment is performed for commits that do not change the rendered objects, an unchanged objects hash is
+// sufficient as well.
+func isDependencyDeployed(dep *kluctlv1.KluctlDeployment) bool {
+	lastDeployResult, err := dep.Status.GetLastDeployResult()
+	if err != nil || lastDeployResult == nil {
+		return false
+	}
+	if lastDeployResult.GitInfo.Commit == "" || lastDeployResult.GitInfo.Commit == dep.Status.ObservedCommit {
+		// OCI sources do not have git info, so there is no commit to compare
+		return true
+	}
+	return dep.Status.LastDeployedObjectsHash != "" && dep.Status.LastDeployedObjectsHash == dep.Status.LastObjectsHash
+}
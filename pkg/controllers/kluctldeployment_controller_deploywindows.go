@@ -0,0 +1,51 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	kluctlv1 "github.com/kluctl/kluctl/v2/api/v1beta1"
+	"github.com/kluctl/kluctl/v2/pkg/controllers/deploywindows"
+	"github.com/kluctl/kluctl/v2/pkg/kluctl_project"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"time"
+)
+
+func buildDeployWindows(obj *kluctlv1.KluctlDeployment) (*deploywindows.DeployWindows, error) {
+	if obj.Spec.DeployWindows == nil {
+		return nil, nil
+	}
+	return deploywindows.New(obj.Spec.DeployWindows)
+}
+
+// deferDeployment marks the deployment as deferred until the next deploy window opens. Unless an approved diff
+// exists already, a diff is performed to report the pending changes. It returns the message to be used for the
+// Ready condition.
+func (r *KluctlDeploymentReconciler) deferDeployment(ctx context.Context, obj *kluctlv1.KluctlDeployment, pt *preparedTarget, targetContext *kluctl_project.TargetContext, deployWindows *deploywindows.DeployWindows) string {
+	log := ctrl.LoggerFrom(ctx)
+
+	obj.Status.DeployDeferred = true
+
+	msg := "deployment is pending as it is outside of the deploy windows"
+	if next := deployWindows.NextAllowed(time.Now()); next != nil {
+		msg += fmt.Sprintf(", next window opens at %s", next.Format(time.RFC3339))
+	} else {
+		msg += ", no upcoming deploy window found"
+	}
+
+	if obj.Status.PendingApproval != nil {
+		return msg + fmt.Sprintf(". Diff %s has been approved", obj.Status.PendingApproval.ResultId)
+	}
+
+	cmdResult, err := pt.kluctlDiff(ctx, targetContext)
+	if err != nil {
+		log.Info(fmt.Sprintf("Diff for deferred deployment failed: %s", err.Error()))
+		return msg
+	}
+	summary := cmdResult.BuildSummary()
+	msg += fmt.Sprintf(". Pending changes: %d new objects, %d changed objects, %d deleted objects",
+		summary.NewObjects, summary.ChangedObjects, summary.DeletedObjects)
+	if obj.Spec.Prune {
+		msg += fmt.Sprintf(", %d orphan objects", summary.OrphanObjects)
+	}
+	return msg
+}
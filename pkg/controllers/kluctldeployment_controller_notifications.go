@@ -0,0 +1,150 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"github.com/kluctl/go-jinja2"
+	kluctlv1 "github.com/kluctl/kluctl/v2/api/v1beta1"
+	"github.com/kluctl/kluctl/v2/pkg/controllers/notifications"
+	"github.com/kluctl/kluctl/v2/pkg/types/result"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"strings"
+)
+
+func (r *KluctlDeploymentReconciler) getNotificationUrl(ctx context.Context, obj *kluctlv1.KluctlDeployment, n *kluctlv1.Notification) (string, error) {
+	name := types.NamespacedName{
+		Namespace: obj.GetNamespace(),
+		Name:      n.SecretRef.Name,
+	}
+	var secret corev1.Secret
+	if err := r.Get(ctx, name, &secret); err != nil {
+		return "", fmt.Errorf("failed to get secret '%s': %w", name.String(), err)
+	}
+
+	key := n.SecretRef.Key
+	if key == "" {
+		key = "url"
+	}
+	url, ok := secret.Data[key]
+	if !ok {
+		return "", fmt.Errorf("secret '%s' does not contain a '%s' key", name.String(), key)
+	}
+	return strings.TrimSpace(string(url)), nil
+}
+
+func (r *KluctlDeploymentReconciler) buildResultUrl(id string) string {
+	if r.WebuiUrl == "" || id == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s/#/results/%s", strings.TrimSuffix(r.WebuiUrl, "/"), id)
+}
+
+// sendNotifications sends the event to all notifications configured for it. Failures are only logged and reported
+// as events, as they should not fail the reconciliation. Messages are rendered synchronously, but posted in the
+// background, as the retrying http client might wait for minutes if a webhook is not reachable.
+func (r *KluctlDeploymentReconciler) sendNotifications(ctx context.Context, obj *kluctlv1.KluctlDeployment, j2 *jinja2.Jinja2, e *notifications.Event) {
+	log := ctrl.LoggerFrom(ctx)
+
+	e.KluctlDeployment = notifications.KluctlDeploymentRef{
+		Name:      obj.GetName(),
+		Namespace: obj.GetNamespace(),
+	}
+	if e.Summary != nil {
+		e.ResultUrl = r.buildResultUrl(e.Summary.Id)
+	}
+
+	for i := range obj.Spec.Notifications {
+		n := &obj.Spec.Notifications[i]
+		if !notifications.IsEnabled(n, e.Event) {
+			continue
+		}
+
+		url, err := r.getNotificationUrl(ctx, obj, n)
+		var body []byte
+		if err == nil {
+			body, err = notifications.Build(j2, n, e)
+		}
+		if err != nil {
+			log.Error(err, "Failed to send notification")
+			r.event(ctx, obj, true, fmt.Sprintf("failed to send %s notification. %s", n.Type, err.Error()), nil)
+			continue
+		}
+
+		// the object is modified by the reconciliation while the notification is being sent
+		objCopy := obj.DeepCopy()
+		notificationType := n.Type
+		runInBackground(ctx, func(ctx context.Context) {
+			err := notifications.Post(ctx, r.httpClient, notificationType, url, body)
+			if err != nil {
+				ctrl.LoggerFrom(ctx).Error(err, "Failed to send notification")
+				r.event(ctx, objCopy, true, fmt.Sprintf("failed to send %s notification. %s", notificationType, err.Error()), nil)
+			}
+		})
+	}
+}
+
+func (r *KluctlDeploymentReconciler) notifyDeployResult(ctx context.Context, obj *kluctlv1.KluctlDeployment, j2 *jinja2.Jinja2, deployResult *result.CommandResult, deployErr error) {
+	if len(obj.Spec.Notifications) == 0 {
+		return
+	}
+
+	summary := deployResult.BuildSummary()
+	if deployErr != nil {
+		r.sendNotifications(ctx, obj, j2, &notifications.Event{
+			Event:   kluctlv1.NotificationEventDeployFailed,
+			Message: deployErr.Error(),
+			Summary: summary,
+		})
+	} else if summary != nil {
+		r.sendNotifications(ctx, obj, j2, &notifications.Event{
+			Event:   kluctlv1.NotificationEventDeploySucceeded,
+			Message: buildCommandResultMessage(summary.Command.Command, summary),
+			Summary: summary,
+		})
+	}
+
+	if summary != nil && summary.DeletedObjects != 0 {
+		r.sendNotifications(ctx, obj, j2, &notifications.Event{
+			Event:   kluctlv1.NotificationEventPrune,
+			Message: fmt.Sprintf("%d objects pruned.", summary.DeletedObjects),
+			Summary: summary,
+		})
+	}
+}
+
+func (r *KluctlDeploymentReconciler) notifyValidateResult(ctx context.Context, obj *kluctlv1.KluctlDeployment, j2 *jinja2.Jinja2, validateResult *result.ValidateResult, validateErr error) {
+	if len(obj.Spec.Notifications) == 0 {
+		return
+	}
+
+	var msg string
+	if validateErr != nil {
+		msg = validateErr.Error()
+	} else if len(validateResult.Errors) != 0 {
+		msg = fmt.Sprintf("validate failed with %d errors.", len(validateResult.Errors))
+	} else if !validateResult.Ready {
+		msg = "validate failed. Not all objects are ready."
+	} else {
+		return
+	}
+
+	r.sendNotifications(ctx, obj, j2, &notifications.Event{
+		Event:          kluctlv1.NotificationEventValidateFailed,
+		Message:        msg,
+		ValidateResult: notifications.StripDriftChanges(validateResult),
+	})
+}
+
+func (r *KluctlDeploymentReconciler) notifyDriftDetectionResult(ctx context.Context, obj *kluctlv1.KluctlDeployment, j2 *jinja2.Jinja2, driftResult *result.DriftDetectionResult, driftErr error) {
+	if len(obj.Spec.Notifications) == 0 || driftErr != nil || !driftResult.HasDrift() {
+		return
+	}
+
+	r.sendNotifications(ctx, obj, j2, &notifications.Event{
+		Event:                kluctlv1.NotificationEventDriftDetected,
+		Message:              fmt.Sprintf("drift detected. %d drifted objects.", len(driftResult.Objects)+len(driftResult.MissingObjects)),
+		DriftDetectionResult: driftResult,
+	})
+}
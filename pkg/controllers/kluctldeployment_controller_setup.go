@@ -21,6 +21,11 @@ func (r *KluctlDeploymentReconciler) SetupWithManager(ctx context.Context, mgr c
 	httpClient.Logger = nil
 	r.httpClient = httpClient
 
+	r.requeueDependency = opts.DependencyRequeueInterval
+	if r.requeueDependency == 0 {
+		r.requeueDependency = 30 * time.Second
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&kluctlv1.KluctlDeployment{}, builder.WithPredicates(
 			predicate.Or(predicate.GenerationChangedPredicate{}, ReconcileRequestedPredicate{}),
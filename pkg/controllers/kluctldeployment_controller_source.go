@@ -1,21 +1,30 @@
 package controllers
 
 import (
+	"bytes"
 	"context"
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"github.com/docker/cli/cli/config/configfile"
+	"github.com/google/go-containerregistry/pkg/name"
 	kluctlv1 "github.com/kluctl/kluctl/v2/api/v1beta1"
 	"github.com/kluctl/kluctl/v2/pkg/git/auth"
 	"github.com/kluctl/kluctl/v2/pkg/git/messages"
+	ssh_pool "github.com/kluctl/kluctl/v2/pkg/git/ssh-pool"
+	"github.com/kluctl/kluctl/v2/pkg/oci"
+	"github.com/kluctl/kluctl/v2/pkg/registries"
 	"github.com/kluctl/kluctl/v2/pkg/repocache"
+	"github.com/kluctl/kluctl/v2/pkg/signature"
+	types2 "github.com/kluctl/kluctl/v2/pkg/types"
 	"github.com/kluctl/kluctl/v2/pkg/utils"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"os"
 	"path/filepath"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"strconv"
 )
 
 func (r *KluctlDeploymentReconciler) getGitSecret(ctx context.Context, source *kluctlv1.ProjectSource, objNs string) (*corev1.Secret, error) {
@@ -35,7 +44,7 @@ func (r *KluctlDeploymentReconciler) getGitSecret(ctx context.Context, source *k
 	return &secret, nil
 }
 
-func (r *KluctlDeploymentReconciler) buildGitAuth(ctx context.Context, gitSecret *corev1.Secret) (*auth.GitAuthProviders, error) {
+func buildGitAuth(ctx context.Context, gitSecret *corev1.Secret) (*auth.GitAuthProviders, error) {
 	log := ctrl.LoggerFrom(ctx)
 	ga := auth.NewDefaultAuthProviders("KLUCTL_GIT", &messages.MessageCallbacks{
 		WarningFn: func(s string) {
@@ -77,7 +86,7 @@ func (r *KluctlDeploymentReconciler) buildGitAuth(ctx context.Context, gitSecret
 	return ga, nil
 }
 
-func (r *KluctlDeploymentReconciler) buildRepoCache(ctx context.Context, secret *corev1.Secret) (*repocache.GitRepoCache, error) {
+func buildRepoCache(ctx context.Context, sshPool *ssh_pool.SshPool, secret *corev1.Secret) (*repocache.GitRepoCache, error) {
 	// make sure we use a unique repo cache per set of credentials
 	h := sha256.New()
 	if secret == nil {
@@ -99,11 +108,147 @@ func (r *KluctlDeploymentReconciler) buildRepoCache(ctx context.Context, secret
 
 	ctx = utils.WithTmpBaseDir(ctx, tmpBaseDir)
 
-	ga, err := r.buildGitAuth(ctx, secret)
+	ga, err := buildGitAuth(ctx, secret)
 	if err != nil {
 		return nil, err
 	}
 
-	rc := repocache.NewGitRepoCache(ctx, r.SshPool, ga, nil, 0)
+	rc := repocache.NewGitRepoCache(ctx, sshPool, ga, nil, 0)
 	return rc, nil
 }
+
+func (r *KluctlDeploymentReconciler) getOciSecret(ctx context.Context, source *kluctlv1.ProjectSourceOci, objNs string) (*corev1.Secret, error) {
+	if source == nil || source.SecretRef == nil {
+		return nil, nil
+	}
+
+	name := types.NamespacedName{
+		Namespace: objNs,
+		Name:      source.SecretRef.Name,
+	}
+	var secret corev1.Secret
+	if err := r.Get(ctx, name, &secret); err != nil {
+		return nil, fmt.Errorf("failed to get secret '%s': %w", name.String(), err)
+	}
+	return &secret, nil
+}
+
+func (r *KluctlDeploymentReconciler) buildOciRegistryHelper(ctx context.Context, source *kluctlv1.ProjectSourceOci, secret *corev1.Secret) (*registries.RegistryHelper, error) {
+	rh := registries.NewRegistryHelper(ctx)
+	err := rh.ParseAuthEntriesFromEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	if secret == nil {
+		return rh, nil
+	}
+
+	caFile := secret.Data["caFile"]
+	insecure := false
+	if x, ok := secret.Data["insecure"]; ok {
+		insecure, err = strconv.ParseBool(string(x))
+		if err != nil {
+			return nil, fmt.Errorf("failed parsing insecure flag from secret %s: %w", secret.Name, err)
+		}
+	}
+
+	if dockerConfig, ok := secret.Data[".dockerconfigjson"]; ok {
+		c := configfile.New(".dockerconfigjson")
+		err = c.LoadFromReader(bytes.NewReader(dockerConfig))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse .dockerconfigjson from secret %s: %w", secret.Name, err)
+		}
+		for registry, ac := range c.GetAuthConfigs() {
+			rh.AddAuthEntry(registries.AuthEntry{
+				Registry: registry,
+				Username: ac.Username,
+				Password: ac.Password,
+				Auth:     ac.Auth,
+				CABundle: caFile,
+				Insecure: insecure,
+			})
+		}
+		return rh, nil
+	}
+
+	e := registries.AuthEntry{
+		Registry: string(secret.Data["registry"]),
+		Username: string(secret.Data["username"]),
+		Password: string(secret.Data["password"]),
+		Auth:     string(secret.Data["auth"]),
+		CABundle: caFile,
+		Insecure: insecure,
+	}
+	if e.Registry == "" {
+		// default to the registry of the project source
+		s, err := oci.ParseOciUrl(source.URL)
+		if err != nil {
+			return nil, err
+		}
+		repo, err := name.NewRepository(s)
+		if err != nil {
+			return nil, err
+		}
+		e.Registry = repo.RegistryStr()
+	}
+	rh.AddAuthEntry(e)
+	return rh, nil
+}
+
+// buildSourceRepoKey returns the repo key used to identify the project source. For OCI sources, the registry and
+// repository of the artifact are used.
+func buildSourceRepoKey(source *kluctlv1.ProjectSource) types2.GitRepoKey {
+	if source.Oci == nil {
+		return source.URL.RepoKey()
+	}
+	s, err := oci.ParseOciUrl(source.Oci.URL)
+	if err != nil {
+		return types2.GitRepoKey{}
+	}
+	repo, err := name.NewRepository(s)
+	if err != nil {
+		return types2.GitRepoKey{}
+	}
+	return types2.GitRepoKey{
+		Host: repo.RegistryStr(),
+		Path: repo.RepositoryStr(),
+	}
+}
+
+type sourceVerificationError struct {
+	err error
+}
+
+func (e *sourceVerificationError) Error() string {
+	return fmt.Sprintf("source verification failed: %s", e.err.Error())
+}
+
+func (e *sourceVerificationError) Unwrap() error {
+	return e.err
+}
+
+func (r *KluctlDeploymentReconciler) getVerifyKeys(ctx context.Context, source *kluctlv1.ProjectSource, objNs string) (*signature.PublicKeys, error) {
+	if source.Verify == nil {
+		return nil, nil
+	}
+
+	name := types.NamespacedName{
+		Namespace: objNs,
+		Name:      source.Verify.SecretRef.Name,
+	}
+	var secret corev1.Secret
+	if err := r.Get(ctx, name, &secret); err != nil {
+		return nil, fmt.Errorf("failed to get secret '%s': %w", name.String(), err)
+	}
+
+	var keys [][]byte
+	for _, k := range secret.Data {
+		keys = append(keys, k)
+	}
+	ret, err := signature.ParsePublicKeys(keys)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse public keys from secret '%s': %w", name.String(), err)
+	}
+	return ret, nil
+}
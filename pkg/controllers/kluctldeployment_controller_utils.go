@@ -8,8 +8,23 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/tools/reference"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"time"
 )
 
+// backgroundTimeout limits the time spent on work that is performed in the background, e.g. sending notifications
+const backgroundTimeout = 10 * time.Minute
+
+// runInBackground runs f in a goroutine. The context passed to f is detached from ctx, so that it is not cancelled
+// when the reconciliation finishes, but it keeps the logger of ctx.
+func runInBackground(ctx context.Context, f func(ctx context.Context)) {
+	bgCtx := ctrl.LoggerInto(context.Background(), ctrl.LoggerFrom(ctx))
+	go func() {
+		bgCtx, cancel := context.WithTimeout(bgCtx, backgroundTimeout)
+		defer cancel()
+		f(bgCtx)
+	}()
+}
+
 func (r *KluctlDeploymentReconciler) event(ctx context.Context, obj *kluctlv1.KluctlDeployment, warning bool, msg string, metadata map[string]string) {
 	if metadata == nil {
 		metadata = map[string]string{}
@@ -0,0 +1,37 @@
+package controllers
+
+import (
+	"context"
+	"github.com/go-logr/logr/funcr"
+	"github.com/stretchr/testify/assert"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"testing"
+	"time"
+)
+
+func TestRunInBackground(t *testing.T) {
+	var logged []string
+	log := funcr.New(func(prefix, args string) {
+		logged = append(logged, args)
+	}, funcr.Options{})
+
+	ctx, cancel := context.WithCancel(ctrl.LoggerInto(context.Background(), log))
+
+	started := make(chan struct{})
+	done := make(chan error)
+	runInBackground(ctx, func(ctx context.Context) {
+		close(started)
+		ctrl.LoggerFrom(ctx).Info("from background")
+		_, hasDeadline := ctx.Deadline()
+		assert.True(t, hasDeadline)
+		// wait a little to ensure that cancelling the reconciliation context does not propagate
+		time.Sleep(100 * time.Millisecond)
+		done <- ctx.Err()
+	})
+
+	<-started
+	cancel()
+	assert.NoError(t, <-done)
+	assert.Len(t, logged, 1)
+	assert.Contains(t, logged[0], "from background")
+}
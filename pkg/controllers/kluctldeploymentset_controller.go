@@ -0,0 +1,247 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	kluctlv1 "github.com/kluctl/kluctl/v2/api/v1beta1"
+	"github.com/kluctl/kluctl/v2/pkg/controllers/deploymentset"
+	"github.com/kluctl/kluctl/v2/pkg/git"
+	ssh_pool "github.com/kluctl/kluctl/v2/pkg/git/ssh-pool"
+	"github.com/kluctl/kluctl/v2/pkg/kluctl_jinja2"
+	"github.com/kluctl/kluctl/v2/pkg/status"
+	"github.com/kluctl/kluctl/v2/pkg/utils/flux_utils/meta"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	kuberecorder "k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sort"
+)
+
+type KluctlDeploymentSetReconciler struct {
+	client.Client
+	Scheme         *runtime.Scheme
+	EventRecorder  kuberecorder.EventRecorder
+	ControllerName string
+
+	SshPool *ssh_pool.SshPool
+}
+
+// +kubebuilder:rbac:groups=gitops.kluctl.io,resources=kluctldeploymentsets,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=gitops.kluctl.io,resources=kluctldeploymentsets/status,verbs=get;update;patch
+
+func (r *KluctlDeploymentSetReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	ctx = status.NewContext(ctx, status.NewSimpleStatusHandler(func(message string) {
+		log.Info(message)
+	}, false, false))
+
+	obj := &kluctlv1.KluctlDeploymentSet{}
+	if err := r.Get(ctx, req.NamespacedName, obj); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	// generated KluctlDeployments are garbage collected via their owner references
+	if !obj.GetDeletionTimestamp().IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	if obj.Spec.Suspend {
+		log.Info("Reconciliation is suspended for this object")
+		return ctrl.Result{}, nil
+	}
+
+	patch := client.MergeFrom(obj.DeepCopy())
+
+	names, err := r.reconcileDeployments(ctx, obj)
+
+	obj.Status.ObservedGeneration = obj.GetGeneration()
+	if err != nil {
+		setSetReadiness(obj, metav1.ConditionFalse, kluctlv1.GenerateFailedReason, err.Error())
+	} else {
+		obj.Status.Deployments = names
+		setSetReadiness(obj, metav1.ConditionTrue, kluctlv1.ReconciliationSucceededReason,
+			fmt.Sprintf("Generated %d KluctlDeployments", len(names)))
+	}
+	if err2 := r.Status().Patch(ctx, obj, patch, client.FieldOwner(r.ControllerName)); err2 != nil {
+		return ctrl.Result{}, err2
+	}
+
+	if err != nil {
+		r.EventRecorder.Event(obj, corev1.EventTypeWarning, kluctlv1.GenerateFailedReason, err.Error())
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: obj.Spec.Interval.Duration}, nil
+}
+
+func setSetReadiness(obj *kluctlv1.KluctlDeploymentSet, status metav1.ConditionStatus, reason, message string) {
+	apimeta.SetStatusCondition(&obj.Status.Conditions, metav1.Condition{
+		Type:               meta.ReadyCondition,
+		Status:             status,
+		ObservedGeneration: obj.GetGeneration(),
+		Reason:             reason,
+		Message:            message,
+	})
+}
+
+// reconcileDeployments applies all generated KluctlDeployments and deletes the ones that are not generated anymore.
+// Nothing is deleted if generating fails, so that temporary errors (e.g. an unreachable Git server) do not
+// result in deleted deployments.
+func (r *KluctlDeploymentSetReconciler) reconcileDeployments(ctx context.Context, obj *kluctlv1.KluctlDeploymentSet) ([]string, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	params, err := r.generateParams(ctx, obj)
+	if err != nil {
+		return nil, err
+	}
+
+	j2, err := kluctl_jinja2.NewKluctlJinja2(true)
+	if err != nil {
+		return nil, err
+	}
+	defer j2.Close()
+
+	var generated []*unstructured.Unstructured
+	names := map[string]bool{}
+	for _, p := range params {
+		o, err := deploymentset.Render(j2, obj, p)
+		if err != nil {
+			return nil, err
+		}
+		if names[o.GetName()] {
+			return nil, fmt.Errorf("template renders the name '%s' more than once", o.GetName())
+		}
+		names[o.GetName()] = true
+
+		err = controllerutil.SetControllerReference(obj, o, r.Scheme)
+		if err != nil {
+			return nil, err
+		}
+		generated = append(generated, o)
+	}
+
+	for _, o := range generated {
+		var existing kluctlv1.KluctlDeployment
+		err = r.Get(ctx, client.ObjectKeyFromObject(o), &existing)
+		if err == nil && !metav1.IsControlledBy(&existing, obj) {
+			return nil, fmt.Errorf("KluctlDeployment %s already exists and is not controlled by this KluctlDeploymentSet", o.GetName())
+		} else if err != nil && !errors.IsNotFound(err) {
+			return nil, err
+		}
+
+		err = r.Patch(ctx, o, client.Apply, client.FieldOwner(r.ControllerName), client.ForceOwnership)
+		if err != nil {
+			return nil, fmt.Errorf("failed to apply KluctlDeployment %s: %w", o.GetName(), err)
+		}
+	}
+
+	var list kluctlv1.KluctlDeploymentList
+	err = r.List(ctx, &list, client.InNamespace(obj.GetNamespace()), client.MatchingLabels{
+		kluctlv1.KluctlDeploymentSetLabel: obj.GetName(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i := range list.Items {
+		kd := &list.Items[i]
+		if names[kd.GetName()] || !metav1.IsControlledBy(kd, obj) || !kd.GetDeletionTimestamp().IsZero() {
+			continue
+		}
+		log.Info(fmt.Sprintf("Deleting KluctlDeployment %s as it is not generated anymore", kd.GetName()))
+		err = r.Delete(ctx, kd)
+		if err != nil && !errors.IsNotFound(err) {
+			return nil, fmt.Errorf("failed to delete KluctlDeployment %s: %w", kd.GetName(), err)
+		}
+		r.EventRecorder.Eventf(obj, corev1.EventTypeNormal, "Pruned", "Deleted KluctlDeployment %s", kd.GetName())
+	}
+
+	ret := make([]string, 0, len(names))
+	for n := range names {
+		ret = append(ret, n)
+	}
+	sort.Strings(ret)
+	return ret, nil
+}
+
+func (r *KluctlDeploymentSetReconciler) generateParams(ctx context.Context, obj *kluctlv1.KluctlDeploymentSet) ([]map[string]any, error) {
+	var ret []map[string]any
+	for i, g := range obj.Spec.Generators {
+		switch {
+		case g.List != nil && g.Git == nil:
+			ret = append(ret, deploymentset.ListParams(g.List)...)
+		case g.Git != nil && g.List == nil:
+			params, err := r.generateGitParams(ctx, obj, g.Git)
+			if err != nil {
+				return nil, fmt.Errorf("git generator %d failed: %w", i, err)
+			}
+			ret = append(ret, params...)
+		default:
+			return nil, fmt.Errorf("generator %d must specify exactly one of list or git", i)
+		}
+	}
+	return ret, nil
+}
+
+func (r *KluctlDeploymentSetReconciler) generateGitParams(ctx context.Context, obj *kluctlv1.KluctlDeploymentSet, g *kluctlv1.GitGenerator) ([]map[string]any, error) {
+	var secret *corev1.Secret
+	if g.SecretRef != nil {
+		name := types.NamespacedName{
+			Namespace: obj.GetNamespace(),
+			Name:      g.SecretRef.Name,
+		}
+		secret = &corev1.Secret{}
+		if err := r.Get(ctx, name, secret); err != nil {
+			return nil, fmt.Errorf("failed to get secret '%s': %w", name.String(), err)
+		}
+	}
+
+	switch {
+	case g.Branches != nil && g.Directories == nil:
+		ga, err := buildGitAuth(ctx, secret)
+		if err != nil {
+			return nil, err
+		}
+		refs, err := git.ListRemoteRefs(ctx, g.URL, r.SshPool, ga.BuildAuth(ctx, g.URL))
+		if err != nil {
+			return nil, fmt.Errorf("failed to list refs of %s: %w", g.URL.String(), err)
+		}
+		return deploymentset.BranchParams(g.URL, g.Branches, refs)
+	case g.Directories != nil && g.Branches == nil:
+		rp, err := buildRepoCache(ctx, r.SshPool, secret)
+		if err != nil {
+			return nil, err
+		}
+		defer rp.Clear()
+
+		rpEntry, err := rp.GetEntry(g.URL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to clone %s: %w", g.URL.String(), err)
+		}
+		dir, gi, err := rpEntry.GetClonedDir(g.Directories.Ref.String())
+		if err != nil {
+			return nil, err
+		}
+		return deploymentset.DirectoryParams(g.URL, g.Directories, dir, gi.CheckedOutRef, gi.CheckedOutCommit)
+	default:
+		return nil, fmt.Errorf("exactly one of branches or directories must be specified")
+	}
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *KluctlDeploymentSetReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&kluctlv1.KluctlDeploymentSet{}, builder.WithPredicates(
+			predicate.Or(predicate.GenerationChangedPredicate{}, ReconcileRequestedPredicate{}),
+		)).
+		Owns(&kluctlv1.KluctlDeployment{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Complete(r)
+}
@@ -15,6 +15,7 @@ const (
 	PruneDurationKey          = "prune_duration_seconds"
 	DeleteDurationKey         = "delete_duration_seconds"
 	ValidateDurationKey       = "validate_duration_seconds"
+	DriftDetectionDurationKey = "drift_detection_duration_seconds"
 )
 
 var (
@@ -71,6 +72,12 @@ var (
 		Name:      ValidateDurationKey,
 		Help:      "How long a single validate takes in seconds.",
 	}, []string{"namespace", "name"})
+
+	driftDetectionDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Subsystem: KluctlDeploymentControllerSubsystem,
+		Name:      DriftDetectionDurationKey,
+		Help:      "How long a single drift detection takes in seconds.",
+	}, []string{"namespace", "name"})
 )
 
 func init() {
@@ -83,6 +90,7 @@ func init() {
 	metrics.Registry.MustRegister(pruneDuration)
 	metrics.Registry.MustRegister(deleteDuration)
 	metrics.Registry.MustRegister(validateDuration)
+	metrics.Registry.MustRegister(driftDetectionDuration)
 }
 
 func NewKluctlDeploymentDuration(namespace string, name string, mode string) prometheus.Observer {
@@ -120,3 +128,7 @@ func NewKluctlDeleteDuration(namespace string, name string) prometheus.Observer
 func NewKluctlValidateDuration(namespace string, name string) prometheus.Observer {
 	return validateDuration.WithLabelValues(namespace, name)
 }
+
+func NewKluctlDriftDetectionDuration(namespace string, name string) prometheus.Observer {
+	return driftDetectionDuration.WithLabelValues(namespace, name)
+}
@@ -15,6 +15,9 @@ const (
 	PruneEnabledKey       = "prune_enabled"
 	DeleteEnabledKey      = "delete_enabled"
 	SourceSpecKey         = "source_spec"
+
+	NumberOfDriftedObjectsKey = "number_of_drifted_objects"
+	DriftedObjectKey          = "drifted_object"
 )
 
 var (
@@ -53,6 +56,18 @@ var (
 		Name:      SourceSpecKey,
 		Help:      "The configured source spec of a single deployment.",
 	}, []string{"namespace", "name", "url", "path", "ref"})
+
+	numberOfDriftedObjects = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: KluctlDeploymentControllerSubsystem,
+		Name:      NumberOfDriftedObjectsKey,
+		Help:      "How many objects have drifted from the rendered state of a single deployment.",
+	}, []string{"namespace", "name"})
+
+	driftedObject = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: KluctlDeploymentControllerSubsystem,
+		Name:      DriftedObjectKey,
+		Help:      "Set to 1 for every object that has drifted from the rendered state of a single deployment.",
+	}, []string{"namespace", "name", "object_group", "object_kind", "object_namespace", "object_name"})
 )
 
 func init() {
@@ -62,6 +77,8 @@ func init() {
 	metrics.Registry.MustRegister(pruneEnabled)
 	metrics.Registry.MustRegister(deleteEnabled)
 	metrics.Registry.MustRegister(sourceSpec)
+	metrics.Registry.MustRegister(numberOfDriftedObjects)
+	metrics.Registry.MustRegister(driftedObject)
 }
 
 func NewKluctlDeploymentInterval(namespace string, name string) prometheus.Gauge {
@@ -87,3 +104,16 @@ func NewKluctlDeleteEnabled(namespace string, name string) prometheus.Gauge {
 func NewKluctlSourceSpec(namespace string, name string, url string, path string, ref string) prometheus.Gauge {
 	return sourceSpec.WithLabelValues(namespace, name, url, path, ref)
 }
+
+func NewKluctlNumberOfDriftedObjects(namespace string, name string) prometheus.Gauge {
+	return numberOfDriftedObjects.WithLabelValues(namespace, name)
+}
+
+func NewKluctlDriftedObject(namespace string, name string, objectGroup string, objectKind string, objectNamespace string, objectName string) prometheus.Gauge {
+	return driftedObject.WithLabelValues(namespace, name, objectGroup, objectKind, objectNamespace, objectName)
+}
+
+// DeleteKluctlDriftedObjects removes all drifted object metrics of a single deployment
+func DeleteKluctlDriftedObjects(namespace string, name string) {
+	driftedObject.DeletePartialMatch(prometheus.Labels{"namespace": namespace, "name": name})
+}
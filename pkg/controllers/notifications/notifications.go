@@ -0,0 +1,140 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/hashicorp/go-retryablehttp"
+	"github.com/kluctl/go-jinja2"
+	kluctlv1 "github.com/kluctl/kluctl/v2/api/v1beta1"
+	"github.com/kluctl/kluctl/v2/pkg/types/result"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const DefaultTemplate = `{{ kluctlDeployment.namespace }}/{{ kluctlDeployment.name }}: {{ message }}
+{%- if resultUrl %}
+{{ resultUrl }}
+{%- endif %}`
+
+type KluctlDeploymentRef struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+}
+
+// Event is the payload of a notification. It is sent as JSON to generic webhooks and passed to the message templates.
+type Event struct {
+	Event            string              `json:"event"`
+	KluctlDeployment KluctlDeploymentRef `json:"kluctlDeployment"`
+	Message          string              `json:"message"`
+	ResultUrl        string              `json:"resultUrl,omitempty"`
+
+	Summary              *result.CommandResultSummary `json:"summary,omitempty"`
+	ValidateResult       *result.ValidateResult       `json:"validateResult,omitempty"`
+	DriftDetectionResult *result.DriftDetectionResult `json:"driftDetectionResult,omitempty"`
+}
+
+// StripDriftChanges returns a copy of the validate result without the changes of drifted objects, as these might
+// contain secret data. Only the references of the drifted objects are kept.
+func StripDriftChanges(vr *result.ValidateResult) *result.ValidateResult {
+	if vr == nil {
+		return nil
+	}
+	ret := *vr
+	ret.Drift = nil
+	for _, o := range vr.Drift {
+		ret.Drift = append(ret.Drift, result.ChangedObject{Ref: o.Ref})
+	}
+	return &ret
+}
+
+// IsEnabled returns true if the given notification is configured to fire on the given event
+func IsEnabled(n *kluctlv1.Notification, event string) bool {
+	if len(n.Events) == 0 {
+		return true
+	}
+	for _, e := range n.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// RenderMessage renders the message template with all fields of the event passed as globals
+func RenderMessage(j2 *jinja2.Jinja2, template string, e *Event) (string, error) {
+	if template == "" {
+		template = DefaultTemplate
+	}
+	u, err := uo.FromStruct(e)
+	if err != nil {
+		return "", err
+	}
+	globals, err := u.ToMap()
+	if err != nil {
+		return "", err
+	}
+	s, err := j2.RenderString(template, jinja2.WithGlobals(globals))
+	if err != nil {
+		return "", fmt.Errorf("failed to render notification template: %w", err)
+	}
+	return strings.TrimSpace(s), nil
+}
+
+// BuildPayload builds the request body for the given notification type
+func BuildPayload(notificationType string, text string, e *Event) ([]byte, error) {
+	var payload any
+	switch notificationType {
+	case kluctlv1.NotificationTypeWebhook:
+		// the full event plus the rendered message
+		e2 := *e
+		e2.Message = text
+		payload = &e2
+	case kluctlv1.NotificationTypeSlack:
+		payload = map[string]any{
+			"text": text,
+		}
+	case kluctlv1.NotificationTypeMSTeams:
+		payload = map[string]any{
+			"@type":    "MessageCard",
+			"@context": "https://schema.org/extensions",
+			"summary":  fmt.Sprintf("%s/%s: %s", e.KluctlDeployment.Namespace, e.KluctlDeployment.Name, e.Event),
+			"text":     text,
+		}
+	default:
+		return nil, fmt.Errorf("unsupported notification type '%s'", notificationType)
+	}
+	return json.Marshal(payload)
+}
+
+// Build renders the message and builds the request body for the given notification
+func Build(j2 *jinja2.Jinja2, n *kluctlv1.Notification, e *Event) ([]byte, error) {
+	text, err := RenderMessage(j2, n.Template, e)
+	if err != nil {
+		return nil, err
+	}
+	return BuildPayload(n.Type, text, e)
+}
+
+// Post posts an already built request body to the given webhook url
+func Post(ctx context.Context, client *retryablehttp.Client, notificationType string, url string, body []byte) error {
+	req, err := retryablehttp.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send %s notification: %w", notificationType, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("failed to send %s notification: %s: %s", notificationType, resp.Status, strings.TrimSpace(string(b)))
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/hashicorp/go-retryablehttp"
+	"github.com/kluctl/go-jinja2"
+	kluctlv1 "github.com/kluctl/kluctl/v2/api/v1beta1"
+	"github.com/kluctl/kluctl/v2/pkg/kluctl_jinja2"
+	"github.com/kluctl/kluctl/v2/pkg/types/k8s"
+	"github.com/kluctl/kluctl/v2/pkg/types/result"
+	"github.com/stretchr/testify/assert"
+	"io"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func buildTestEvent() *Event {
+	return &Event{
+		Event: kluctlv1.NotificationEventDeploySucceeded,
+		KluctlDeployment: KluctlDeploymentRef{
+			Name:      "kd",
+			Namespace: "ns",
+		},
+		Message:   "deploy succeeded.",
+		ResultUrl: "http://webui/#/results/id",
+		Summary: &result.CommandResultSummary{
+			Id:         "id",
+			NewObjects: 2,
+		},
+	}
+}
+
+func TestIsEnabled(t *testing.T) {
+	n := &kluctlv1.Notification{}
+	assert.True(t, IsEnabled(n, kluctlv1.NotificationEventDeployFailed))
+
+	n.Events = []string{kluctlv1.NotificationEventDeployFailed, kluctlv1.NotificationEventDriftDetected}
+	assert.True(t, IsEnabled(n, kluctlv1.NotificationEventDeployFailed))
+	assert.True(t, IsEnabled(n, kluctlv1.NotificationEventDriftDetected))
+	assert.False(t, IsEnabled(n, kluctlv1.NotificationEventDeploySucceeded))
+}
+
+func TestRenderMessage(t *testing.T) {
+	j2, err := kluctl_jinja2.NewKluctlJinja2(true)
+	assert.NoError(t, err)
+	defer j2.Close()
+
+	e := buildTestEvent()
+
+	s, err := RenderMessage(j2, "", e)
+	assert.NoError(t, err)
+	assert.Equal(t, "ns/kd: deploy succeeded.\nhttp://webui/#/results/id", s)
+
+	s, err = RenderMessage(j2, "{{ event }} {{ summary.newObjects }} new objects", e)
+	assert.NoError(t, err)
+	assert.Equal(t, "deploy-succeeded 2 new objects", s)
+
+	_, err = RenderMessage(j2, "{{ summary.newObjects ", e)
+	assert.Error(t, err)
+}
+
+// buildAndPost mirrors what the controller does: the body is built synchronously while the post happens in the
+// background
+func buildAndPost(client *retryablehttp.Client, j2 *jinja2.Jinja2, n *kluctlv1.Notification, url string, e *Event) error {
+	body, err := Build(j2, n, e)
+	if err != nil {
+		return err
+	}
+	errCh := make(chan error)
+	go func() {
+		errCh <- Post(context.Background(), client, n.Type, url, body)
+	}()
+	return <-errCh
+}
+
+func TestBuildAndPost(t *testing.T) {
+	j2, err := kluctl_jinja2.NewKluctlJinja2(true)
+	assert.NoError(t, err)
+	defer j2.Close()
+
+	var body map[string]any
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		body = nil
+		_ = json.Unmarshal(b, &body)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	client := retryablehttp.NewClient()
+	client.RetryMax = 0
+	client.Logger = nil
+
+	e := buildTestEvent()
+
+	n := &kluctlv1.Notification{Type: kluctlv1.NotificationTypeSlack, Template: "{{ message }}"}
+	assert.NoError(t, buildAndPost(client, j2, n, server.URL, e))
+	assert.Equal(t, map[string]any{"text": "deploy succeeded."}, body)
+
+	n = &kluctlv1.Notification{Type: kluctlv1.NotificationTypeMSTeams, Template: "{{ message }}"}
+	assert.NoError(t, buildAndPost(client, j2, n, server.URL, e))
+	assert.Equal(t, "MessageCard", body["@type"])
+	assert.Equal(t, "ns/kd: deploy-succeeded", body["summary"])
+	assert.Equal(t, "deploy succeeded.", body["text"])
+
+	n = &kluctlv1.Notification{Type: kluctlv1.NotificationTypeWebhook, Template: "custom {{ message }}"}
+	assert.NoError(t, buildAndPost(client, j2, n, server.URL, e))
+	assert.Equal(t, "deploy-succeeded", body["event"])
+	assert.Equal(t, "custom deploy succeeded.", body["message"])
+	assert.Equal(t, map[string]any{"name": "kd", "namespace": "ns"}, body["kluctlDeployment"])
+	assert.Equal(t, "id", body["summary"].(map[string]any)["id"])
+
+	// rendering errors are reported before anything is posted
+	n = &kluctlv1.Notification{Type: kluctlv1.NotificationTypeSlack, Template: "{{ message "}
+	_, err = Build(j2, n, e)
+	assert.Error(t, err)
+
+	status = http.StatusBadRequest
+	n = &kluctlv1.Notification{Type: kluctlv1.NotificationTypeWebhook}
+	assert.ErrorContains(t, buildAndPost(client, j2, n, server.URL, e), "400 Bad Request")
+}
+
+func TestStripDriftChanges(t *testing.T) {
+	ref := k8s.ObjectRef{Version: "v1", Kind: "Secret", Name: "s", Namespace: "ns"}
+	vr := &result.ValidateResult{
+		Id: "id",
+		Drift: []result.ChangedObject{{
+			Ref: ref,
+			Changes: []result.Change{{
+				Type:     "update",
+				JsonPath: "data.password",
+				OldValue: &apiextensionsv1.JSON{Raw: []byte(`"secret-old"`)},
+				NewValue: &apiextensionsv1.JSON{Raw: []byte(`"secret-new"`)},
+			}},
+		}},
+	}
+
+	stripped := StripDriftChanges(vr)
+	assert.Equal(t, []result.ChangedObject{{Ref: ref}}, stripped.Drift)
+	// the original result must not be modified
+	assert.Len(t, vr.Drift[0].Changes, 1)
+
+	e := buildTestEvent()
+	e.ValidateResult = stripped
+	b, err := BuildPayload(kluctlv1.NotificationTypeWebhook, "", e)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(b), "secret-old")
+	assert.NotContains(t, string(b), "secret-new")
+
+	assert.Nil(t, StripDriftChanges(nil))
+}
@@ -0,0 +1,89 @@
+package receiver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+type githubPushPayload struct {
+	Ref        string `json:"ref"`
+	Repository struct {
+		CloneUrl string `json:"clone_url"`
+		SshUrl   string `json:"ssh_url"`
+		GitUrl   string `json:"git_url"`
+		HtmlUrl  string `json:"html_url"`
+	} `json:"repository"`
+}
+
+type gitlabPushPayload struct {
+	Ref     string `json:"ref"`
+	Project struct {
+		GitHttpUrl string `json:"git_http_url"`
+		GitSshUrl  string `json:"git_ssh_url"`
+		WebUrl     string `json:"web_url"`
+	} `json:"project"`
+}
+
+type genericPushPayload struct {
+	Url string `json:"url"`
+	Ref string `json:"ref"`
+}
+
+// ParsePushEvent parses the webhook payload of the given provider. It returns nil if the payload is not a push event.
+func ParsePushEvent(provider string, header http.Header, body []byte) (*PushEvent, error) {
+	var ev PushEvent
+	switch provider {
+	case ProviderGitHub, ProviderGitea:
+		eventHeader := "X-GitHub-Event"
+		if provider == ProviderGitea {
+			eventHeader = "X-Gitea-Event"
+		}
+		if header.Get(eventHeader) != "push" {
+			return nil, nil
+		}
+		var p githubPushPayload
+		err := json.Unmarshal(body, &p)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s payload: %w", provider, err)
+		}
+		ev.Ref = p.Ref
+		ev.Urls = appendNonEmpty(ev.Urls, p.Repository.CloneUrl, p.Repository.SshUrl, p.Repository.GitUrl, p.Repository.HtmlUrl)
+	case ProviderGitLab:
+		e := header.Get("X-Gitlab-Event")
+		if e != "Push Hook" && e != "Tag Push Hook" {
+			return nil, nil
+		}
+		var p gitlabPushPayload
+		err := json.Unmarshal(body, &p)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s payload: %w", provider, err)
+		}
+		ev.Ref = p.Ref
+		ev.Urls = appendNonEmpty(ev.Urls, p.Project.GitHttpUrl, p.Project.GitSshUrl, p.Project.WebUrl)
+	case ProviderGeneric:
+		var p genericPushPayload
+		err := json.Unmarshal(body, &p)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s payload: %w", provider, err)
+		}
+		ev.Ref = p.Ref
+		ev.Urls = appendNonEmpty(ev.Urls, p.Url)
+	default:
+		return nil, fmt.Errorf("unknown provider %s", provider)
+	}
+
+	if len(ev.Urls) == 0 {
+		return nil, fmt.Errorf("payload does not contain a repository url")
+	}
+	return &ev, nil
+}
+
+func appendNonEmpty(l []string, s ...string) []string {
+	for _, x := range s {
+		if x != "" {
+			l = append(l, x)
+		}
+	}
+	return l
+}
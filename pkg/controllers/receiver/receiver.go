@@ -0,0 +1,259 @@
+package receiver
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	kluctlv1 "github.com/kluctl/kluctl/v2/api/v1beta1"
+	"github.com/kluctl/kluctl/v2/pkg/types"
+	"io"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"net/http"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"strings"
+	"time"
+)
+
+const (
+	ProviderGitHub  = "github"
+	ProviderGitLab  = "gitlab"
+	ProviderGitea   = "gitea"
+	ProviderGeneric = "generic"
+)
+
+// PathPrefix is the prefix of all receiver endpoints. The provider name is appended to it, e.g. /hooks/github
+const PathPrefix = "/hooks/"
+
+const maxPayloadSize = 10 * 1024 * 1024
+
+var errInvalidSignature = errors.New("invalid signature")
+
+// PushEvent describes a push to a Git repository as reported by a webhook
+type PushEvent struct {
+	// Urls contains all urls of the pushed repository found in the payload
+	Urls []string
+	// Ref is the full ref that got pushed, e.g. refs/heads/main. It is empty if unknown.
+	Ref string
+}
+
+// Receiver accepts push webhooks from Git providers and requests reconciliation of all KluctlDeployments that use
+// the pushed repository and ref.
+type Receiver struct {
+	Client       client.Client
+	BindAddress  string
+	Secret       string
+	FieldManager string
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable. The receiver must run on all replicas, as all of
+// them might receive webhooks.
+func (r *Receiver) NeedLeaderElection() bool {
+	return false
+}
+
+// Start implements manager.Runnable
+func (r *Receiver) Start(ctx context.Context) error {
+	log := ctrl.LoggerFrom(ctx)
+
+	srv := &http.Server{
+		Addr:              r.BindAddress,
+		Handler:           r.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		log.Info(fmt.Sprintf("Starting webhook receiver on %s", r.BindAddress))
+		err := srv.ListenAndServe()
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			errCh <- err
+		}
+		close(errCh)
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	return srv.Shutdown(shutdownCtx)
+}
+
+// Handler returns the http.Handler serving all receiver endpoints
+func (r *Receiver) Handler() http.Handler {
+	mux := http.NewServeMux()
+	for _, p := range []string{ProviderGitHub, ProviderGitLab, ProviderGitea, ProviderGeneric} {
+		mux.Handle(PathPrefix+p, r.handle(p))
+	}
+	return mux
+}
+
+func (r *Receiver) handle(provider string) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		log := ctrl.Log.WithName("webhook-receiver")
+
+		if req.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		body, err := io.ReadAll(io.LimitReader(req.Body, maxPayloadSize))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		err = verifySignature(provider, r.Secret, req.Header, body)
+		if err != nil {
+			log.Info(fmt.Sprintf("Rejected %s webhook: %s", provider, err.Error()))
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+
+		ev, err := ParsePushEvent(provider, req.Header, body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if ev == nil {
+			// not a push event, e.g. a ping
+			_, _ = fmt.Fprintf(w, "ignored event\n")
+			return
+		}
+
+		names, err := r.RequestReconcile(req.Context(), ev)
+		if err != nil {
+			log.Error(err, "Failed to request reconciliation")
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		log.Info(fmt.Sprintf("Received %s push for %s (%s), requested reconciliation of %d KluctlDeployments",
+			provider, strings.Join(ev.Urls, ", "), ev.Ref, len(names)))
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"kluctlDeployments": names,
+		})
+	}
+}
+
+// RequestReconcile sets the reconcile request annotation on all KluctlDeployments that match the push event. It
+// returns the namespaced names of all affected KluctlDeployments.
+func (r *Receiver) RequestReconcile(ctx context.Context, ev *PushEvent) ([]string, error) {
+	var keys []types.GitRepoKey
+	for _, u := range ev.Urls {
+		gu, err := types.ParseGitUrl(u)
+		if err != nil {
+			continue
+		}
+		keys = append(keys, gu.RepoKey())
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no valid repository url found in payload")
+	}
+
+	var l kluctlv1.KluctlDeploymentList
+	err := r.Client.List(ctx, &l)
+	if err != nil {
+		return nil, err
+	}
+
+	names := []string{}
+	for i := range l.Items {
+		kd := &l.Items[i]
+		if !matchesPushEvent(kd, keys, ev.Ref) {
+			continue
+		}
+
+		patch := client.MergeFrom(kd.DeepCopy())
+		metav1.SetMetaDataAnnotation(&kd.ObjectMeta, kluctlv1.KluctlRequestReconcileAnnotation, time.Now().Format(time.RFC3339Nano))
+		err = r.Client.Patch(ctx, kd, patch, client.FieldOwner(r.FieldManager))
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, fmt.Sprintf("%s/%s", kd.Namespace, kd.Name))
+	}
+	return names, nil
+}
+
+func matchesPushEvent(kd *kluctlv1.KluctlDeployment, keys []types.GitRepoKey, ref string) bool {
+	if kd.Spec.Source.Oci != nil || kd.Spec.Source.URL.Host == "" {
+		return false
+	}
+
+	repoKey := kd.Spec.Source.URL.RepoKey()
+	found := false
+	for _, k := range keys {
+		if k == repoKey {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return false
+	}
+
+	// if either the pushed ref or the ref of the KluctlDeployment is unknown, we can't filter by ref. The latter
+	// means that the default branch is used, which we can't determine from all payloads.
+	kdRef := kd.Spec.Source.Ref.String()
+	if ref == "" || kdRef == "" {
+		return true
+	}
+	return ref == kdRef
+}
+
+func verifySignature(provider string, secret string, header http.Header, body []byte) error {
+	if secret == "" {
+		return fmt.Errorf("no secret configured")
+	}
+
+	switch provider {
+	case ProviderGitLab:
+		// GitLab does not sign payloads but sends the configured secret token instead
+		token := header.Get("X-Gitlab-Token")
+		if subtle.ConstantTimeCompare([]byte(token), []byte(secret)) != 1 {
+			return fmt.Errorf("invalid token")
+		}
+		return nil
+	case ProviderGitHub:
+		return verifyHmac(strings.TrimPrefix(header.Get("X-Hub-Signature-256"), "sha256="), secret, body)
+	case ProviderGitea:
+		return verifyHmac(header.Get("X-Gitea-Signature"), secret, body)
+	case ProviderGeneric:
+		return verifyHmac(strings.TrimPrefix(header.Get("X-Signature"), "sha256="), secret, body)
+	default:
+		return fmt.Errorf("unknown provider %s", provider)
+	}
+}
+
+func verifyHmac(signature string, secret string, body []byte) error {
+	if signature == "" {
+		return fmt.Errorf("missing signature")
+	}
+	sig, err := hex.DecodeString(signature)
+	if err != nil {
+		return errInvalidSignature
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	if !hmac.Equal(sig, mac.Sum(nil)) {
+		return errInvalidSignature
+	}
+	return nil
+}
+
+// Sign computes the signature of the given payload in the format expected by the generic provider
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
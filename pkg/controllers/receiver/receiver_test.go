@@ -0,0 +1,207 @@
+package receiver
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	kluctlv1 "github.com/kluctl/kluctl/v2/api/v1beta1"
+	"github.com/kluctl/kluctl/v2/pkg/types"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"net/http"
+	"net/http/httptest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"testing"
+)
+
+const testSecret = "test-secret"
+
+const githubPayload = `{
+  "ref": "refs/heads/main",
+  "repository": {
+    "clone_url": "https://github.com/example/repo.git",
+    "ssh_url": "git@github.com:example/repo.git",
+    "html_url": "https://github.com/example/repo"
+  }
+}`
+
+const gitlabPayload = `{
+  "object_kind": "push",
+  "ref": "refs/heads/main",
+  "project": {
+    "git_http_url": "https://gitlab.com/example/repo.git",
+    "git_ssh_url": "git@gitlab.com:example/repo.git",
+    "web_url": "https://gitlab.com/example/repo"
+  }
+}`
+
+const giteaPayload = `{
+  "ref": "refs/heads/main",
+  "repository": {
+    "clone_url": "https://gitea.example.com/example/repo.git",
+    "ssh_url": "git@gitea.example.com:example/repo.git",
+    "html_url": "https://gitea.example.com/example/repo"
+  }
+}`
+
+func hexHmac(secret string, body string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(body))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func buildKd(name string, url string, ref *kluctlv1.GitRef) *kluctlv1.KluctlDeployment {
+	return &kluctlv1.KluctlDeployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+		},
+		Spec: kluctlv1.KluctlDeploymentSpec{
+			Source: kluctlv1.ProjectSource{
+				URL: *types.ParseGitUrlMust(url),
+				Ref: ref,
+			},
+		},
+	}
+}
+
+func newTestReceiver(t *testing.T, objs ...client.Object) *Receiver {
+	scheme := runtime.NewScheme()
+	err := kluctlv1.AddToScheme(scheme)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+	return &Receiver{
+		Client:       c,
+		Secret:       testSecret,
+		FieldManager: "test",
+	}
+}
+
+func isReconcileRequested(t *testing.T, r *Receiver, name string) bool {
+	var kd kluctlv1.KluctlDeployment
+	err := r.Client.Get(context.Background(), client.ObjectKey{Name: name, Namespace: "default"}, &kd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, ok := kd.GetAnnotations()[kluctlv1.KluctlRequestReconcileAnnotation]
+	return ok
+}
+
+func doPost(r *Receiver, provider string, header map[string]string, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, PathPrefix+provider, bytes.NewReader([]byte(body)))
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	w := httptest.NewRecorder()
+	r.Handler().ServeHTTP(w, req)
+	return w
+}
+
+func TestReceiverProviders(t *testing.T) {
+	type testCase struct {
+		provider string
+		header   map[string]string
+		body     string
+		url      string
+	}
+	tests := []testCase{
+		{provider: ProviderGitHub, body: githubPayload, url: "git@github.com:example/repo.git", header: map[string]string{
+			"X-GitHub-Event":      "push",
+			"X-Hub-Signature-256": "sha256=" + hexHmac(testSecret, githubPayload),
+		}},
+		{provider: ProviderGitLab, body: gitlabPayload, url: "https://gitlab.com/example/repo", header: map[string]string{
+			"X-Gitlab-Event": "Push Hook",
+			"X-Gitlab-Token": testSecret,
+		}},
+		{provider: ProviderGitea, body: giteaPayload, url: "https://gitea.example.com/example/repo.git", header: map[string]string{
+			"X-Gitea-Event":     "push",
+			"X-Gitea-Signature": hexHmac(testSecret, giteaPayload),
+		}},
+		{provider: ProviderGeneric, body: `{"url": "https://example.com/example/repo.git", "ref": "refs/heads/main"}`, url: "ssh://git@example.com/example/repo", header: map[string]string{
+			"X-Signature": Sign(testSecret, []byte(`{"url": "https://example.com/example/repo.git", "ref": "refs/heads/main"}`)),
+		}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.provider, func(t *testing.T) {
+			r := newTestReceiver(t,
+				buildKd("match", tc.url, &kluctlv1.GitRef{Branch: "main"}),
+				buildKd("match-default-branch", tc.url, nil),
+				buildKd("other-branch", tc.url, &kluctlv1.GitRef{Branch: "other"}),
+				buildKd("other-repo", "https://example.com/other/repo.git", nil),
+			)
+
+			w := doPost(r, tc.provider, tc.header, tc.body)
+			assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+			assert.True(t, isReconcileRequested(t, r, "match"))
+			assert.True(t, isReconcileRequested(t, r, "match-default-branch"))
+			assert.False(t, isReconcileRequested(t, r, "other-branch"))
+			assert.False(t, isReconcileRequested(t, r, "other-repo"))
+		})
+	}
+}
+
+func TestReceiverInvalidSignature(t *testing.T) {
+	r := newTestReceiver(t, buildKd("match", "https://github.com/example/repo.git", nil))
+
+	w := doPost(r, ProviderGitHub, map[string]string{
+		"X-GitHub-Event":      "push",
+		"X-Hub-Signature-256": "sha256=" + hexHmac("wrong-secret", githubPayload),
+	}, githubPayload)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	w = doPost(r, ProviderGitHub, map[string]string{
+		"X-GitHub-Event": "push",
+	}, githubPayload)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	w = doPost(r, ProviderGitLab, map[string]string{
+		"X-Gitlab-Event": "Push Hook",
+		"X-Gitlab-Token": "wrong-secret",
+	}, gitlabPayload)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	assert.False(t, isReconcileRequested(t, r, "match"))
+}
+
+func TestReceiverIgnoredEvents(t *testing.T) {
+	r := newTestReceiver(t, buildKd("match", "https://github.com/example/repo.git", nil))
+
+	body := `{"zen": "Keep it logically awesome."}`
+	w := doPost(r, ProviderGitHub, map[string]string{
+		"X-GitHub-Event":      "ping",
+		"X-Hub-Signature-256": "sha256=" + hexHmac(testSecret, body),
+	}, body)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.False(t, isReconcileRequested(t, r, "match"))
+}
+
+func TestReceiverTagPush(t *testing.T) {
+	r := newTestReceiver(t,
+		buildKd("tag", "https://example.com/example/repo.git", &kluctlv1.GitRef{Tag: "v1.0.0"}),
+		buildKd("branch", "https://example.com/example/repo.git", &kluctlv1.GitRef{Branch: "main"}),
+	)
+
+	body := `{"url": "https://example.com/example/repo.git", "ref": "refs/tags/v1.0.0"}`
+	w := doPost(r, ProviderGeneric, map[string]string{
+		"X-Signature": Sign(testSecret, []byte(body)),
+	}, body)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.True(t, isReconcileRequested(t, r, "tag"))
+	assert.False(t, isReconcileRequested(t, r, "branch"))
+}
+
+func TestReceiverMethodNotAllowed(t *testing.T) {
+	r := newTestReceiver(t)
+	req := httptest.NewRequest(http.MethodGet, PathPrefix+ProviderGeneric, nil)
+	w := httptest.NewRecorder()
+	r.Handler().ServeHTTP(w, req)
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+}
@@ -21,6 +21,34 @@ func setReadiness(obj *kluctlv1.KluctlDeployment, status metav1.ConditionStatus,
 	obj.SetConditions(c)
 }
 
+func setDrifted(obj *kluctlv1.KluctlDeployment, status metav1.ConditionStatus, reason, message string) {
+	newCondition := metav1.Condition{
+		Type:               kluctlv1.DriftedCondition,
+		Status:             status,
+		Reason:             reason,
+		Message:            trimString(message, kluctlv1.MaxConditionMessageLength),
+		ObservedGeneration: obj.Generation,
+	}
+
+	c := obj.GetConditions()
+	apimeta.SetStatusCondition(&c, newCondition)
+	obj.SetConditions(c)
+}
+
+func setAwaitingApproval(obj *kluctlv1.KluctlDeployment, status metav1.ConditionStatus, reason, message string) {
+	newCondition := metav1.Condition{
+		Type:               kluctlv1.AwaitingApprovalCondition,
+		Status:             status,
+		Reason:             reason,
+		Message:            trimString(message, kluctlv1.MaxConditionMessageLength),
+		ObservedGeneration: obj.Generation,
+	}
+
+	c := obj.GetConditions()
+	apimeta.SetStatusCondition(&c, newCondition)
+	obj.SetConditions(c)
+}
+
 func trimString(str string, limit int) string {
 	if len(str) <= limit {
 		return str
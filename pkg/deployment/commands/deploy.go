@@ -5,9 +5,11 @@ import (
 	"github.com/google/uuid"
 	utils2 "github.com/kluctl/kluctl/v2/pkg/deployment/utils"
 	"github.com/kluctl/kluctl/v2/pkg/kluctl_project"
+	"github.com/kluctl/kluctl/v2/pkg/results"
 	"github.com/kluctl/kluctl/v2/pkg/status"
 	k8s2 "github.com/kluctl/kluctl/v2/pkg/types/k8s"
 	"github.com/kluctl/kluctl/v2/pkg/types/result"
+	"github.com/kluctl/kluctl/v2/pkg/validation"
 	"time"
 )
 
@@ -22,6 +24,15 @@ type DeployCommand struct {
 	NoWait              bool
 	Prune               bool
 	WaitPrune           bool
+	ApplyConcurrency    int
+
+	// RollbackOnError causes the deployment to be rolled back to the last successful deploy result found in
+	// ResultStore in case the deployment finished with errors.
+	RollbackOnError bool
+	ResultStore     results.ResultStore
+	// ProjectKey overrides the project key that is used to find previous command results. If not set, it is
+	// determined from the git repository of the project.
+	ProjectKey *result.ProjectKey
 }
 
 func NewDeployCommand(targetCtx *kluctl_project.TargetContext) *DeployCommand {
@@ -44,6 +55,11 @@ func (cmd *DeployCommand) Run(diffResultCb func(diffResult *result.CommandResult
 		return nil, err
 	}
 
+	healthChecks, err := validation.NewHealthChecks(cmd.targetCtx.DeploymentCollection.Project.GetHealthChecks())
+	if err != nil {
+		return nil, err
+	}
+
 	// prepare for a diff
 	o := &utils2.ApplyUtilOptions{
 		ForceApply:          cmd.ForceApply,
@@ -53,6 +69,8 @@ func (cmd *DeployCommand) Run(diffResultCb func(diffResult *result.CommandResult
 		AbortOnError:        false,
 		ReadinessTimeout:    cmd.ReadinessTimeout,
 		NoWait:              cmd.NoWait,
+		Concurrency:         cmd.ApplyConcurrency,
+		HealthChecks:        healthChecks,
 	}
 
 	if diffResultCb != nil {
@@ -60,6 +78,7 @@ func (cmd *DeployCommand) Run(diffResultCb func(diffResult *result.CommandResult
 		au.ApplyDeployments(cmd.targetCtx.DeploymentCollection.Deployments)
 
 		du := utils2.NewDiffUtil(dew, ru, au.GetAppliedObjectsMap())
+		du.Concurrency = cmd.ApplyConcurrency
 		du.DiffDeploymentItems(cmd.targetCtx.DeploymentCollection.Deployments)
 
 		orphanObjects, err := FindOrphanObjects(cmd.targetCtx.SharedContext.K, ru, cmd.targetCtx.DeploymentCollection)
@@ -88,6 +107,7 @@ func (cmd *DeployCommand) Run(diffResultCb func(diffResult *result.CommandResult
 	au.ApplyDeployments(cmd.targetCtx.DeploymentCollection.Deployments)
 
 	du := utils2.NewDiffUtil(dew, ru, au.GetAppliedObjectsMap())
+	du.Concurrency = cmd.ApplyConcurrency
 	du.DiffDeploymentItems(cmd.targetCtx.DeploymentCollection.Deployments)
 
 	orphanObjects, err := FindOrphanObjects(cmd.targetCtx.SharedContext.K, ru, cmd.targetCtx.DeploymentCollection)
@@ -127,9 +147,74 @@ func (cmd *DeployCommand) Run(diffResultCb func(diffResult *result.CommandResult
 	r.Command.ForceReplaceOnError = cmd.ForceReplaceOnError
 	r.Command.AbortOnError = cmd.AbortOnError
 	r.Command.NoWait = cmd.NoWait
+	r.Command.RollbackOnError = cmd.RollbackOnError
 	err = addBaseCommandInfoToResult(cmd.targetCtx, r, "deploy")
 	if err != nil {
 		return r, err
 	}
+	if cmd.ProjectKey != nil {
+		r.ProjectKey = *cmd.ProjectKey
+	}
+
+	if cmd.RollbackOnError && len(r.Errors) != 0 && !o.DryRun {
+		err = cmd.rollback(r, *o)
+		if err != nil {
+			return r, err
+		}
+	}
 	return r, nil
 }
+
+func (cmd *DeployCommand) rollback(r *result.CommandResult, o utils2.ApplyUtilOptions) error {
+	ctx := cmd.targetCtx.SharedContext.Ctx
+
+	if cmd.ResultStore == nil {
+		r.Warnings = append(r.Warnings, result.DeploymentError{
+			Message: "rollback is not possible without a result store",
+		})
+		return nil
+	}
+
+	prev, err := findLastSuccessfulDeployResult(cmd.ResultStore, r.ProjectKey, r.TargetKey, r.Id)
+	if err != nil {
+		return fmt.Errorf("failed to find previous command result for rollback: %w", err)
+	}
+	if prev == nil {
+		status.Warning(ctx, "No previous successful deployment found, skipping rollback")
+		r.Warnings = append(r.Warnings, result.DeploymentError{
+			Message: "no previous successful deployment found, skipping rollback",
+		})
+		return nil
+	}
+
+	var newObjects []k8s2.ObjectRef
+	for _, x := range r.Objects {
+		if x.New {
+			newObjects = append(newObjects, x.Ref)
+		}
+	}
+
+	dew := utils2.NewDeploymentErrorsAndWarnings()
+	rr := rollbackToCommandResult(ctx, cmd.targetCtx.SharedContext.K, dew, o, prev, newObjects)
+
+	deleted := map[k8s2.ObjectRef]bool{}
+	for _, ref := range rr.deleted {
+		deleted[ref] = true
+	}
+	for i := range r.Objects {
+		if deleted[r.Objects[i].Ref] {
+			r.Objects[i].Deleted = true
+		}
+	}
+
+	r.RolledBackTo = prev.Id
+	for _, e := range dew.GetErrorsList() {
+		e.Message = "rollback: " + e.Message
+		r.Errors = append(r.Errors, e)
+	}
+	for _, w := range dew.GetWarningsList() {
+		w.Message = "rollback: " + w.Message
+		r.Warnings = append(r.Warnings, w)
+	}
+	return nil
+}
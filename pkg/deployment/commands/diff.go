@@ -19,6 +19,7 @@ type DiffCommand struct {
 	IgnoreTags          bool
 	IgnoreLabels        bool
 	IgnoreAnnotations   bool
+	ApplyConcurrency    int
 }
 
 func NewDiffCommand(targetCtx *kluctl_project.TargetContext) *DiffCommand {
@@ -48,11 +49,13 @@ func (cmd *DiffCommand) Run() (*result.CommandResult, error) {
 		DryRun:              true,
 		AbortOnError:        false,
 		ReadinessTimeout:    0,
+		Concurrency:         cmd.ApplyConcurrency,
 	}
 	au := utils.NewApplyDeploymentsUtil(cmd.targetCtx.SharedContext.Ctx, dew, ru, cmd.targetCtx.SharedContext.K, o)
 	au.ApplyDeployments(cmd.targetCtx.DeploymentCollection.Deployments)
 
 	du := utils.NewDiffUtil(dew, ru, au.GetAppliedObjectsMap())
+	du.Concurrency = cmd.ApplyConcurrency
 	du.IgnoreTags = cmd.IgnoreTags
 	du.IgnoreLabels = cmd.IgnoreLabels
 	du.IgnoreAnnotations = cmd.IgnoreAnnotations
@@ -14,6 +14,8 @@ import (
 
 type PokeImagesCommand struct {
 	targetCtx *kluctl_project.TargetContext
+
+	ApplyConcurrency int
 }
 
 func NewPokeImagesCommand(targetCtx *kluctl_project.TargetContext) *PokeImagesCommand {
@@ -92,6 +94,7 @@ func (cmd *PokeImagesCommand) Run() (*result.CommandResult, error) {
 	wg.Wait()
 
 	du := utils2.NewDiffUtil(dew, ru, au.GetAppliedObjectsMap())
+	du.Concurrency = cmd.ApplyConcurrency
 	du.DiffDeploymentItems(cmd.targetCtx.DeploymentCollection.Deployments)
 
 	orphanObjects, err := FindOrphanObjects(cmd.targetCtx.SharedContext.K, ru, cmd.targetCtx.DeploymentCollection)
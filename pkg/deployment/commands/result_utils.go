@@ -75,45 +75,60 @@ func addGitInfo(targetCtx *kluctl_project.TargetContext, r *result.CommandResult
 		return nil
 	}
 
-	projectDirAbs, err := filepath.Abs(targetCtx.KluctlProject.LoadArgs.ProjectDir)
+	gitInfo, projectKey, err := BuildGitInfo(targetCtx.KluctlProject.LoadArgs.RepoRoot, targetCtx.KluctlProject.LoadArgs.ProjectDir)
 	if err != nil {
 		return err
 	}
+	if gitInfo == nil {
+		return nil
+	}
+	r.GitInfo = *gitInfo
+	r.ProjectKey = *projectKey
+	return nil
+}
 
-	subDir, err := filepath.Rel(targetCtx.KluctlProject.LoadArgs.RepoRoot, projectDirAbs)
+// BuildGitInfo determines the git info and project key of the project found at projectDir. It returns nil if repoRoot
+// is not a git repository.
+func BuildGitInfo(repoRoot string, projectDir string) (*result.GitInfo, *result.ProjectKey, error) {
+	projectDirAbs, err := filepath.Abs(projectDir)
 	if err != nil {
-		return err
+		return nil, nil, err
+	}
+
+	subDir, err := filepath.Rel(repoRoot, projectDirAbs)
+	if err != nil {
+		return nil, nil, err
 	}
 	if subDir == "." {
 		subDir = ""
 	}
 
-	g, err := git2.PlainOpen(targetCtx.KluctlProject.LoadArgs.RepoRoot)
+	g, err := git2.PlainOpen(repoRoot)
 	if err != nil {
 		if err == git2.ErrRepositoryNotExists {
-			return nil
+			return nil, nil, nil
 		}
-		return err
+		return nil, nil, err
 	}
 
 	w, err := g.Worktree()
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
 
 	s, err := w.Status()
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
 
 	head, err := g.Head()
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
 
 	remotes, err := g.Remotes()
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
 
 	var originUrl *types.GitUrl
@@ -121,7 +136,7 @@ func addGitInfo(targetCtx *kluctl_project.TargetContext, r *result.CommandResult
 		if r.Config().Name == "origin" {
 			originUrl, err = types.ParseGitUrl(r.Config().URLs[0])
 			if err != nil {
-				return err
+				return nil, nil, err
 			}
 		}
 	}
@@ -131,31 +146,42 @@ func addGitInfo(targetCtx *kluctl_project.TargetContext, r *result.CommandResult
 		repoKey = originUrl.RepoKey()
 	}
 
-	r.GitInfo = result.GitInfo{
+	gitInfo := &result.GitInfo{
 		Url:    originUrl,
 		Ref:    head.Name().String(),
 		SubDir: subDir,
 		Commit: head.Hash().String(),
 		Dirty:  !s.IsClean(),
 	}
-	r.ProjectKey.GitRepoKey = repoKey
-	r.ProjectKey.SubDir = subDir
-	return nil
+	projectKey := &result.ProjectKey{
+		GitRepoKey: repoKey,
+		SubDir:     subDir,
+	}
+	return gitInfo, projectKey, nil
 }
 
 func addClusterInfo(k *k8s2.K8sCluster, r *result.CommandResult) error {
+	clusterId, err := GetClusterId(k)
+	if err != nil {
+		return err
+	}
+	r.ClusterInfo = result.ClusterInfo{
+		ClusterId: clusterId,
+	}
+	return nil
+}
+
+// GetClusterId returns the id that is used to identify the cluster in command results
+func GetClusterId(k *k8s2.K8sCluster) (string, error) {
 	kubeSystemNs, _, err := k.GetSingleObject(
 		k8s.NewObjectRef("", "v1", "Namespace", "kube-system", ""))
 	if err != nil {
-		return err
+		return "", err
 	}
 	// we reuse the kube-system namespace uid as global cluster id
 	clusterId := kubeSystemNs.GetK8sUid()
 	if clusterId == "" {
-		return fmt.Errorf("kube-system namespace has no uid")
+		return "", fmt.Errorf("kube-system namespace has no uid")
 	}
-	r.ClusterInfo = result.ClusterInfo{
-		ClusterId: clusterId,
-	}
-	return nil
+	return clusterId, nil
 }
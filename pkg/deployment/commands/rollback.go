@@ -0,0 +1,166 @@
+package commands
+
+import (
+	"context"
+	"github.com/google/uuid"
+	utils2 "github.com/kluctl/kluctl/v2/pkg/deployment/utils"
+	"github.com/kluctl/kluctl/v2/pkg/k8s"
+	k8s2 "github.com/kluctl/kluctl/v2/pkg/types/k8s"
+	"github.com/kluctl/kluctl/v2/pkg/types/result"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"time"
+)
+
+// RollbackCommand re-applies the rendered objects stored in a previous command result. The project is not loaded or
+// rendered again.
+type RollbackCommand struct {
+	prev *result.CommandResult
+
+	ForceApply          bool
+	ReplaceOnError      bool
+	ForceReplaceOnError bool
+	AbortOnError        bool
+	Prune               bool
+	ApplyConcurrency    int
+}
+
+func NewRollbackCommand(prev *result.CommandResult) *RollbackCommand {
+	return &RollbackCommand{
+		prev: prev,
+	}
+}
+
+func (cmd *RollbackCommand) Run(ctx context.Context, k *k8s.K8sCluster, diffResultCb func(diffResult *result.CommandResult) error) (*result.CommandResult, error) {
+	startTime := time.Now()
+
+	dew := utils2.NewDeploymentErrorsAndWarnings()
+
+	objects, renderedRefs := collectRollbackObjects(cmd.prev, dew)
+	var refs []k8s2.ObjectRef
+	for _, x := range objects {
+		refs = append(refs, x.GetK8sRef())
+	}
+
+	ru := utils2.NewRemoteObjectsUtil(ctx, dew)
+	err := ru.UpdateRemoteObjects(k, &cmd.prev.Target.Discriminator, refs, false)
+	if err != nil {
+		return nil, err
+	}
+
+	findOrphans := func() ([]k8s2.ObjectRef, error) {
+		if cmd.prev.Target.Discriminator == "" {
+			return nil, nil
+		}
+		var keep []k8s2.ObjectRef
+		for ref := range renderedRefs {
+			keep = append(keep, ref)
+		}
+		return utils2.FindObjectsForDelete(k, ru.GetFilteredRemoteObjects(nil), false, keep)
+	}
+
+	o := &utils2.ApplyUtilOptions{
+		ForceApply:          cmd.ForceApply,
+		ReplaceOnError:      cmd.ReplaceOnError,
+		ForceReplaceOnError: cmd.ForceReplaceOnError,
+		DryRun:              true,
+		AbortOnError:        false,
+		NoWait:              true,
+		Concurrency:         cmd.ApplyConcurrency,
+	}
+
+	if diffResultCb != nil {
+		ad := applyRollbackObjects(ctx, k, dew, ru, o, cmd.prev.Id, objects)
+
+		du := utils2.NewDiffUtil(dew, ru, ad.GetAppliedObjectsMap())
+		du.Concurrency = cmd.ApplyConcurrency
+		du.DiffObjects(objects)
+
+		orphanObjects, err := findOrphans()
+		if err != nil {
+			return nil, err
+		}
+		diffResult := &result.CommandResult{
+			Id:       uuid.New().String(),
+			Objects:  collectObjectsFromRendered(objects, ru, ad, du, orphanObjects, nil),
+			Errors:   dew.GetErrorsList(),
+			Warnings: dew.GetWarningsList(),
+		}
+
+		err = diffResultCb(diffResult)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// clear errors and warnings and collect again so that warnings about skipped objects are part of the final result
+	dew.Init()
+	objects, _ = collectRollbackObjects(cmd.prev, dew)
+
+	o.DryRun = k.DryRun
+	o.AbortOnError = cmd.AbortOnError
+
+	ad := applyRollbackObjects(ctx, k, dew, ru, o, cmd.prev.Id, objects)
+
+	du := utils2.NewDiffUtil(dew, ru, ad.GetAppliedObjectsMap())
+	du.Concurrency = cmd.ApplyConcurrency
+	du.DiffObjects(objects)
+
+	orphanObjects, err := findOrphans()
+	if err != nil {
+		return nil, err
+	}
+
+	var deleted []k8s2.ObjectRef
+	if cmd.Prune && len(orphanObjects) != 0 {
+		deleted = utils2.DeleteObjects(ctx, k, orphanObjects, dew, false)
+		orphanObjects = removeRefs(orphanObjects, deleted)
+	}
+
+	r := &result.CommandResult{
+		Id:           uuid.New().String(),
+		ProjectKey:   cmd.prev.ProjectKey,
+		TargetKey:    cmd.prev.TargetKey,
+		Target:       cmd.prev.Target,
+		GitInfo:      cmd.prev.GitInfo,
+		Deployment:   cmd.prev.Deployment,
+		Objects:      collectObjectsFromRendered(objects, ru, ad, du, orphanObjects, deleted),
+		Errors:       dew.GetErrorsList(),
+		Warnings:     dew.GetWarningsList(),
+		SeenImages:   cmd.prev.SeenImages,
+		RolledBackTo: cmd.prev.Id,
+	}
+	r.Command = result.CommandInfo{
+		StartTime:           metav1.NewTime(startTime),
+		EndTime:             metav1.Now(),
+		Command:             "rollback",
+		Target:              cmd.prev.Command.Target,
+		TargetNameOverride:  cmd.prev.Command.TargetNameOverride,
+		ContextOverride:     cmd.prev.Command.ContextOverride,
+		Args:                cmd.prev.Command.Args,
+		Images:              cmd.prev.Command.Images,
+		DryRun:              k.DryRun,
+		ForceApply:          cmd.ForceApply,
+		ReplaceOnError:      cmd.ReplaceOnError,
+		ForceReplaceOnError: cmd.ForceReplaceOnError,
+		AbortOnError:        cmd.AbortOnError,
+	}
+	err = addClusterInfo(k, r)
+	if err != nil {
+		return r, err
+	}
+	return r, nil
+}
+
+func removeRefs(refs []k8s2.ObjectRef, remove []k8s2.ObjectRef) []k8s2.ObjectRef {
+	m := map[k8s2.ObjectRef]bool{}
+	for _, x := range remove {
+		m[x] = true
+	}
+	var ret []k8s2.ObjectRef
+	for _, x := range refs {
+		if !m[x] {
+			ret = append(ret, x)
+		}
+	}
+	return ret
+}
@@ -0,0 +1,194 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	utils2 "github.com/kluctl/kluctl/v2/pkg/deployment/utils"
+	"github.com/kluctl/kluctl/v2/pkg/k8s"
+	"github.com/kluctl/kluctl/v2/pkg/results"
+	"github.com/kluctl/kluctl/v2/pkg/status"
+	k8s2 "github.com/kluctl/kluctl/v2/pkg/types/k8s"
+	"github.com/kluctl/kluctl/v2/pkg/types/result"
+	"github.com/kluctl/kluctl/v2/pkg/utils"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"sort"
+)
+
+func isSuccessfulDeploy(s *result.CommandResultSummary) bool {
+	return s.Command.Command == "deploy" && !s.Command.DryRun && len(s.Errors) == 0
+}
+
+// findLastSuccessfulDeployResult returns the most recent deploy result for the given project and target that
+// finished without errors and was not performed in dry-run mode.
+func findLastSuccessfulDeployResult(store results.ResultStore, projectKey result.ProjectKey, targetKey result.TargetKey, excludeId string) (*result.CommandResult, error) {
+	summaries, err := store.ListCommandResultSummaries(results.ListCommandResultSummariesOptions{
+		ProjectFilter: &projectKey,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// summaries are sorted with the newest result first
+	for _, s := range summaries {
+		if s.Id == excludeId || s.TargetKey != targetKey {
+			continue
+		}
+		if !isSuccessfulDeploy(&s) {
+			continue
+		}
+		return store.GetCommandResult(results.GetCommandResultOptions{
+			Id: s.Id,
+		})
+	}
+	return nil, nil
+}
+
+// FindPreviousDeployResult returns the n-th successful deploy result before the most recent deployment of the given
+// project and target. The most recent deployment itself is skipped, no matter if it was successful or not.
+func FindPreviousDeployResult(store results.ResultStore, projectKey result.ProjectKey, targetName string, clusterId string, n int) (*result.CommandResult, error) {
+	if n <= 0 {
+		return nil, fmt.Errorf("invalid number of previous deployments: %d", n)
+	}
+
+	summaries, err := store.ListCommandResultSummaries(results.ListCommandResultSummariesOptions{
+		ProjectFilter: &projectKey,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	foundMostRecent := false
+	for _, s := range summaries {
+		if s.TargetKey.TargetName != targetName || s.TargetKey.ClusterId != clusterId {
+			continue
+		}
+		if s.Command.Command != "deploy" || s.Command.DryRun {
+			continue
+		}
+		if !foundMostRecent {
+			foundMostRecent = true
+			continue
+		}
+		if !isSuccessfulDeploy(&s) {
+			continue
+		}
+		n--
+		if n == 0 {
+			return store.GetCommandResult(results.GetCommandResultOptions{
+				Id: s.Id,
+			})
+		}
+	}
+	return nil, nil
+}
+
+// collectRollbackObjects returns the rendered objects of a command result that should be re-applied when rolling back
+// to it. Hooks are not re-applied. Secrets are skipped as well, as their content is obfuscated in stored command
+// results. All rendered refs are returned as well, including the skipped ones.
+func collectRollbackObjects(cr *result.CommandResult, dew *utils2.DeploymentErrorsAndWarnings) ([]*uo.UnstructuredObject, map[k8s2.ObjectRef]bool) {
+	var objects []*uo.UnstructuredObject
+	renderedRefs := map[k8s2.ObjectRef]bool{}
+	for _, x := range cr.Objects {
+		if x.Rendered == nil {
+			continue
+		}
+		renderedRefs[x.Ref] = true
+		if x.Hook || x.Rendered.GetK8sAnnotation("kluctl.io/hook") != nil || x.Rendered.GetK8sAnnotation("helm.sh/hook") != nil {
+			continue
+		}
+		if utils.ParseBoolOrFalse(x.Rendered.GetK8sAnnotation("kluctl.io/delete")) {
+			continue
+		}
+		if x.Ref.Group == "" && x.Ref.Kind == "Secret" {
+			dew.AddWarning(x.Ref, fmt.Errorf("secrets are not rolled back, as stored command results do not contain secret data"))
+			continue
+		}
+		objects = append(objects, x.Rendered)
+	}
+
+	// namespaces and CRDs must exist before the objects that depend on them are applied
+	order := func(x *uo.UnstructuredObject) int {
+		switch x.GetK8sGVK().GroupKind().String() {
+		case "Namespace":
+			return 0
+		case "CustomResourceDefinition.apiextensions.k8s.io":
+			return 1
+		default:
+			return 2
+		}
+	}
+	sort.SliceStable(objects, func(i, j int) bool {
+		return order(objects[i]) < order(objects[j])
+	})
+
+	return objects, renderedRefs
+}
+
+func applyRollbackObjects(ctx context.Context, k *k8s.K8sCluster, dew *utils2.DeploymentErrorsAndWarnings, ru *utils2.RemoteObjectUtils, o *utils2.ApplyUtilOptions, resultId string, objects []*uo.UnstructuredObject) *utils2.ApplyDeploymentsUtil {
+	ad := utils2.NewApplyDeploymentsUtil(ctx, dew, ru, k, o)
+
+	s := status.StartWithOptions(ctx,
+		status.WithStatus("Rolling back to command result %s", resultId),
+		status.WithTotal(len(objects)),
+	)
+	defer s.Failed()
+
+	au := ad.NewApplyUtil(ctx, s)
+	for i, x := range objects {
+		if o.AbortOnError && len(dew.GetErrorsList()) != 0 {
+			break
+		}
+		s.Update(fmt.Sprintf("Applying object %s (%d of %d)", x.GetK8sRef().String(), i+1, len(objects)))
+		au.ApplyObject(x, false, false)
+		s.Increment()
+	}
+
+	if len(dew.GetErrorsList()) == 0 {
+		s.UpdateAndInfoFallback("Applied %d objects of command result %s", len(ad.GetAppliedObjects()), resultId)
+		s.Success()
+	}
+	return ad
+}
+
+type rollbackResult struct {
+	applied []*uo.UnstructuredObject
+	deleted []k8s2.ObjectRef
+}
+
+// rollbackToCommandResult re-applies the rendered objects of a previous command result and then deletes all objects
+// from newObjects that are not part of the previous result.
+func rollbackToCommandResult(ctx context.Context, k *k8s.K8sCluster, dew *utils2.DeploymentErrorsAndWarnings, o utils2.ApplyUtilOptions, prev *result.CommandResult, newObjects []k8s2.ObjectRef) *rollbackResult {
+	objects, prevRefs := collectRollbackObjects(prev, dew)
+
+	var refs []k8s2.ObjectRef
+	for _, x := range objects {
+		refs = append(refs, x.GetK8sRef())
+	}
+
+	ru := utils2.NewRemoteObjectsUtil(ctx, dew)
+	err := ru.UpdateRemoteObjects(k, &prev.Target.Discriminator, refs, false)
+	if err != nil {
+		dew.AddError(k8s2.ObjectRef{}, err)
+		return &rollbackResult{}
+	}
+
+	o.DryRun = k.DryRun
+	o.AbortOnError = false
+	ad := applyRollbackObjects(ctx, k, dew, ru, &o, prev.Id, objects)
+
+	var toDelete []k8s2.ObjectRef
+	for _, ref := range newObjects {
+		if !prevRefs[ref] {
+			toDelete = append(toDelete, ref)
+		}
+	}
+	var deleted []k8s2.ObjectRef
+	if len(toDelete) != 0 {
+		deleted = utils2.DeleteObjects(ctx, k, toDelete, dew, false)
+	}
+
+	return &rollbackResult{
+		applied: ad.GetAppliedObjects(),
+		deleted: deleted,
+	}
+}
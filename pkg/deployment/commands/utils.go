@@ -5,10 +5,19 @@ import (
 	"github.com/kluctl/kluctl/v2/pkg/deployment/utils"
 	"github.com/kluctl/kluctl/v2/pkg/types/k8s"
 	"github.com/kluctl/kluctl/v2/pkg/types/result"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
 	"sort"
 )
 
 func collectObjects(c *deployment.DeploymentCollection, ru *utils.RemoteObjectUtils, au *utils.ApplyDeploymentsUtil, du *utils.DiffUtil, orphans []k8s.ObjectRef, deleted []k8s.ObjectRef) []result.ResultObject {
+	var rendered []*uo.UnstructuredObject
+	if c != nil {
+		rendered = c.LocalObjects()
+	}
+	return collectObjectsFromRendered(rendered, ru, au, du, orphans, deleted)
+}
+
+func collectObjectsFromRendered(rendered []*uo.UnstructuredObject, ru *utils.RemoteObjectUtils, au *utils.ApplyDeploymentsUtil, du *utils.DiffUtil, orphans []k8s.ObjectRef, deleted []k8s.ObjectRef) []result.ResultObject {
 	m := map[k8s.ObjectRef]*result.ResultObject{}
 	remoteDiffNames := map[k8s.ObjectRef]k8s.ObjectRef{}
 	appliedDiffNames := map[k8s.ObjectRef]k8s.ObjectRef{}
@@ -23,12 +32,10 @@ func collectObjects(c *deployment.DeploymentCollection, ru *utils.RemoteObjectUt
 		return x
 	}
 
-	if c != nil {
-		for _, x := range c.LocalObjects() {
-			dn := du.GetDiffRef(x)
-			o := getOrCreate(dn)
-			o.Rendered = x
-		}
+	for _, x := range rendered {
+		dn := du.GetDiffRef(x)
+		o := getOrCreate(dn)
+		o.Rendered = x
 	}
 	if ru != nil {
 		for _, x := range ru.GetFilteredRemoteObjects(nil) {
@@ -7,6 +7,7 @@ import (
 	"github.com/kluctl/kluctl/v2/pkg/deployment"
 	utils2 "github.com/kluctl/kluctl/v2/pkg/deployment/utils"
 	"github.com/kluctl/kluctl/v2/pkg/k8s"
+	"github.com/kluctl/kluctl/v2/pkg/types"
 	k8s2 "github.com/kluctl/kluctl/v2/pkg/types/k8s"
 	"github.com/kluctl/kluctl/v2/pkg/types/result"
 	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
@@ -21,6 +22,8 @@ type ValidateCommand struct {
 
 	dew *utils2.DeploymentErrorsAndWarnings
 	ru  *utils2.RemoteObjectUtils
+
+	ApplyConcurrency int
 }
 
 func NewValidateCommand(ctx context.Context, discriminator string, c *deployment.DeploymentCollection, r *result.CommandResult) *ValidateCommand {
@@ -47,6 +50,7 @@ func (cmd *ValidateCommand) Run(ctx context.Context, k *k8s.K8sCluster) (*result
 	var renderedObjects []*uo.UnstructuredObject
 	appliedObjects := map[k8s2.ObjectRef]*uo.UnstructuredObject{}
 	var discriminator string
+	var healthCheckConfigs []*types.HealthCheckConfig
 
 	if cmd.c != nil && cmd.r != nil {
 		return nil, fmt.Errorf("passing both deployment collection and command result is not allowed")
@@ -58,6 +62,7 @@ func (cmd *ValidateCommand) Run(ctx context.Context, k *k8s.K8sCluster) (*result
 				renderedObjects = append(renderedObjects, o)
 			}
 		}
+		healthCheckConfigs = cmd.c.Project.GetHealthChecks()
 	} else if cmd.r != nil {
 		for _, o := range cmd.r.Objects {
 			refs = append(refs, o.Ref)
@@ -72,6 +77,7 @@ func (cmd *ValidateCommand) Run(ctx context.Context, k *k8s.K8sCluster) (*result
 			}
 		}
 		discriminator = cmd.r.TargetKey.Discriminator
+		healthCheckConfigs = collectHealthChecks(cmd.r.Deployment)
 	} else {
 		return nil, fmt.Errorf("either deployment collection or command result must be passed")
 	}
@@ -80,7 +86,12 @@ func (cmd *ValidateCommand) Run(ctx context.Context, k *k8s.K8sCluster) (*result
 		discriminator = cmd.discriminator
 	}
 
-	err := cmd.ru.UpdateRemoteObjects(k, &cmd.discriminator, refs, true)
+	healthChecks, err := validation.NewHealthChecks(healthCheckConfigs)
+	if err != nil {
+		return nil, err
+	}
+
+	err = cmd.ru.UpdateRemoteObjects(k, &cmd.discriminator, refs, true)
 	if err != nil {
 		return nil, err
 	}
@@ -101,7 +112,7 @@ func (cmd *ValidateCommand) Run(ctx context.Context, k *k8s.K8sCluster) (*result
 			ret.Errors = append(ret.Errors, result.DeploymentError{Ref: ref, Message: "object not found"})
 			continue
 		}
-		r := validation.ValidateObject(k, remoteObject, true, false)
+		r := validation.ValidateObject(k, remoteObject, true, false, healthChecks)
 		if !r.Ready {
 			ret.Ready = false
 		}
@@ -112,6 +123,7 @@ func (cmd *ValidateCommand) Run(ctx context.Context, k *k8s.K8sCluster) (*result
 
 	du := utils2.NewDiffUtil(cmd.dew, cmd.ru, appliedObjects)
 	du.Swapped = true
+	du.Concurrency = cmd.ApplyConcurrency
 	du.DiffObjects(renderedObjects)
 
 	ret.Warnings = append(ret.Warnings, cmd.dew.GetWarningsList()...)
@@ -126,6 +138,18 @@ func (cmd *ValidateCommand) Run(ctx context.Context, k *k8s.K8sCluster) (*result
 	return &ret, nil
 }
 
+// collectHealthChecks collects the health checks from a deployment project config, including all rendered includes
+func collectHealthChecks(c *types.DeploymentProjectConfig) []*types.HealthCheckConfig {
+	if c == nil {
+		return nil
+	}
+	ret := append([]*types.HealthCheckConfig{}, c.HealthChecks...)
+	for _, d := range c.Deployments {
+		ret = append(ret, collectHealthChecks(d.RenderedInclude)...)
+	}
+	return ret
+}
+
 func (cmd *ValidateCommand) ForgetRemoteObject(ref k8s2.ObjectRef) {
 	cmd.ru.ForgetRemoteObject(ref)
 }
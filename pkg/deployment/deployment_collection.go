@@ -37,7 +37,12 @@ func NewDeploymentCollection(ctx SharedContext, project *DeploymentProject, imag
 	}
 
 	indexes := make(map[string]int)
-	deployments, err := dc.collectAllDeployments(project, indexes)
+	deployments, err := dc.collectAllDeployments(project, indexes, nil)
+	if err != nil {
+		return nil, err
+	}
+	resolveDependencies(deployments)
+	err = CheckDependencyCycles(deployments)
 	if err != nil {
 		return nil, err
 	}
@@ -83,7 +88,7 @@ func findDeploymentItemIndex(project *DeploymentProject, pth *string, indexes ma
 	return index, dir2
 }
 
-func (c *DeploymentCollection) collectAllDeployments(project *DeploymentProject, indexes map[string]int) ([]*DeploymentItem, error) {
+func (c *DeploymentCollection) collectAllDeployments(project *DeploymentProject, indexes map[string]int, inheritedDependsOn []string) ([]*DeploymentItem, error) {
 	var ret []*DeploymentItem
 
 	if x, err := project.CheckWhenTrue(); !x || err != nil {
@@ -99,12 +104,21 @@ func (c *DeploymentCollection) collectAllDeployments(project *DeploymentProject,
 			continue
 		}
 
+		dependsOn := append([]string{}, inheritedDependsOn...)
+		for _, dep := range diConfig.DependsOn {
+			dir, err := project.getDependsOnDir(dep)
+			if err != nil {
+				return nil, err
+			}
+			dependsOn = append(dependsOn, dir)
+		}
+
 		if diConfig.Include != nil || diConfig.Git != nil {
 			includedProject, ok := project.includes[i]
 			if !ok {
 				panic(fmt.Sprintf("Did not find find index %d in project.includes", i))
 			}
-			ret2, err := c.collectAllDeployments(includedProject, indexes)
+			ret2, err := c.collectAllDeployments(includedProject, indexes, dependsOn)
 			if err != nil {
 				return nil, err
 			}
@@ -118,6 +132,7 @@ func (c *DeploymentCollection) collectAllDeployments(project *DeploymentProject,
 			if err != nil {
 				return nil, err
 			}
+			di.dependsOnDirs = dependsOn
 			ret = append(ret, di)
 		}
 	}
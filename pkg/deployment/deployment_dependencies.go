@@ -0,0 +1,133 @@
+package deployment
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// resolveDependencies resolves the dependsOn directories of all deployment items to the actual deployment items.
+// A dependsOn directory matches all deployment items that are located inside this directory, which also allows to
+// depend on includes.
+func resolveDependencies(deployments []*DeploymentItem) {
+	for _, d := range deployments {
+		d.DependsOn = nil
+		m := map[*DeploymentItem]bool{}
+		for _, depDir := range d.dependsOnDirs {
+			for _, d2 := range deployments {
+				if d2 == d || d2.dir == nil || m[d2] {
+					continue
+				}
+				if *d2.dir != depDir && !strings.HasPrefix(*d2.dir, depDir+string(filepath.Separator)) {
+					continue
+				}
+				m[d2] = true
+				d.DependsOn = append(d.DependsOn, d2)
+				d2.HasDependents = true
+			}
+		}
+	}
+}
+
+func (di *DeploymentItem) dependencyDisplayName() string {
+	if di.RelToSourceItemDir != "" {
+		return filepath.ToSlash(di.RelToSourceItemDir)
+	}
+	if di.Config.Barrier || di.Barrier {
+		return "<barrier>"
+	}
+	if len(di.Config.DeleteObjects) != 0 {
+		return "<delete>"
+	}
+	return "<unnamed>"
+}
+
+// CheckDependencyCycles ensures that dependsOn together with barriers does not lead to dead-locks while applying
+// the deployment items. Barriers are treated as additional dependencies of all following deployment items.
+// Dependencies to deployment items that are not part of the passed list are ignored.
+func CheckDependencyCycles(deployments []*DeploymentItem) error {
+	indexes := make(map[*DeploymentItem]int, len(deployments))
+	for i, d := range deployments {
+		indexes[d] = i
+	}
+
+	// the first len(deployments) nodes are the deployment items, all following nodes are the barriers
+	edges := make([][]int, len(deployments))
+	lastBarrierNode := -1
+	barrierStart := 0
+	for i, d := range deployments {
+		if lastBarrierNode != -1 {
+			edges[i] = append(edges[i], lastBarrierNode)
+		}
+		for _, dep := range d.DependsOn {
+			if j, ok := indexes[dep]; ok {
+				edges[i] = append(edges[i], j)
+			}
+		}
+
+		if d.Config.Barrier || d.Barrier {
+			// a barrier waits for all previous deployment items, including itself
+			var barrierEdges []int
+			if lastBarrierNode != -1 {
+				barrierEdges = append(barrierEdges, lastBarrierNode)
+			}
+			for j := barrierStart; j <= i; j++ {
+				barrierEdges = append(barrierEdges, j)
+			}
+			edges = append(edges, barrierEdges)
+			lastBarrierNode = len(edges) - 1
+			barrierStart = i + 1
+		}
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make([]int, len(edges))
+	var stack []int
+
+	var visit func(n int) []int
+	visit = func(n int) []int {
+		state[n] = visiting
+		stack = append(stack, n)
+		for _, e := range edges[n] {
+			switch state[e] {
+			case visiting:
+				for i := len(stack) - 1; i >= 0; i-- {
+					if stack[i] == e {
+						return append(append([]int{}, stack[i:]...), e)
+					}
+				}
+			case unvisited:
+				if c := visit(e); c != nil {
+					return c
+				}
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[n] = visited
+		return nil
+	}
+
+	for n := range edges {
+		if state[n] != unvisited {
+			continue
+		}
+		cycle := visit(n)
+		if cycle == nil {
+			continue
+		}
+		var names []string
+		for _, c := range cycle {
+			if c < len(deployments) {
+				names = append(names, deployments[c].dependencyDisplayName())
+			} else {
+				names = append(names, "<barrier>")
+			}
+		}
+		return fmt.Errorf("dependency cycle detected: %s", strings.Join(names, " -> "))
+	}
+	return nil
+}
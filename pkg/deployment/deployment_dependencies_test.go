@@ -0,0 +1,95 @@
+package deployment
+
+import (
+	"github.com/kluctl/kluctl/v2/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"path/filepath"
+	"testing"
+)
+
+func newTestDependencyItem(dir string, barrier bool, dependsOn ...string) *DeploymentItem {
+	var absDir *string
+	if dir != "" {
+		x := filepath.Join("/project", dir)
+		absDir = &x
+	}
+	var dependsOnDirs []string
+	for _, dep := range dependsOn {
+		dependsOnDirs = append(dependsOnDirs, filepath.Join("/project", dep))
+	}
+	return &DeploymentItem{
+		Config:             &types.DeploymentItemConfig{Barrier: barrier},
+		dir:                absDir,
+		dependsOnDirs:      dependsOnDirs,
+		RelToSourceItemDir: dir,
+	}
+}
+
+func TestResolveDependencies(t *testing.T) {
+	a := newTestDependencyItem("a", false)
+	b1 := newTestDependencyItem("inc/b1", false)
+	b2 := newTestDependencyItem("inc/b2", false)
+	c := newTestDependencyItem("c", false, "inc", "a")
+	d := newTestDependencyItem("inc2", false, "a")
+
+	l := []*DeploymentItem{a, b1, b2, c, d}
+	resolveDependencies(l)
+
+	assert.Equal(t, []*DeploymentItem{b1, b2, a}, c.DependsOn)
+	assert.Equal(t, []*DeploymentItem{a}, d.DependsOn)
+	assert.Empty(t, a.DependsOn)
+	assert.True(t, a.HasDependents)
+	assert.True(t, b1.HasDependents)
+	assert.False(t, c.HasDependents)
+	assert.NoError(t, CheckDependencyCycles(l))
+}
+
+func TestCheckDependencyCycles(t *testing.T) {
+	type testCase struct {
+		name  string
+		items []*DeploymentItem
+		err   string
+	}
+
+	tests := []testCase{
+		{name: "forward-dependency", items: []*DeploymentItem{
+			newTestDependencyItem("a", false, "b"),
+			newTestDependencyItem("b", false),
+		}},
+		{name: "simple-cycle", items: []*DeploymentItem{
+			newTestDependencyItem("a", false, "b"),
+			newTestDependencyItem("b", false, "a"),
+		}, err: "dependency cycle detected: a -> b -> a"},
+		{name: "indirect-cycle", items: []*DeploymentItem{
+			newTestDependencyItem("a", false, "c"),
+			newTestDependencyItem("b", false, "a"),
+			newTestDependencyItem("c", false, "b"),
+		}, err: "dependency cycle detected: a -> c -> b -> a"},
+		{name: "barrier-backward", items: []*DeploymentItem{
+			newTestDependencyItem("a", false),
+			newTestDependencyItem("", true),
+			newTestDependencyItem("b", false, "a"),
+		}},
+		{name: "depends-on-barrier-item", items: []*DeploymentItem{
+			newTestDependencyItem("a", false, "b"),
+			newTestDependencyItem("b", true),
+		}},
+		{name: "barrier-forward", items: []*DeploymentItem{
+			newTestDependencyItem("a", false, "b"),
+			newTestDependencyItem("", true),
+			newTestDependencyItem("b", false),
+		}, err: "dependency cycle detected: a -> b -> <barrier> -> a"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			resolveDependencies(tc.items)
+			err := CheckDependencyCycles(tc.items)
+			if tc.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.err)
+			}
+		})
+	}
+}
@@ -36,6 +36,11 @@ type DeploymentItem struct {
 	Barrier       bool
 	WaitReadiness bool
 
+	// These values are resolved from dependsOn
+	DependsOn     []*DeploymentItem
+	HasDependents bool
+	dependsOnDirs []string
+
 	Objects []*uo.UnstructuredObject
 	Tags    *utils.OrderedMap
 
@@ -395,6 +400,21 @@ func (di *DeploymentItem) CheckInclusionForDeploy() bool {
 	return di.Inclusion.CheckIncluded(values, false)
 }
 
+// GetRelSourceFile returns the path of the file that defines this deployment item, relative to the source root. This is
+// the kustomization.yml or helm-chart.yml if present, or the item directory otherwise.
+func (di *DeploymentItem) GetRelSourceFile() string {
+	if di.dir == nil {
+		return ""
+	}
+	for _, n := range []string{"kustomization.yml", "helm-chart.yml"} {
+		p := yaml.FixPathExt(filepath.Join(*di.dir, n))
+		if utils.IsFile(p) {
+			return filepath.ToSlash(filepath.Join(di.RelToSourceItemDir, filepath.Base(p)))
+		}
+	}
+	return filepath.ToSlash(di.RelToSourceItemDir)
+}
+
 func (di *DeploymentItem) readKustomizationYaml(subDir string) (*uo.UnstructuredObject, error) {
 	if di.dir == nil {
 		return nil, nil
@@ -152,6 +152,13 @@ func (p *DeploymentProject) processConfig() error {
 
 func (p *DeploymentProject) checkDeploymentDirs() error {
 	for _, di := range p.Config.Deployments {
+		for _, dep := range di.DependsOn {
+			_, err := p.getDependsOnDir(dep)
+			if err != nil {
+				return err
+			}
+		}
+
 		if di.Path == nil {
 			continue
 		}
@@ -175,6 +182,23 @@ func (p *DeploymentProject) checkDeploymentDirs() error {
 	return nil
 }
 
+// getDependsOnDir resolves a single dependsOn entry, which is relative to the directory of the deployment.yaml,
+// to an absolute directory
+func (p *DeploymentProject) getDependsOnDir(dep string) (string, error) {
+	dir, err := securejoin.SecureJoin(p.source.dir, filepath.Join(p.relDir, dep))
+	if err != nil {
+		return "", err
+	}
+	if !strings.HasPrefix(dir, p.source.dir) {
+		return "", fmt.Errorf("dependsOn is not part of the deployment project: %s", dep)
+	}
+	if !utils.IsDirectory(dir) {
+		return "", fmt.Errorf("dependsOn directory does not exist: %s", dep)
+	}
+	// build it the same way as deployment item directories are built, so that they can be compared later
+	return filepath.Abs(filepath.Join(p.absDir, dep))
+}
+
 func (p *DeploymentProject) CheckWhenTrue() (bool, error) {
 	if p.parentProject == nil && p.Config.When != "" {
 		return false, fmt.Errorf("the root deployment project can not contain 'when'")
@@ -286,6 +310,15 @@ func (p *DeploymentProject) getChildren(recursive bool, includeSelf bool) []*Dep
 	return children
 }
 
+// GetHealthChecks returns the health checks of this project and all its includes
+func (p *DeploymentProject) GetHealthChecks() []*types.HealthCheckConfig {
+	var ret []*types.HealthCheckConfig
+	for _, c := range p.getChildren(true, true) {
+		ret = append(ret, c.Config.HealthChecks...)
+	}
+	return ret
+}
+
 func (p *DeploymentProject) getRenderSearchDirs() []string {
 	var ret []string
 	for _, d := range p.getParents() {
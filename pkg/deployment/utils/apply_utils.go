@@ -25,6 +25,9 @@ import (
 	"time"
 )
 
+// DefaultApplyConcurrency is used when ApplyUtilOptions.Concurrency is not set
+const DefaultApplyConcurrency = 8
+
 type ApplyUtilOptions struct {
 	ForceApply          bool
 	ReplaceOnError      bool
@@ -33,6 +36,8 @@ type ApplyUtilOptions struct {
 	AbortOnError        bool
 	ReadinessTimeout    time.Duration
 	NoWait              bool
+	Concurrency         int
+	HealthChecks        *validation.HealthChecks
 }
 
 type ApplyUtil struct {
@@ -114,11 +119,27 @@ func (ad *ApplyDeploymentsUtil) NewApplyUtil(ctx context.Context, statusCtx *sta
 	return ret
 }
 
+// reportObjectAction informs the status handler about an action performed on an object. Nothing is reported in
+// dry-run mode, as the object was not actually modified.
+func (a *ApplyUtil) reportObjectAction(action status.ObjectAction, ref k8s2.ObjectRef) {
+	if a.o.DryRun {
+		return
+	}
+	status.ReportObjectAction(a.ctx, action, ref)
+}
+
 func (a *ApplyUtil) handleResult(appliedObject *uo.UnstructuredObject, hook bool) {
+	a.handleResultWithAction(appliedObject, hook, status.ObjectApplied)
+}
+
+func (a *ApplyUtil) handleResultWithAction(appliedObject *uo.UnstructuredObject, hook bool, action status.ObjectAction) {
 	a.mutex.Lock()
 	defer a.mutex.Unlock()
 
 	ref := appliedObject.GetK8sRef()
+	if !hook {
+		a.reportObjectAction(action, ref)
+	}
 	if hook {
 		a.appliedHookObjects[ref] = appliedObject
 	}
@@ -173,6 +194,7 @@ func (a *ApplyUtil) DeleteObject(ref k8s2.ObjectRef, hook bool) bool {
 	a.handleApiWarnings(ref, apiWarnings)
 
 	if err == nil {
+		a.reportObjectAction(status.ObjectDeleted, ref)
 		a.mutex.Lock()
 		defer a.mutex.Unlock()
 		if hook {
@@ -210,6 +232,7 @@ func (a *ApplyUtil) DeleteObject(ref k8s2.ObjectRef, hook bool) bool {
 
 	// it got applied, so we need to pretend it actually got deleted
 
+	a.reportObjectAction(status.ObjectDeleted, ref)
 	a.mutex.Lock()
 	defer a.mutex.Unlock()
 	if hook {
@@ -256,9 +279,9 @@ func (a *ApplyUtil) retryApplyForceReplace(x *uo.UnstructuredObject, hook bool,
 			a.HandleError(ref, err)
 			return
 		}
-		a.handleResult(r, hook)
+		a.handleResultWithAction(r, hook, status.ObjectReplaced)
 	} else {
-		a.handleResult(x, hook)
+		a.handleResultWithAction(x, hook, status.ObjectReplaced)
 	}
 }
 
@@ -288,7 +311,7 @@ func (a *ApplyUtil) retryApplyWithReplace(x *uo.UnstructuredObject, hook bool, r
 		a.retryApplyForceReplace(x, hook, remoteObject, err)
 		return
 	}
-	a.handleResult(r, hook)
+	a.handleResultWithAction(r, hook, status.ObjectReplaced)
 }
 
 func (a *ApplyUtil) retryApplyWithConflicts(x *uo.UnstructuredObject, hook bool, remoteObject *uo.UnstructuredObject, applyError error) {
@@ -453,7 +476,7 @@ func (a *ApplyUtil) WaitReadiness(ref k8s2.ObjectRef, timeout time.Duration) boo
 			a.HandleError(ref, err)
 			return false
 		}
-		v := validation.ValidateObject(a.k, o, false, false)
+		v := validation.ValidateObject(a.k, o, false, false, a.o.HealthChecks)
 		if v.Ready {
 			if didLog {
 				a.sctx.InfoFallback("Finished waiting for %s (%ds elapsed)", ref.String(), elapsed)
@@ -596,7 +619,8 @@ func (a *ApplyUtil) applyDeploymentItem(d *deployment.DeploymentItem) {
 			didLog = true
 		}
 
-		waitReadiness := d.Config.WaitReadiness || d.WaitReadiness || utils.ParseBoolOrFalse(o.GetK8sAnnotation("kluctl.io/wait-readiness"))
+		// deployment items that others depend on must be ready before the dependent items are applied
+		waitReadiness := d.Config.WaitReadiness || d.WaitReadiness || d.HasDependents || utils.ParseBoolOrFalse(o.GetK8sAnnotation("kluctl.io/wait-readiness"))
 		if !a.o.NoWait && waitReadiness {
 			a.WaitReadiness(o.GetK8sRef(), 0)
 		}
@@ -650,12 +674,70 @@ func (a *ApplyDeploymentsUtil) buildProgressName(d *deployment.DeploymentItem) *
 	return nil
 }
 
+// itemDone is used to signal dependent deployment items that a deployment item is finished
+type itemDone struct {
+	item *deployment.DeploymentItem
+	ch   chan struct{}
+
+	// failed is only valid after ch got closed
+	failed bool
+}
+
+// waitForDependencies waits for all dependencies to finish and returns the first dependency that failed or got
+// skipped. ok is false if waiting got interrupted.
+func (a *ApplyDeploymentsUtil) waitForDependencies(deps []*itemDone) (failed *itemDone, ok bool) {
+	for _, dep := range deps {
+		select {
+		case <-dep.ch:
+		case <-a.ctx.Done():
+			return nil, false
+		}
+	}
+	for _, dep := range deps {
+		if dep.failed {
+			return dep, true
+		}
+	}
+	return nil, true
+}
+
+func (a *ApplyDeploymentsUtil) buildDependencyError(d *deployment.DeploymentItem, dep *deployment.DeploymentItem) error {
+	name := "<unnamed>"
+	if n := a.buildProgressName(d); n != nil {
+		name = *n
+	}
+	depName := "<unnamed>"
+	if n := a.buildProgressName(dep); n != nil {
+		depName = *n
+	}
+	return fmt.Errorf("skipped deployment item %s because its dependency %s failed", name, depName)
+}
+
 func (a *ApplyDeploymentsUtil) ApplyDeployments(deployments []*deployment.DeploymentItem) {
 	s := status.Start(a.ctx, "Running server-side apply for all objects")
 	defer s.Failed()
 
+	// barriers from kustomization.yaml annotations are only known at this point, so we need to re-check for cycles
+	err := deployment.CheckDependencyCycles(deployments)
+	if err != nil {
+		a.dew.AddError(k8s2.ObjectRef{}, err)
+		return
+	}
+
+	concurrency := a.o.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultApplyConcurrency
+	}
+
 	var wg sync.WaitGroup
-	sem := semaphore.NewWeighted(8)
+	sem := semaphore.NewWeighted(int64(concurrency))
+
+	// each channel is closed when the corresponding deployment item is finished or got skipped
+	doneChs := make(map[*deployment.DeploymentItem]*itemDone, len(deployments))
+	for _, d := range deployments {
+		doneChs[d] = &itemDone{item: d, ch: make(chan struct{})}
+	}
+	started := map[*deployment.DeploymentItem]bool{}
 
 	maxNameLen := 0
 	for _, d := range deployments {
@@ -673,7 +755,18 @@ func (a *ApplyDeploymentsUtil) ApplyDeployments(deployments []*deployment.Deploy
 			break
 		}
 
-		_ = sem.Acquire(context.Background(), 1)
+		var deps []*itemDone
+		for _, dep := range d.DependsOn {
+			if done, ok := doneChs[dep]; ok {
+				deps = append(deps, done)
+			}
+		}
+
+		// deployment items with dependencies acquire the semaphore after their dependencies are finished, so that
+		// they don't block other deployment items while waiting
+		if len(deps) == 0 {
+			_ = sem.Acquire(context.Background(), 1)
+		}
 
 		progressName := a.buildProgressName(d)
 		var sctx *status.StatusContext
@@ -686,12 +779,35 @@ func (a *ApplyDeploymentsUtil) ApplyDeployments(deployments []*deployment.Deploy
 		}
 		a2 := a.NewApplyUtil(a.ctx, sctx)
 
+		started[d] = true
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
+			done := doneChs[d]
+			defer close(done.ch)
+
+			if len(deps) != 0 {
+				sctx.Update("Waiting for dependencies...")
+				failedDep, ok := a.waitForDependencies(deps)
+				if !ok || a.abortSignal.Load().(bool) {
+					done.failed = true
+					sctx.Failed()
+					return
+				}
+				if failedDep != nil {
+					// dependents of this item are skipped as well, as it is marked as failed
+					done.failed = true
+					a2.HandleError(k8s2.ObjectRef{}, a.buildDependencyError(d, failedDep.item))
+					sctx.Update("Skipped due to failed dependency")
+					sctx.Failed()
+					return
+				}
+				_ = sem.Acquire(context.Background(), 1)
+			}
 			defer sem.Release(1)
 
 			a2.applyDeploymentItem(d)
+			done.failed = a2.errorCount != 0
 
 			// if success was not signalled, get into failed status
 			sctx.Failed()
@@ -709,6 +825,15 @@ func (a *ApplyDeploymentsUtil) ApplyDeployments(deployments []*deployment.Deploy
 			sctx.Success()
 		}
 	}
+
+	// release all deployment items that still wait for skipped dependencies (e.g. due to abortion)
+	for _, d := range deployments {
+		if !started[d] {
+			doneChs[d].failed = true
+			close(doneChs[d].ch)
+		}
+	}
+
 	wg.Wait()
 	s.Success()
 }
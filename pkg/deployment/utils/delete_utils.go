@@ -3,6 +3,7 @@ package utils
 import (
 	"context"
 	"github.com/kluctl/kluctl/v2/pkg/k8s"
+	"github.com/kluctl/kluctl/v2/pkg/status"
 	k8s2 "github.com/kluctl/kluctl/v2/pkg/types/k8s"
 	"github.com/kluctl/kluctl/v2/pkg/utils"
 	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
@@ -176,6 +177,9 @@ func DeleteObjects(ctx context.Context, k *k8s.K8sCluster, refs []k8s2.ObjectRef
 
 		if err == nil {
 			ret = append(ret, ref)
+			if !k.DryRun {
+				status.ReportObjectAction(ctx, status.ObjectDeleted, ref)
+			}
 		} else {
 			dew.AddError(ref, err)
 		}
@@ -1,12 +1,15 @@
 package utils
 
 import (
+	"context"
 	"github.com/kluctl/kluctl/v2/pkg/deployment"
 	"github.com/kluctl/kluctl/v2/pkg/diff"
 	"github.com/kluctl/kluctl/v2/pkg/types"
 	k8s2 "github.com/kluctl/kluctl/v2/pkg/types/k8s"
 	"github.com/kluctl/kluctl/v2/pkg/types/result"
 	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"golang.org/x/sync/semaphore"
+	"runtime"
 	"sort"
 	"sync"
 	"time"
@@ -22,6 +25,9 @@ type DiffUtil struct {
 	IgnoreAnnotations bool
 	Swapped           bool
 
+	// Concurrency limits the number of objects diffed in parallel. Defaults to the number of CPUs.
+	Concurrency int
+
 	remoteDiffObjects map[k8s2.ObjectRef]*uo.UnstructuredObject
 	ChangedObjects    []result.ChangedObject
 	mutex             sync.Mutex
@@ -37,12 +43,21 @@ func NewDiffUtil(dew *DeploymentErrorsAndWarnings, ru *RemoteObjectUtils, applie
 	return u
 }
 
+func (u *DiffUtil) newSemaphore() *semaphore.Weighted {
+	concurrency := u.Concurrency
+	if concurrency <= 0 {
+		concurrency = runtime.NumCPU()
+	}
+	return semaphore.NewWeighted(int64(concurrency))
+}
+
 func (u *DiffUtil) DiffDeploymentItems(deployments []*deployment.DeploymentItem) {
 	var wg sync.WaitGroup
+	sem := u.newSemaphore()
 
 	for _, d := range deployments {
 		ignoreForDiffs := d.Project.GetIgnoreForDiffs(u.IgnoreTags, u.IgnoreLabels, u.IgnoreAnnotations)
-		u.diffObjects(d.Objects, ignoreForDiffs, &wg)
+		u.diffObjects(d.Objects, ignoreForDiffs, &wg, sem)
 	}
 	wg.Wait()
 
@@ -51,7 +66,7 @@ func (u *DiffUtil) DiffDeploymentItems(deployments []*deployment.DeploymentItem)
 
 func (u *DiffUtil) DiffObjects(objects []*uo.UnstructuredObject) {
 	var wg sync.WaitGroup
-	u.diffObjects(objects, nil, &wg)
+	u.diffObjects(objects, nil, &wg, u.newSemaphore())
 	wg.Wait()
 	u.sortChanges()
 }
@@ -62,7 +77,7 @@ func (u *DiffUtil) sortChanges() {
 	})
 }
 
-func (u *DiffUtil) diffObjects(objects []*uo.UnstructuredObject, ignoreForDiffs []*types.IgnoreForDiffItemConfig, wg *sync.WaitGroup) {
+func (u *DiffUtil) diffObjects(objects []*uo.UnstructuredObject, ignoreForDiffs []*types.IgnoreForDiffItemConfig, wg *sync.WaitGroup, sem *semaphore.Weighted) {
 	for _, o := range objects {
 		o := o
 		ref := o.GetK8sRef()
@@ -73,9 +88,11 @@ func (u *DiffUtil) diffObjects(objects []*uo.UnstructuredObject, ignoreForDiffs
 		}
 		diffRef, ro := u.getRemoteObjectForDiff(o)
 
+		_ = sem.Acquire(context.Background(), 1)
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
+			defer sem.Release(1)
 			if u.Swapped {
 				u.diffObject(o, diffRef, ro, ao, ignoreForDiffs)
 			} else {
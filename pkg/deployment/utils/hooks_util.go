@@ -3,6 +3,7 @@ package utils
 import (
 	"fmt"
 	"github.com/kluctl/kluctl/v2/pkg/deployment"
+	"github.com/kluctl/kluctl/v2/pkg/status"
 	"github.com/kluctl/kluctl/v2/pkg/types/k8s"
 	"github.com/kluctl/kluctl/v2/pkg/utils"
 	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
@@ -108,9 +109,14 @@ func (u *HooksUtil) RunHooks(hooks []*hook) {
 			continue
 		}
 		if !h.wait || u.a.o.NoWait {
+			u.a.reportObjectAction(status.ObjectHookRun, ref)
 			continue
 		}
 		waitResults[ref] = u.a.WaitReadiness(ref, h.timeout)
+		if waitResults[ref] {
+			// hooks that failed or timed out are reported as errors instead
+			u.a.reportObjectAction(status.ObjectHookRun, ref)
+		}
 	}
 
 	var deleteAfterObjects []*hook
@@ -0,0 +1,91 @@
+package git
+
+import (
+	"fmt"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/kluctl/kluctl/v2/pkg/signature"
+	"io"
+	"strings"
+)
+
+// SignatureNamespace is the namespace used by git when creating SSH signatures
+const SignatureNamespace = "git"
+
+// VerifyCommit verifies that the given commit is signed by one of the given keys and returns the fingerprint of
+// the signing key.
+func (g *MirroredGitRepo) VerifyCommit(commitHash string, keys *signature.PublicKeys) (string, error) {
+	if !g.IsLocked() || !g.hasUpdated {
+		panic("tried to verify a commit from a project that is not locked/updated")
+	}
+
+	r, err := g.buildRepositoryObject()
+	if err != nil {
+		return "", err
+	}
+
+	commit, err := r.CommitObject(plumbing.NewHash(commitHash))
+	if err != nil {
+		return "", fmt.Errorf("failed to read commit %s: %w", commitHash, err)
+	}
+
+	payload, err := EncodeWithoutSignature(commit.EncodeWithoutSignature)
+	if err != nil {
+		return "", err
+	}
+	fp, err := keys.Verify(SignatureNamespace, commit.PGPSignature, payload)
+	if err != nil {
+		return "", fmt.Errorf("verification of commit %s failed: %w", commitHash, err)
+	}
+	return fp, nil
+}
+
+// VerifyTag verifies that the given annotated tag is signed by one of the given keys and returns the fingerprint of
+// the signing key.
+func (g *MirroredGitRepo) VerifyTag(tagName string, keys *signature.PublicKeys) (string, error) {
+	if !g.IsLocked() || !g.hasUpdated {
+		panic("tried to verify a tag from a project that is not locked/updated")
+	}
+
+	r, err := g.buildRepositoryObject()
+	if err != nil {
+		return "", err
+	}
+
+	tagName = strings.TrimPrefix(tagName, "refs/tags/")
+	ref, err := r.Tag(tagName)
+	if err != nil {
+		return "", fmt.Errorf("failed to read tag %s: %w", tagName, err)
+	}
+	tag, err := r.TagObject(ref.Hash())
+	if err != nil {
+		if err == plumbing.ErrObjectNotFound {
+			return "", fmt.Errorf("tag %s is not an annotated tag and can not be verified", tagName)
+		}
+		return "", fmt.Errorf("failed to read tag %s: %w", tagName, err)
+	}
+
+	payload, err := EncodeWithoutSignature(tag.EncodeWithoutSignature)
+	if err != nil {
+		return "", err
+	}
+	fp, err := keys.Verify(SignatureNamespace, tag.PGPSignature, payload)
+	if err != nil {
+		return "", fmt.Errorf("verification of tag %s failed: %w", tagName, err)
+	}
+	return fp, nil
+}
+
+// EncodeWithoutSignature returns the payload that is signed for commits and tags
+func EncodeWithoutSignature(encode func(o plumbing.EncodedObject) error) ([]byte, error) {
+	var o plumbing.MemoryObject
+	err := encode(&o)
+	if err != nil {
+		return nil, err
+	}
+	rd, err := o.Reader()
+	if err != nil {
+		return nil, err
+	}
+	defer rd.Close()
+	return io.ReadAll(rd)
+}
@@ -0,0 +1,157 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/kluctl/kluctl/v2/pkg/git/auth"
+	"github.com/kluctl/kluctl/v2/pkg/signature"
+	"github.com/kluctl/kluctl/v2/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/ssh"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newTestGpgEntity(t *testing.T) (*openpgp.Entity, []byte) {
+	e, err := openpgp.NewEntity("Test User", "", "no@mail.com", nil)
+	assert.NoError(t, err)
+
+	buf := bytes.NewBuffer(nil)
+	w, err := armor.Encode(buf, openpgp.PublicKeyType, nil)
+	assert.NoError(t, err)
+	assert.NoError(t, e.Serialize(w))
+	assert.NoError(t, w.Close())
+	return e, buf.Bytes()
+}
+
+func newTestSshSigner(t *testing.T) (*signature.Signer, []byte) {
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	s, err := ssh.NewSignerFromKey(priv)
+	assert.NoError(t, err)
+	return signature.NewSshSigner(s), ssh.MarshalAuthorizedKey(s.PublicKey())
+}
+
+func commitFile(t *testing.T, gs *TestGitServer, repo string, name string, signKey *openpgp.Entity) plumbing.Hash {
+	wt := gs.GetWorktree(repo)
+	assert.NoError(t, os.WriteFile(filepath.Join(gs.LocalRepoDir(repo), name), []byte(name), 0o600))
+	_, err := wt.Add(name)
+	assert.NoError(t, err)
+	h, err := wt.Commit("add "+name, &git.CommitOptions{SignKey: signKey})
+	assert.NoError(t, err)
+	return h
+}
+
+// sshSignedCommit creates an empty commit signed with the given ssh key, as go-git can only sign with GPG keys
+func sshSignedCommit(t *testing.T, gs *TestGitServer, repo string, signer *signature.Signer) plumbing.Hash {
+	r := gs.GetGitRepo(repo)
+	head, err := r.Head()
+	assert.NoError(t, err)
+	parent, err := r.CommitObject(head.Hash())
+	assert.NoError(t, err)
+
+	sig := object.Signature{Name: "Test User", Email: "no@mail.com", When: time.Now()}
+	c := &object.Commit{
+		Author:       sig,
+		Committer:    sig,
+		Message:      "ssh signed",
+		TreeHash:     parent.TreeHash,
+		ParentHashes: []plumbing.Hash{parent.Hash},
+	}
+	payload, err := EncodeWithoutSignature(c.EncodeWithoutSignature)
+	assert.NoError(t, err)
+	c.PGPSignature, err = signer.Sign(SignatureNamespace, payload)
+	assert.NoError(t, err)
+
+	o := r.Storer.NewEncodedObject()
+	assert.NoError(t, c.Encode(o))
+	h, err := r.Storer.SetEncodedObject(o)
+	assert.NoError(t, err)
+	assert.NoError(t, r.Storer.SetReference(plumbing.NewHashReference(head.Name(), h)))
+	return h
+}
+
+func newTestMirror(t *testing.T, gs *TestGitServer, repo string) *MirroredGitRepo {
+	u := types.ParseGitUrlMust(gs.GitRepoUrl(repo))
+	mr, err := NewMirroredGitRepo(context.Background(), *u, t.TempDir(), nil, auth.NewDefaultAuthProviders("KLUCTL_GIT", nil))
+	assert.NoError(t, err)
+	assert.NoError(t, mr.Lock())
+	t.Cleanup(func() {
+		_ = mr.Unlock()
+	})
+	assert.NoError(t, mr.Update())
+	return mr
+}
+
+func TestVerifyCommit(t *testing.T) {
+	gs := NewTestGitServer(t)
+	gs.GitInit("repo")
+
+	gpgEntity, gpgPub := newTestGpgEntity(t)
+	otherGpgEntity, _ := newTestGpgEntity(t)
+	sshSigner, sshPub := newTestSshSigner(t)
+	otherSshSigner, _ := newTestSshSigner(t)
+
+	unsigned := commitFile(t, gs, "repo", "f1", nil)
+	gpgSigned := commitFile(t, gs, "repo", "f2", gpgEntity)
+	otherGpgSigned := commitFile(t, gs, "repo", "f3", otherGpgEntity)
+	sshSigned := sshSignedCommit(t, gs, "repo", sshSigner)
+	otherSshSigned := sshSignedCommit(t, gs, "repo", otherSshSigner)
+
+	keys, err := signature.ParsePublicKeys([][]byte{gpgPub, sshPub})
+	assert.NoError(t, err)
+
+	mr := newTestMirror(t, gs, "repo")
+
+	_, err = mr.VerifyCommit(unsigned.String(), keys)
+	assert.ErrorContains(t, err, "no signature found")
+
+	_, err = mr.VerifyCommit(gpgSigned.String(), keys)
+	assert.NoError(t, err)
+
+	_, err = mr.VerifyCommit(otherGpgSigned.String(), keys)
+	assert.ErrorContains(t, err, "failed to verify GPG signature")
+
+	_, err = mr.VerifyCommit(sshSigned.String(), keys)
+	assert.NoError(t, err)
+
+	_, err = mr.VerifyCommit(otherSshSigned.String(), keys)
+	assert.ErrorContains(t, err, "untrusted key")
+}
+
+func TestVerifyTag(t *testing.T) {
+	gs := NewTestGitServer(t)
+	gs.GitInit("repo")
+
+	gpgEntity, gpgPub := newTestGpgEntity(t)
+	h := commitFile(t, gs, "repo", "f1", nil)
+
+	r := gs.GetGitRepo("repo")
+	_, err := r.CreateTag("signed", h, &git.CreateTagOptions{Message: "signed", SignKey: gpgEntity})
+	assert.NoError(t, err)
+	_, err = r.CreateTag("unsigned", h, &git.CreateTagOptions{Message: "unsigned"})
+	assert.NoError(t, err)
+	_, err = r.CreateTag("lightweight", h, nil)
+	assert.NoError(t, err)
+
+	keys, err := signature.ParsePublicKeys([][]byte{gpgPub})
+	assert.NoError(t, err)
+
+	mr := newTestMirror(t, gs, "repo")
+
+	_, err = mr.VerifyTag("refs/tags/signed", keys)
+	assert.NoError(t, err)
+	_, err = mr.VerifyTag("unsigned", keys)
+	assert.ErrorContains(t, err, "no signature found")
+	_, err = mr.VerifyTag("lightweight", keys)
+	assert.ErrorContains(t, err, "not an annotated tag")
+}
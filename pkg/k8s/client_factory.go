@@ -107,8 +107,14 @@ func CreateDiscoveryClient(ctx context.Context, config *rest.Config) (discovery.
 
 func NewClientFactoryFromConfig(ctx context.Context, configIn *rest.Config) (ClientFactory, error) {
 	restConfig := rest.CopyConfig(configIn)
-	restConfig.QPS = 10
-	restConfig.Burst = 20
+	// the controller disables client-side rate limiting by setting negative values when APF is enabled, which we
+	// treat the same as unset values, so that the defaults are used
+	if restConfig.QPS <= 0 {
+		restConfig.QPS = 10
+	}
+	if restConfig.Burst <= 0 {
+		restConfig.Burst = 20
+	}
 
 	httpClient, err := rest.HTTPClientFor(restConfig)
 	if err != nil {
@@ -36,3 +36,29 @@ func (c *LoadedKluctlProject) FindTarget(name string) (*types2.Target, error) {
 	}
 	return nil, fmt.Errorf("target %s not existent in kluctl project config", name)
 }
+
+func (c *LoadedKluctlProject) FindRollout(name string) (*types2.Rollout, error) {
+	var rollout *types2.Rollout
+	for _, r := range c.Config.Rollouts {
+		if r.Name == name {
+			rollout = r
+			break
+		}
+	}
+	if rollout == nil {
+		return nil, fmt.Errorf("rollout %s not existent in kluctl project config", name)
+	}
+
+	for i, w := range rollout.Waves {
+		if len(w.Targets) == 0 {
+			return nil, fmt.Errorf("wave %d of rollout %s has no targets", i, name)
+		}
+		for _, t := range w.Targets {
+			_, err := c.FindTarget(t)
+			if err != nil {
+				return nil, fmt.Errorf("wave %d of rollout %s: %w", i, name, err)
+			}
+		}
+	}
+	return rollout, nil
+}
@@ -41,6 +41,10 @@ type TargetContextParams struct {
 	Inclusion          *utils.Inclusion
 	HelmCredentials    helm.HelmCredentialsProvider
 	RenderOutputDir    string
+
+	// K8sQPS and K8sBurst override the rate limits of the Kubernetes client. Zero means that defaults are used.
+	K8sQPS   int
+	K8sBurst int
 }
 
 func (p *LoadedKluctlProject) NewTargetContext(ctx context.Context, params TargetContextParams) (*TargetContext, error) {
@@ -98,6 +102,13 @@ func (p *LoadedKluctlProject) NewTargetContext(ctx context.Context, params Targe
 	var k *k8s.K8sCluster
 	if clientConfig != nil {
 		s := status.Start(ctx, fmt.Sprintf("Initializing k8s client"))
+		clientConfig = rest.CopyConfig(clientConfig)
+		if params.K8sQPS > 0 {
+			clientConfig.QPS = float32(params.K8sQPS)
+		}
+		if params.K8sBurst > 0 {
+			clientConfig.Burst = params.K8sBurst
+		}
 		clientFactory, err := k8s.NewClientFactoryFromConfig(ctx, clientConfig)
 		if err != nil {
 			return nil, err
@@ -0,0 +1,339 @@
+package oci
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	securejoin "github.com/cyphar/filepath-securejoin"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/kluctl/kluctl/v2/pkg/registries"
+	"github.com/kluctl/kluctl/v2/pkg/signature"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	ProjectConfigMediaType  types.MediaType = "application/vnd.kluctl.project.config.v1+json"
+	ProjectContentMediaType types.MediaType = "application/vnd.kluctl.project.content.v1.tar+gzip"
+
+	// SignatureAnnotation holds the signature of the project content layer digest
+	SignatureAnnotation = "io.kluctl.project.signature"
+	// SignatureNamespace is the namespace used when creating SSH signatures for project artifacts
+	SignatureNamespace = "kluctl-project"
+
+	urlPrefix = "oci://"
+)
+
+// ErrVerificationFailed is returned by PullProject when the signature of the artifact could not be verified
+var ErrVerificationFailed = errors.New("signature verification failed")
+
+// IsOciUrl returns true if the given url uses the oci:// scheme
+func IsOciUrl(u string) bool {
+	return strings.HasPrefix(u, urlPrefix)
+}
+
+// ParseOciUrl strips the oci:// prefix from the given url and returns the artifact reference
+func ParseOciUrl(u string) (string, error) {
+	if !IsOciUrl(u) {
+		return "", fmt.Errorf("invalid OCI url %s, must start with %s", u, urlPrefix)
+	}
+	return strings.TrimPrefix(u, urlPrefix), nil
+}
+
+// BuildOciUrl appends the tag or digest to the given oci:// url. The digest takes precedence over the tag.
+func BuildOciUrl(u string, tag string, digest string) string {
+	if digest != "" {
+		return fmt.Sprintf("%s@%s", u, digest)
+	} else if tag != "" {
+		return fmt.Sprintf("%s:%s", u, tag)
+	}
+	return u
+}
+
+// PushProject packages the given project directory as OCI artifact and pushes it to the given oci:// url. If signer
+// is not nil, the digest of the project content is signed and the signature is stored as annotation. It returns
+// the digest of the pushed artifact.
+func PushProject(rh *registries.RegistryHelper, u string, dir string, annotations map[string]string, signer *signature.Signer) (string, error) {
+	s, err := ParseOciUrl(u)
+	if err != nil {
+		return "", err
+	}
+	ref, err := rh.ParseReference(s)
+	if err != nil {
+		return "", err
+	}
+
+	b, err := buildTarGz(dir)
+	if err != nil {
+		return "", fmt.Errorf("failed to package project: %w", err)
+	}
+
+	layer := static.NewLayer(b, ProjectContentMediaType)
+	if signer != nil {
+		layerDigest, err := layer.Digest()
+		if err != nil {
+			return "", err
+		}
+		sig, err := signer.Sign(SignatureNamespace, []byte(layerDigest.String()))
+		if err != nil {
+			return "", fmt.Errorf("failed to sign project: %w", err)
+		}
+		a := map[string]string{}
+		for k, v := range annotations {
+			a[k] = v
+		}
+		a[SignatureAnnotation] = sig
+		annotations = a
+	}
+
+	img, err := mutate.Append(empty.Image, mutate.Addendum{
+		Layer: layer,
+	})
+	if err != nil {
+		return "", err
+	}
+	img = mutate.MediaType(img, types.OCIManifestSchema1)
+	img = mutate.ConfigMediaType(img, ProjectConfigMediaType)
+	if len(annotations) != 0 {
+		img = mutate.Annotations(img, annotations).(v1.Image)
+	}
+
+	opts, err := rh.RemoteOptions(ref.Context().RegistryStr())
+	if err != nil {
+		return "", err
+	}
+	err = remote.Write(ref, img, opts...)
+	if err != nil {
+		return "", fmt.Errorf("failed to push project to %s: %w", u, err)
+	}
+
+	digest, err := img.Digest()
+	if err != nil {
+		return "", err
+	}
+	return digest.String(), nil
+}
+
+// PullProject pulls the project artifact referenced by the given oci:// url and extracts it into dir. If keys is not
+// nil, the artifact must be signed by one of the given keys. It returns the digest of the pulled artifact.
+func PullProject(rh *registries.RegistryHelper, u string, dir string, keys *signature.PublicKeys) (string, error) {
+	s, err := ParseOciUrl(u)
+	if err != nil {
+		return "", err
+	}
+	ref, err := rh.ParseReference(s)
+	if err != nil {
+		return "", err
+	}
+
+	opts, err := rh.RemoteOptions(ref.Context().RegistryStr())
+	if err != nil {
+		return "", err
+	}
+	img, err := remote.Image(ref, opts...)
+	if err != nil {
+		return "", fmt.Errorf("failed to pull project from %s: %w", u, err)
+	}
+
+	m, err := img.Manifest()
+	if err != nil {
+		return "", err
+	}
+	if m.Config.MediaType != ProjectConfigMediaType {
+		return "", fmt.Errorf("%s is not a kluctl project artifact, config media type is %s", u, m.Config.MediaType)
+	}
+
+	var content v1.Layer
+	layers, err := img.Layers()
+	if err != nil {
+		return "", err
+	}
+	for _, l := range layers {
+		mt, err := l.MediaType()
+		if err != nil {
+			return "", err
+		}
+		if mt == ProjectContentMediaType {
+			content = l
+			break
+		}
+	}
+	if content == nil {
+		return "", fmt.Errorf("%s does not contain a layer of type %s", u, ProjectContentMediaType)
+	}
+
+	if keys != nil {
+		contentDigest, err := content.Digest()
+		if err != nil {
+			return "", err
+		}
+		_, err = keys.Verify(SignatureNamespace, m.Annotations[SignatureAnnotation], []byte(contentDigest.String()))
+		if err != nil {
+			return "", fmt.Errorf("%w for %s: %s", ErrVerificationFailed, u, err.Error())
+		}
+	}
+
+	rc, err := content.Compressed()
+	if err != nil {
+		return "", err
+	}
+	defer rc.Close()
+
+	err = extractTarGz(rc, dir)
+	if err != nil {
+		return "", fmt.Errorf("failed to extract project from %s: %w", u, err)
+	}
+
+	digest, err := img.Digest()
+	if err != nil {
+		return "", err
+	}
+	return digest.String(), nil
+}
+
+// buildTarGz packages the given directory, excluding .git directories. Timestamps are zeroed so that the same
+// content always leads to the same digest.
+func buildTarGz(dir string) ([]byte, error) {
+	buf := bytes.NewBuffer(nil)
+	gz := gzip.NewWriter(buf)
+	tw := tar.NewWriter(gz)
+
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && d.Name() == ".git" {
+			return filepath.SkipDir
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		var link string
+		if info.Mode()&fs.ModeSymlink != 0 {
+			link, err = os.Readlink(p)
+			if err != nil {
+				return err
+			}
+		}
+		h, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+		h.Name = filepath.ToSlash(rel)
+		h.ModTime = time.Time{}
+		h.AccessTime = time.Time{}
+		h.ChangeTime = time.Time{}
+		h.Uid, h.Gid = 0, 0
+		h.Uname, h.Gname = "", ""
+		h.Format = tar.FormatPAX
+
+		err = tw.WriteHeader(h)
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		f, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(tw, f)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = tw.Close()
+	if err != nil {
+		return nil, err
+	}
+	err = gz.Close()
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func extractTarGz(r io.Reader, dir string) error {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return err
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		p, err := securejoin.SecureJoin(dir, h.Name)
+		if err != nil {
+			return err
+		}
+
+		switch h.Typeflag {
+		case tar.TypeDir:
+			err = os.MkdirAll(p, 0o755)
+		case tar.TypeReg:
+			err = extractFile(tr, p, h.FileInfo().Mode().Perm())
+		case tar.TypeSymlink:
+			// only allow relative links that stay inside the extracted directory
+			target := filepath.Join(filepath.Dir(p), h.Linkname)
+			if filepath.IsAbs(h.Linkname) || !strings.HasPrefix(target, filepath.Clean(dir)+string(filepath.Separator)) {
+				return fmt.Errorf("symlink %s points outside of the project", h.Name)
+			}
+			err = os.MkdirAll(filepath.Dir(p), 0o755)
+			if err == nil {
+				err = os.Symlink(h.Linkname, p)
+			}
+		default:
+			return fmt.Errorf("unsupported file type for %s", h.Name)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func extractFile(r io.Reader, p string, perm fs.FileMode) error {
+	err := os.MkdirAll(filepath.Dir(p), 0o755)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(p, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = io.Copy(f, r)
+	return err
+}
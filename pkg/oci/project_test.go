@@ -0,0 +1,126 @@
+package oci
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/kluctl/kluctl/v2/pkg/registries"
+	"github.com/kluctl/kluctl/v2/pkg/signature"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/ssh"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func newTestRegistry(t *testing.T) (*registries.RegistryHelper, string) {
+	s := httptest.NewServer(registry.New())
+	t.Cleanup(s.Close)
+
+	u, err := url.Parse(s.URL)
+	assert.NoError(t, err)
+
+	rh := registries.NewRegistryHelper(context.Background())
+	rh.AddAuthEntry(registries.AuthEntry{
+		Registry: u.Host,
+		Insecure: true,
+	})
+	return rh, u.Host
+}
+
+func writeTestFile(t *testing.T, p string, content string) {
+	assert.NoError(t, os.MkdirAll(filepath.Dir(p), 0o755))
+	assert.NoError(t, os.WriteFile(p, []byte(content), 0o644))
+}
+
+func TestPushPullProject(t *testing.T) {
+	rh, host := newTestRegistry(t)
+
+	src := t.TempDir()
+	writeTestFile(t, filepath.Join(src, "deployment.yaml"), "deployments: []\n")
+	writeTestFile(t, filepath.Join(src, "sub", "cm.yaml"), "kind: ConfigMap\n")
+	writeTestFile(t, filepath.Join(src, ".git", "HEAD"), "ref: refs/heads/main\n")
+	assert.NoError(t, os.Symlink("cm.yaml", filepath.Join(src, "sub", "link.yaml")))
+
+	u := "oci://" + host + "/test/project"
+	digest, err := PushProject(rh, BuildOciUrl(u, "v1", ""), src, map[string]string{"test": "a"}, nil)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, digest)
+
+	// pushing the same content again must result in the same digest
+	digest2, err := PushProject(rh, BuildOciUrl(u, "v2", ""), src, map[string]string{"test": "a"}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, digest, digest2)
+
+	for _, x := range []string{BuildOciUrl(u, "v1", ""), BuildOciUrl(u, "", digest)} {
+		dst := t.TempDir()
+		pulledDigest, err := PullProject(rh, x, dst, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, digest, pulledDigest)
+
+		b, err := os.ReadFile(filepath.Join(dst, "deployment.yaml"))
+		assert.NoError(t, err)
+		assert.Equal(t, "deployments: []\n", string(b))
+		b, err = os.ReadFile(filepath.Join(dst, "sub", "link.yaml"))
+		assert.NoError(t, err)
+		assert.Equal(t, "kind: ConfigMap\n", string(b))
+		assert.NoFileExists(t, filepath.Join(dst, ".git", "HEAD"))
+	}
+}
+
+func TestPullProjectMissingTag(t *testing.T) {
+	rh, host := newTestRegistry(t)
+
+	_, err := PullProject(rh, "oci://"+host+"/test/project:missing", t.TempDir(), nil)
+	assert.Error(t, err)
+}
+
+func TestPushPullSignedProject(t *testing.T) {
+	rh, host := newTestRegistry(t)
+
+	src := t.TempDir()
+	writeTestFile(t, filepath.Join(src, "deployment.yaml"), "deployments: []\n")
+
+	newKey := func() (*signature.Signer, *signature.PublicKeys) {
+		_, priv, err := ed25519.GenerateKey(rand.Reader)
+		assert.NoError(t, err)
+		s, err := ssh.NewSignerFromKey(priv)
+		assert.NoError(t, err)
+		keys, err := signature.ParsePublicKeys([][]byte{ssh.MarshalAuthorizedKey(s.PublicKey())})
+		assert.NoError(t, err)
+		return signature.NewSshSigner(s), keys
+	}
+	signer, keys := newKey()
+	_, otherKeys := newKey()
+
+	u := "oci://" + host + "/test/project"
+	_, err := PushProject(rh, u+":signed", src, nil, signer)
+	assert.NoError(t, err)
+	_, err = PushProject(rh, u+":unsigned", src, nil, nil)
+	assert.NoError(t, err)
+
+	_, err = PullProject(rh, u+":signed", t.TempDir(), keys)
+	assert.NoError(t, err)
+	_, err = PullProject(rh, u+":signed", t.TempDir(), otherKeys)
+	assert.ErrorContains(t, err, "untrusted key")
+	_, err = PullProject(rh, u+":unsigned", t.TempDir(), keys)
+	assert.ErrorContains(t, err, "no signature found")
+	_, err = PullProject(rh, u+":unsigned", t.TempDir(), nil)
+	assert.NoError(t, err)
+}
+
+func TestParseOciUrl(t *testing.T) {
+	s, err := ParseOciUrl("oci://ghcr.io/org/project:v1")
+	assert.NoError(t, err)
+	assert.Equal(t, "ghcr.io/org/project:v1", s)
+
+	_, err = ParseOciUrl("https://ghcr.io/org/project")
+	assert.Error(t, err)
+
+	assert.Equal(t, "oci://r/p@sha256:x", BuildOciUrl("oci://r/p", "v1", "sha256:x"))
+	assert.Equal(t, "oci://r/p:v1", BuildOciUrl("oci://r/p", "v1", ""))
+	assert.Equal(t, "oci://r/p", BuildOciUrl("oci://r/p", "", ""))
+}
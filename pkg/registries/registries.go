@@ -71,28 +71,22 @@ func NewRegistryHelper(ctx context.Context) *RegistryHelper {
 }
 
 func (rh *RegistryHelper) ListImageTags(image string) ([]string, error) {
-	var nameOpts []name.Option
-	repo, err := name.NewRepository(image, nameOpts...)
+	repo, err := name.NewRepository(image)
 	if err != nil {
 		return nil, err
 	}
+	if rh.isInsecureRegistry(repo.RegistryStr()) {
+		repo, err = name.NewRepository(image, name.Insecure)
+		if err != nil {
+			return nil, err
+		}
+	}
 
-	t, err := rh.buildTransport(repo.RegistryStr())
+	remoteOpts, err := rh.RemoteOptions(repo.RegistryStr())
 	if err != nil {
 		return nil, err
 	}
 
-	remoteOpts := []remote.Option{
-		remote.WithAuthFromKeychain(rh),
-		remote.WithTransport(rh),
-		remote.WithContext(context.WithValue(rh.ctx, transportKey, t)),
-	}
-
-	if rh.isInsecureRegistry(repo.RegistryStr()) {
-		nameOpts = append(nameOpts, name.Insecure)
-		repo, err = name.NewRepository(image, nameOpts...)
-	}
-
 	ret, err := remote.List(repo, remoteOpts...)
 	if e, ok := err.(*transport.Error); ok && (e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden) {
 		return nil, fmt.Errorf("failed to authenticate against image registry %s, "+
@@ -107,6 +101,33 @@ func (rh *RegistryHelper) ListImageTags(image string) ([]string, error) {
 	return ret, err
 }
 
+// ParseReference parses an image or artifact reference while respecting the insecure setting of the registry
+func (rh *RegistryHelper) ParseReference(s string) (name.Reference, error) {
+	ref, err := name.ParseReference(s)
+	if err != nil {
+		return nil, err
+	}
+	if rh.isInsecureRegistry(ref.Context().RegistryStr()) {
+		return name.ParseReference(s, name.Insecure)
+	}
+	return ref, nil
+}
+
+// RemoteOptions returns the options to be passed to go-containerregistry's remote package so that the credentials,
+// CA bundles and TLS settings configured for the given registry are used
+func (rh *RegistryHelper) RemoteOptions(registry string) ([]remote.Option, error) {
+	t, err := rh.buildTransport(registry)
+	if err != nil {
+		return nil, err
+	}
+
+	return []remote.Option{
+		remote.WithAuthFromKeychain(rh),
+		remote.WithTransport(rh),
+		remote.WithContext(context.WithValue(rh.ctx, transportKey, t)),
+	}, nil
+}
+
 func (rh *RegistryHelper) AddAuthEntry(e AuthEntry) {
 	rh.authEntries = append(rh.authEntries, e)
 }
@@ -14,6 +14,7 @@ import (
 	"github.com/kluctl/kluctl/v2/pkg/git"
 	"github.com/kluctl/kluctl/v2/pkg/git/auth"
 	ssh_pool "github.com/kluctl/kluctl/v2/pkg/git/ssh-pool"
+	"github.com/kluctl/kluctl/v2/pkg/signature"
 	"github.com/kluctl/kluctl/v2/pkg/status"
 	"github.com/kluctl/kluctl/v2/pkg/utils"
 	cp "github.com/otiai10/copy"
@@ -304,3 +305,41 @@ func (e *CacheEntry) GetClonedDir(ref string) (string, git.CheckoutInfo, error)
 	}
 	return p, repoInfo, nil
 }
+
+// VerifyCommit verifies that the given commit is signed by one of the given keys and returns the fingerprint of the
+// signing key.
+func (e *CacheEntry) VerifyCommit(commit string, keys *signature.PublicKeys) (string, error) {
+	e.updateMutex.Lock()
+	defer e.updateMutex.Unlock()
+
+	if e.mr == nil {
+		return "", fmt.Errorf("signature verification is not supported for overridden repositories")
+	}
+
+	err := e.mr.Lock()
+	if err != nil {
+		return "", err
+	}
+	defer e.mr.Unlock()
+
+	return e.mr.VerifyCommit(commit, keys)
+}
+
+// VerifyTag verifies that the given annotated tag is signed by one of the given keys and returns the fingerprint of
+// the signing key.
+func (e *CacheEntry) VerifyTag(tag string, keys *signature.PublicKeys) (string, error) {
+	e.updateMutex.Lock()
+	defer e.updateMutex.Unlock()
+
+	if e.mr == nil {
+		return "", fmt.Errorf("signature verification is not supported for overridden repositories")
+	}
+
+	err := e.mr.Lock()
+	if err != nil {
+		return "", err
+	}
+	defer e.mr.Unlock()
+
+	return e.mr.VerifyTag(tag, keys)
+}
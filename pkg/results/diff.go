@@ -0,0 +1,104 @@
+package results
+
+import (
+	"github.com/kluctl/kluctl/v2/pkg/diff"
+	"github.com/kluctl/kluctl/v2/pkg/types/k8s"
+	"github.com/kluctl/kluctl/v2/pkg/types/result"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"sort"
+)
+
+// objectStateAfterCommand returns the state of the object as it was after the command finished. Deleted and orphan
+// objects have no state. Hooks are ignored as they are usually not meant to stay in the cluster.
+func objectStateAfterCommand(o *result.ResultObject) *uo.UnstructuredObject {
+	if o.Deleted || o.Hook {
+		return nil
+	}
+	if o.Applied != nil {
+		return o.Applied
+	}
+	return o.Rendered
+}
+
+func buildObjectStates(cr *result.CommandResult) map[k8s.ObjectRef]*result.ResultObject {
+	ret := map[k8s.ObjectRef]*result.ResultObject{}
+	for i := range cr.Objects {
+		o := &cr.Objects[i]
+		if objectStateAfterCommand(o) == nil {
+			continue
+		}
+		// versions might change between deployments, so we ignore them
+		ref := o.Ref
+		ref.Version = ""
+		ret[ref] = o
+	}
+	return ret
+}
+
+// DiffCommandResults compares the objects found in two command results and returns a command result that contains
+// the object-level changes from oldCr to newCr. Objects only found in newCr are marked as new and objects only found
+// in oldCr are marked as deleted.
+func DiffCommandResults(oldCr *result.CommandResult, newCr *result.CommandResult) (*result.CommandResult, error) {
+	oldObjects := buildObjectStates(oldCr)
+	newObjects := buildObjectStates(newCr)
+
+	ret := &result.CommandResult{
+		ProjectKey:  newCr.ProjectKey,
+		TargetKey:   newCr.TargetKey,
+		Target:      newCr.Target,
+		ClusterInfo: newCr.ClusterInfo,
+		Command: result.CommandInfo{
+			Initiator: result.CommandInititiator_CommandLine,
+			Command:   "results diff",
+			Target:    newCr.Command.Target,
+		},
+	}
+
+	for ref, no := range newObjects {
+		oo, ok := oldObjects[ref]
+		if !ok {
+			ret.Objects = append(ret.Objects, result.ResultObject{
+				BaseObject: result.BaseObject{Ref: no.Ref, New: true},
+				Rendered:   objectStateAfterCommand(no),
+			})
+			continue
+		}
+
+		newState := objectStateAfterCommand(no)
+		oldN, err := diff.NormalizeObject(objectStateAfterCommand(oo), nil, newState)
+		if err != nil {
+			return nil, err
+		}
+		newN, err := diff.NormalizeObject(newState, nil, newState)
+		if err != nil {
+			return nil, err
+		}
+		changes, err := diff.Diff(oldN, newN)
+		if err != nil {
+			return nil, err
+		}
+		if len(changes) == 0 {
+			continue
+		}
+		ret.Objects = append(ret.Objects, result.ResultObject{
+			BaseObject: result.BaseObject{Ref: no.Ref, Changes: changes},
+			Rendered:   newState,
+			Remote:     objectStateAfterCommand(oo),
+		})
+	}
+	for ref, oo := range oldObjects {
+		if _, ok := newObjects[ref]; ok {
+			continue
+		}
+		ret.Objects = append(ret.Objects, result.ResultObject{
+			BaseObject: result.BaseObject{Ref: oo.Ref, Deleted: true},
+			Remote:     objectStateAfterCommand(oo),
+		})
+	}
+
+	sort.Slice(ret.Objects, func(i, j int) bool {
+		return ret.Objects[i].Ref.String() < ret.Objects[j].Ref.String()
+	})
+
+	return ret, nil
+}
@@ -0,0 +1,63 @@
+package results
+
+import (
+	"github.com/kluctl/kluctl/v2/pkg/types/k8s"
+	"github.com/kluctl/kluctl/v2/pkg/types/result"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func buildTestConfigMap(name string, data map[string]any) result.ResultObject {
+	o := uo.FromMap(map[string]any{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata": map[string]any{
+			"name":      name,
+			"namespace": "default",
+		},
+		"data": data,
+	})
+	return result.ResultObject{
+		BaseObject: result.BaseObject{Ref: o.GetK8sRef()},
+		Rendered:   o,
+	}
+}
+
+func TestDiffCommandResults(t *testing.T) {
+	deleted := buildTestConfigMap("deleted", map[string]any{"a": "b"})
+	deleted.Deleted = true
+	hook := buildTestConfigMap("hook", map[string]any{"a": "b"})
+	hook.Hook = true
+
+	oldCr := &result.CommandResult{
+		Objects: []result.ResultObject{
+			buildTestConfigMap("unchanged", map[string]any{"a": "b"}),
+			buildTestConfigMap("changed", map[string]any{"a": "b"}),
+			buildTestConfigMap("removed", map[string]any{"a": "b"}),
+		},
+	}
+	newCr := &result.CommandResult{
+		Objects: []result.ResultObject{
+			buildTestConfigMap("unchanged", map[string]any{"a": "b"}),
+			buildTestConfigMap("changed", map[string]any{"a": "c"}),
+			buildTestConfigMap("added", map[string]any{"a": "b"}),
+			deleted,
+			hook,
+		},
+	}
+
+	cr, err := DiffCommandResults(oldCr, newCr)
+	assert.NoError(t, err)
+
+	byName := map[string]result.ResultObject{}
+	for _, o := range cr.Objects {
+		byName[o.Ref.Name] = o
+	}
+	assert.Len(t, byName, 3)
+	assert.True(t, byName["added"].New)
+	assert.True(t, byName["removed"].Deleted)
+	assert.Len(t, byName["changed"].Changes, 1)
+	assert.Equal(t, `data["a"]`, byName["changed"].Changes[0].JsonPath)
+	assert.Equal(t, k8s.ObjectRef{Version: "v1", Kind: "ConfigMap", Name: "changed", Namespace: "default"}, byName["changed"].Ref)
+}
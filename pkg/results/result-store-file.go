@@ -0,0 +1,360 @@
+package results
+
+import (
+	"compress/gzip"
+	"context"
+	"fmt"
+	"github.com/fsnotify/fsnotify"
+	"github.com/kluctl/kluctl/v2/pkg/types/result"
+	"github.com/kluctl/kluctl/v2/pkg/utils"
+	"github.com/kluctl/kluctl/v2/pkg/yaml"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	fileSummarySuffix = ".summary.json"
+	fileResultSuffix  = ".result.json.gz"
+	fileObjectsSuffix = ".objects.json.gz"
+)
+
+var validIdRegex = regexp.MustCompile(`^[a-zA-Z0-9-]+$`)
+
+// ResultStoreFile stores command results in a local directory. Each command result is stored in three files: the
+// summary, the reduced command result and the compacted objects. The summary is always written last, so that readers
+// will never see incomplete results.
+type ResultStoreFile struct {
+	ctx context.Context
+	dir string
+
+	retention RetentionPolicy
+
+	mutex sync.Mutex
+}
+
+func NewResultStoreFile(ctx context.Context, dir string, retention RetentionPolicy) (*ResultStoreFile, error) {
+	err := os.MkdirAll(dir, 0o700)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &ResultStoreFile{
+		ctx:       ctx,
+		dir:       dir,
+		retention: retention,
+	}
+	return s, nil
+}
+
+// DefaultResultStoreFileDir returns the directory used when no explicit directory is given for the file based result
+// store.
+func DefaultResultStoreFileDir() (string, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(cacheDir, "kluctl", "results"), nil
+}
+
+func (s *ResultStoreFile) buildPath(id string, suffix string) (string, error) {
+	if !validIdRegex.MatchString(id) {
+		return "", fmt.Errorf("invalid command result id %s", id)
+	}
+	return filepath.Join(s.dir, id+suffix), nil
+}
+
+func (s *ResultStoreFile) writeFileAtomic(p string, data []byte) error {
+	tmp, err := os.CreateTemp(s.dir, ".tmp-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(data)
+	if err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	err = tmp.Close()
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), p)
+}
+
+func (s *ResultStoreFile) WriteCommandResult(cr *result.CommandResult) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	summaryPath, err := s.buildPath(cr.Id, fileSummarySuffix)
+	if err != nil {
+		return err
+	}
+	resultPath, _ := s.buildPath(cr.Id, fileResultSuffix)
+	objectsPath, _ := s.buildPath(cr.Id, fileObjectsSuffix)
+
+	crJson, err := yaml.WriteJsonString(cr.ToReducedObjects())
+	if err != nil {
+		return err
+	}
+	compressedCr, err := utils.CompressGzip([]byte(crJson), gzip.BestCompression)
+	if err != nil {
+		return err
+	}
+
+	objectsJson, err := yaml.WriteJsonString(result.CompactedObjects(cr.Objects))
+	if err != nil {
+		return err
+	}
+	compressedObjects, err := utils.CompressGzip([]byte(objectsJson), gzip.BestCompression)
+	if err != nil {
+		return err
+	}
+
+	summaryJson, err := yaml.WriteJsonString(cr.BuildSummary())
+	if err != nil {
+		return err
+	}
+
+	err = s.writeFileAtomic(resultPath, compressedCr)
+	if err != nil {
+		return err
+	}
+	err = s.writeFileAtomic(objectsPath, compressedObjects)
+	if err != nil {
+		return err
+	}
+	err = s.writeFileAtomic(summaryPath, []byte(summaryJson))
+	if err != nil {
+		return err
+	}
+
+	return cleanupResults(s.ctx, s, s.retention, cr.ProjectKey, cr.TargetKey)
+}
+
+func (s *ResultStoreFile) DeleteCommandResult(id string) error {
+	// delete the summary first so that the result is not visible anymore while we delete the rest
+	for _, suffix := range []string{fileSummarySuffix, fileResultSuffix, fileObjectsSuffix} {
+		p, err := s.buildPath(id, suffix)
+		if err != nil {
+			return err
+		}
+		err = os.Remove(p)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *ResultStoreFile) readSummary(p string) (*result.CommandResultSummary, error) {
+	var summary result.CommandResultSummary
+	err := yaml.ReadYamlFile(p, &summary)
+	if err != nil {
+		return nil, err
+	}
+	return &summary, nil
+}
+
+func (s *ResultStoreFile) listSummaries(filter *result.ProjectKey) ([]result.CommandResultSummary, error) {
+	des, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := make([]result.CommandResultSummary, 0, len(des))
+	for _, de := range des {
+		if de.IsDir() || !strings.HasSuffix(de.Name(), fileSummarySuffix) {
+			continue
+		}
+		summary, err := s.readSummary(filepath.Join(s.dir, de.Name()))
+		if err != nil {
+			continue
+		}
+		if !FilterSummary(summary, filter) {
+			continue
+		}
+		ret = append(ret, *summary)
+	}
+
+	sort.Slice(ret, func(i, j int) bool {
+		return lessSummary(&ret[i], &ret[j])
+	})
+
+	return ret, nil
+}
+
+func (s *ResultStoreFile) ListCommandResultSummaries(options ListCommandResultSummariesOptions) ([]result.CommandResultSummary, error) {
+	return s.listSummaries(options.ProjectFilter)
+}
+
+func (s *ResultStoreFile) WatchCommandResultSummaries(options ListCommandResultSummariesOptions) ([]*result.CommandResultSummary, <-chan WatchCommandResultSummaryEvent, context.CancelFunc, error) {
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	err = w.Add(s.dir)
+	if err != nil {
+		_ = w.Close()
+		return nil, nil, nil, err
+	}
+
+	// the initial list must be retrieved after the watch got started, so that we don't miss any results
+	l, err := s.listSummaries(options.ProjectFilter)
+	if err != nil {
+		_ = w.Close()
+		return nil, nil, nil, err
+	}
+
+	// we need to remember the summaries so that we can send them with delete events
+	known := map[string]*result.CommandResultSummary{}
+	var initialListRet []*result.CommandResultSummary
+	for i := range l {
+		known[l[i].Id] = &l[i]
+		initialListRet = append(initialListRet, &l[i])
+	}
+
+	ch := make(chan WatchCommandResultSummaryEvent)
+	done := make(chan struct{})
+
+	send := func(e WatchCommandResultSummaryEvent) bool {
+		select {
+		case ch <- e:
+			return true
+		case <-done:
+			return false
+		}
+	}
+
+	go func() {
+		defer close(ch)
+		for {
+			var event fsnotify.Event
+			var ok bool
+			select {
+			case event, ok = <-w.Events:
+				if !ok {
+					return
+				}
+			case _, ok = <-w.Errors:
+				if !ok {
+					return
+				}
+				continue
+			case <-done:
+				return
+			}
+
+			name := filepath.Base(event.Name)
+			if !strings.HasSuffix(name, fileSummarySuffix) {
+				continue
+			}
+			id := strings.TrimSuffix(name, fileSummarySuffix)
+
+			if event.Has(fsnotify.Remove) || event.Has(fsnotify.Rename) {
+				summary, ok := known[id]
+				if !ok {
+					continue
+				}
+				delete(known, id)
+				if !send(WatchCommandResultSummaryEvent{Delete: true, Summary: summary}) {
+					return
+				}
+			} else if event.Has(fsnotify.Create) || event.Has(fsnotify.Write) {
+				summary, err := s.readSummary(event.Name)
+				if err != nil {
+					continue
+				}
+				if !FilterSummary(summary, options.ProjectFilter) {
+					continue
+				}
+				known[id] = summary
+				if !send(WatchCommandResultSummaryEvent{Summary: summary}) {
+					return
+				}
+			}
+		}
+	}()
+
+	var cancelOnce sync.Once
+	cancel := func() {
+		cancelOnce.Do(func() {
+			close(done)
+			_ = w.Close()
+		})
+	}
+	return initialListRet, ch, cancel, nil
+}
+
+func (s *ResultStoreFile) HasCommandResult(id string) (bool, error) {
+	p, err := s.buildPath(id, fileSummarySuffix)
+	if err != nil {
+		return false, err
+	}
+	return utils.IsFile(p), nil
+}
+
+func (s *ResultStoreFile) GetCommandResultSummary(id string) (*result.CommandResultSummary, error) {
+	p, err := s.buildPath(id, fileSummarySuffix)
+	if err != nil {
+		return nil, err
+	}
+	if !utils.IsFile(p) {
+		return nil, nil
+	}
+	return s.readSummary(p)
+}
+
+func (s *ResultStoreFile) readCompressed(id string, suffix string) ([]byte, error) {
+	p, err := s.buildPath(id, suffix)
+	if err != nil {
+		return nil, err
+	}
+	b, err := os.ReadFile(p)
+	if err != nil {
+		return nil, err
+	}
+	return utils.UncompressGzip(b)
+}
+
+func (s *ResultStoreFile) GetCommandResult(options GetCommandResultOptions) (*result.CommandResult, error) {
+	has, err := s.HasCommandResult(options.Id)
+	if err != nil {
+		return nil, err
+	}
+	if !has {
+		return nil, nil
+	}
+
+	crJson, err := s.readCompressed(options.Id, fileResultSuffix)
+	if err != nil {
+		return nil, err
+	}
+
+	var cr result.CommandResult
+	err = yaml.ReadYamlBytes(crJson, &cr)
+	if err != nil {
+		return nil, err
+	}
+
+	if !options.Reduced {
+		objectsJson, err := s.readCompressed(options.Id, fileObjectsSuffix)
+		if err != nil {
+			return nil, err
+		}
+		var objects result.CompactedObjects
+		err = yaml.ReadYamlBytes(objectsJson, &objects)
+		if err != nil {
+			return nil, err
+		}
+		cr.Objects = objects
+	}
+
+	return &cr, nil
+}
+
+var _ ResultStore = &ResultStoreFile{}
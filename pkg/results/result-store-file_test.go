@@ -0,0 +1,132 @@
+package results
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/kluctl/kluctl/v2/pkg/types/k8s"
+	"github.com/kluctl/kluctl/v2/pkg/types/result"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"testing"
+	"time"
+)
+
+func buildTestCommandResult(target string, startTime time.Time) *result.CommandResult {
+	ref := k8s.ObjectRef{Version: "v1", Kind: "ConfigMap", Name: "cm", Namespace: "default"}
+	o := uo.FromMap(map[string]any{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata": map[string]any{
+			"name":      "cm",
+			"namespace": "default",
+		},
+		"data": map[string]any{
+			"a": "b",
+		},
+	})
+	return &result.CommandResult{
+		Id: uuid.New().String(),
+		ProjectKey: result.ProjectKey{
+			SubDir: "test",
+		},
+		TargetKey: result.TargetKey{
+			TargetName: target,
+			ClusterId:  "cluster",
+		},
+		Command: result.CommandInfo{
+			Initiator: result.CommandInititiator_CommandLine,
+			Command:   "deploy",
+			StartTime: metav1.NewTime(startTime),
+			EndTime:   metav1.NewTime(startTime.Add(time.Second)),
+		},
+		Objects: []result.ResultObject{
+			{
+				BaseObject: result.BaseObject{Ref: ref, New: true},
+				Rendered:   o,
+				Applied:    o,
+			},
+		},
+	}
+}
+
+func TestResultStoreFile(t *testing.T) {
+	s, err := NewResultStoreFile(context.Background(), t.TempDir(), RetentionPolicy{KeepCount: 2})
+	assert.NoError(t, err)
+
+	now := time.Now().Truncate(time.Second)
+	cr1 := buildTestCommandResult("t1", now)
+	cr2 := buildTestCommandResult("t1", now.Add(time.Minute))
+	cr3 := buildTestCommandResult("t1", now.Add(2*time.Minute))
+	cr4 := buildTestCommandResult("t2", now)
+
+	for _, cr := range []*result.CommandResult{cr1, cr2, cr3, cr4} {
+		assert.NoError(t, s.WriteCommandResult(cr))
+	}
+
+	l, err := s.ListCommandResultSummaries(ListCommandResultSummariesOptions{})
+	assert.NoError(t, err)
+	var ids []string
+	for _, x := range l {
+		ids = append(ids, x.Id)
+	}
+	// cr1 got cleaned up as only 2 results per target are kept
+	assert.ElementsMatch(t, []string{cr2.Id, cr3.Id, cr4.Id}, ids)
+
+	has, err := s.HasCommandResult(cr1.Id)
+	assert.NoError(t, err)
+	assert.False(t, has)
+
+	cr, err := s.GetCommandResult(GetCommandResultOptions{Id: cr3.Id})
+	assert.NoError(t, err)
+	assert.Equal(t, cr3.Id, cr.Id)
+	assert.Len(t, cr.Objects, 1)
+	assert.Equal(t, cr3.Objects[0].Rendered, cr.Objects[0].Rendered)
+
+	cr, err = s.GetCommandResult(GetCommandResultOptions{Id: cr3.Id, Reduced: true})
+	assert.NoError(t, err)
+	assert.Len(t, cr.Objects, 1)
+
+	summary, err := s.GetCommandResultSummary(cr4.Id)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, summary.NewObjects)
+
+	_, err = s.GetCommandResult(GetCommandResultOptions{Id: "../foo"})
+	assert.Error(t, err)
+}
+
+func TestResultStoreFileWatch(t *testing.T) {
+	s, err := NewResultStoreFile(context.Background(), t.TempDir(), RetentionPolicy{KeepCount: 1})
+	assert.NoError(t, err)
+
+	now := time.Now().Truncate(time.Second)
+	cr1 := buildTestCommandResult("t1", now)
+	assert.NoError(t, s.WriteCommandResult(cr1))
+
+	initial, ch, cancel, err := s.WatchCommandResultSummaries(ListCommandResultSummariesOptions{})
+	assert.NoError(t, err)
+	defer cancel()
+	assert.Len(t, initial, 1)
+	assert.Equal(t, cr1.Id, initial[0].Id)
+
+	cr2 := buildTestCommandResult("t1", now.Add(time.Minute))
+	go func() {
+		_ = s.WriteCommandResult(cr2)
+	}()
+
+	// writing cr2 causes cr1 to be deleted
+	var added, deleted bool
+	timeout := time.After(10 * time.Second)
+	for !added || !deleted {
+		select {
+		case e := <-ch:
+			if e.Delete && e.Summary.Id == cr1.Id {
+				deleted = true
+			} else if !e.Delete && e.Summary.Id == cr2.Id {
+				added = true
+			}
+		case <-timeout:
+			t.Fatal("timeout while waiting for watch events")
+		}
+	}
+}
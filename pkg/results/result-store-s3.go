@@ -0,0 +1,465 @@
+package results
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/kluctl/kluctl/v2/pkg/status"
+	"github.com/kluctl/kluctl/v2/pkg/types/result"
+	"github.com/kluctl/kluctl/v2/pkg/utils"
+	"github.com/kluctl/kluctl/v2/pkg/yaml"
+	"io"
+	"k8s.io/utils/lru"
+	"net/url"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// S3Client is the subset of the S3 API used by ResultStoreS3
+type S3Client interface {
+	PutObject(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error)
+	GetObject(ctx context.Context, params *s3.GetObjectInput, optFns ...func(*s3.Options)) (*s3.GetObjectOutput, error)
+	DeleteObject(ctx context.Context, params *s3.DeleteObjectInput, optFns ...func(*s3.Options)) (*s3.DeleteObjectOutput, error)
+	ListObjectsV2(ctx context.Context, params *s3.ListObjectsV2Input, optFns ...func(*s3.Options)) (*s3.ListObjectsV2Output, error)
+}
+
+// ResultStoreS3 stores command results in an S3 compatible bucket. The following objects are written per command
+// result:
+//
+//	<prefix>/results/<id>/result.json.gz: the compacted command result
+//	<prefix>/results/<id>/summary.json: the command result summary
+//	<prefix>/summaries/<project-hash>/<target-hash>/<id>.json: a copy of the summary, used as index
+//
+// The index object is always written last and deleted first, so that readers never see incomplete results.
+type ResultStoreS3 struct {
+	ctx    context.Context
+	client S3Client
+	bucket string
+	prefix string
+
+	retention RetentionPolicy
+
+	// PollInterval specifies how often WatchCommandResultSummaries polls for new results
+	PollInterval time.Duration
+
+	// summaries are immutable, so we can safely cache them while listing the index. The cache is bounded, as
+	// results are continuously added and deleted while the store is running.
+	summaryCache *lru.Cache
+}
+
+// summaryCacheSize is the maximum number of summaries kept in the cache
+const summaryCacheSize = 10000
+
+func NewResultStoreS3(ctx context.Context, client S3Client, bucket string, prefix string, retention RetentionPolicy) (*ResultStoreS3, error) {
+	if bucket == "" {
+		return nil, fmt.Errorf("missing bucket")
+	}
+	s := &ResultStoreS3{
+		ctx:          ctx,
+		client:       client,
+		bucket:       bucket,
+		prefix:       strings.Trim(prefix, "/"),
+		retention:    retention,
+		PollInterval: 10 * time.Second,
+		summaryCache: lru.New(summaryCacheSize),
+	}
+	return s, nil
+}
+
+// NewResultStoreS3FromUrl creates a ResultStoreS3 from an url in the form s3://<bucket>/<prefix>. The following query
+// parameters are supported: 'endpoint' to specify a custom endpoint (e.g. for MinIO), 'region', 'profile' and
+// 'pathStyle' to enforce path style addressing. Credentials are loaded from the default AWS credentials chain.
+func NewResultStoreS3FromUrl(ctx context.Context, u string, retention RetentionPolicy) (*ResultStoreS3, error) {
+	pu, err := url.Parse(u)
+	if err != nil {
+		return nil, err
+	}
+	if pu.Scheme != "s3" {
+		return nil, fmt.Errorf("invalid s3 url %s", u)
+	}
+
+	q := pu.Query()
+
+	var configOpts []func(*config.LoadOptions) error
+	if q.Has("profile") {
+		configOpts = append(configOpts, config.WithSharedConfigProfile(q.Get("profile")))
+	}
+	if q.Has("region") {
+		configOpts = append(configOpts, config.WithRegion(q.Get("region")))
+	}
+
+	cfg, err := config.LoadDefaultConfig(ctx, configOpts...)
+	if err != nil {
+		return nil, err
+	}
+
+	pathStyle := false
+	if q.Has("pathStyle") {
+		pathStyle, err = strconv.ParseBool(q.Get("pathStyle"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid pathStyle parameter: %w", err)
+		}
+	}
+
+	client := s3.NewFromConfig(cfg, func(o *s3.Options) {
+		if q.Has("endpoint") {
+			o.EndpointResolver = s3.EndpointResolverFromURL(q.Get("endpoint"))
+		}
+		o.UsePathStyle = pathStyle
+	})
+
+	return NewResultStoreS3(ctx, client, pu.Host, pu.Path, retention)
+}
+
+func (s *ResultStoreS3) buildKey(elems ...string) string {
+	return path.Join(append([]string{s.prefix}, elems...)...)
+}
+
+func (s *ResultStoreS3) buildProjectIndexPrefix(projectKey result.ProjectKey) string {
+	return s.buildKey("summaries", utils.Sha256String(yaml.WriteJsonStringMust(projectKey))[:16]) + "/"
+}
+
+func (s *ResultStoreS3) buildTargetIndexPrefix(projectKey result.ProjectKey, targetKey result.TargetKey) string {
+	return s.buildProjectIndexPrefix(projectKey) + utils.Sha256String(yaml.WriteJsonStringMust(targetKey))[:16] + "/"
+}
+
+func (s *ResultStoreS3) buildResultKey(id string, name string) (string, error) {
+	if !validIdRegex.MatchString(id) {
+		return "", fmt.Errorf("invalid command result id %s", id)
+	}
+	return s.buildKey("results", id, name), nil
+}
+
+func (s *ResultStoreS3) putObject(key string, data []byte) error {
+	_, err := s.client.PutObject(s.ctx, &s3.PutObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+		Body:   bytes.NewReader(data),
+	})
+	return err
+}
+
+func (s *ResultStoreS3) getObject(key string) ([]byte, error) {
+	o, err := s.client.GetObject(s.ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		var nsk *types.NoSuchKey
+		if errors.As(err, &nsk) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer o.Body.Close()
+	return io.ReadAll(o.Body)
+}
+
+func (s *ResultStoreS3) deleteObject(key string) error {
+	_, err := s.client.DeleteObject(s.ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	return err
+}
+
+func (s *ResultStoreS3) listKeys(prefix string) ([]string, error) {
+	var ret []string
+	var token *string
+	for {
+		o, err := s.client.ListObjectsV2(s.ctx, &s3.ListObjectsV2Input{
+			Bucket:            aws.String(s.bucket),
+			Prefix:            aws.String(prefix),
+			ContinuationToken: token,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, x := range o.Contents {
+			ret = append(ret, aws.ToString(x.Key))
+		}
+		if !o.IsTruncated {
+			break
+		}
+		token = o.NextContinuationToken
+	}
+	return ret, nil
+}
+
+func (s *ResultStoreS3) WriteCommandResult(cr *result.CommandResult) error {
+	resultKey, err := s.buildResultKey(cr.Id, "result.json.gz")
+	if err != nil {
+		return err
+	}
+	summaryKey, _ := s.buildResultKey(cr.Id, "summary.json")
+	indexKey := s.buildTargetIndexPrefix(cr.ProjectKey, cr.TargetKey) + cr.Id + ".json"
+
+	crJson, err := yaml.WriteJsonString(cr.ToCompacted())
+	if err != nil {
+		return err
+	}
+	compressedCr, err := utils.CompressGzip([]byte(crJson), gzip.BestCompression)
+	if err != nil {
+		return err
+	}
+
+	summaryJson, err := yaml.WriteJsonString(cr.BuildSummary())
+	if err != nil {
+		return err
+	}
+
+	err = s.putObject(resultKey, compressedCr)
+	if err != nil {
+		return err
+	}
+	err = s.putObject(summaryKey, []byte(summaryJson))
+	if err != nil {
+		return err
+	}
+	err = s.putObject(indexKey, []byte(summaryJson))
+	if err != nil {
+		return err
+	}
+
+	return s.cleanupResults(cr.ProjectKey, cr.TargetKey)
+}
+
+func (s *ResultStoreS3) cleanupResults(project result.ProjectKey, target result.TargetKey) error {
+	summaries, err := s.listSummaries(s.buildTargetIndexPrefix(project, target))
+	if err != nil {
+		return err
+	}
+
+	l := make([]result.CommandResultSummary, 0, len(summaries))
+	for _, rs := range summaries {
+		l = append(l, *rs)
+	}
+
+	deleteResults(s.ctx, s, s.retention.SelectForDeletion(l, time.Now()))
+	return nil
+}
+
+func (s *ResultStoreS3) DeleteCommandResult(id string) error {
+	summary, err := s.GetCommandResultSummary(id)
+	if err != nil {
+		return err
+	}
+	if summary == nil {
+		return nil
+	}
+
+	// delete the index first so that the result is not visible anymore while we delete the rest
+	keys := []string{s.buildTargetIndexPrefix(summary.ProjectKey, summary.TargetKey) + summary.Id + ".json"}
+	for _, n := range []string{"summary.json", "result.json.gz"} {
+		k, err := s.buildResultKey(summary.Id, n)
+		if err != nil {
+			return err
+		}
+		keys = append(keys, k)
+	}
+	for _, k := range keys {
+		err := s.deleteObject(k)
+		if err != nil {
+			return err
+		}
+	}
+
+	s.summaryCache.Remove(summary.Id)
+	return nil
+}
+
+func (s *ResultStoreS3) readSummary(key string) (*result.CommandResultSummary, error) {
+	b, err := s.getObject(key)
+	if err != nil {
+		return nil, err
+	}
+	if b == nil {
+		return nil, nil
+	}
+	var summary result.CommandResultSummary
+	err = yaml.ReadYamlBytes(b, &summary)
+	if err != nil {
+		return nil, err
+	}
+	return &summary, nil
+}
+
+// listSummaries lists all summaries found in the index below the given prefix, sorted from newest to oldest
+func (s *ResultStoreS3) listSummaries(prefix string) ([]*result.CommandResultSummary, error) {
+	keys, err := s.listKeys(prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	var ret []*result.CommandResultSummary
+	for _, k := range keys {
+		id := strings.TrimSuffix(path.Base(k), ".json")
+
+		var summary *result.CommandResultSummary
+		if v, ok := s.summaryCache.Get(id); ok {
+			summary = v.(*result.CommandResultSummary)
+		} else {
+			summary, err = s.readSummary(k)
+			if err != nil || summary == nil {
+				continue
+			}
+			s.summaryCache.Add(id, summary)
+		}
+		ret = append(ret, summary)
+	}
+
+	sort.Slice(ret, func(i, j int) bool {
+		return lessSummary(ret[i], ret[j])
+	})
+
+	return ret, nil
+}
+
+func (s *ResultStoreS3) listSummariesForFilter(filter *result.ProjectKey) ([]*result.CommandResultSummary, error) {
+	prefix := s.buildKey("summaries") + "/"
+	if filter != nil {
+		prefix = s.buildProjectIndexPrefix(*filter)
+	}
+	return s.listSummaries(prefix)
+}
+
+func (s *ResultStoreS3) ListCommandResultSummaries(options ListCommandResultSummariesOptions) ([]result.CommandResultSummary, error) {
+	l, err := s.listSummariesForFilter(options.ProjectFilter)
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]result.CommandResultSummary, 0, len(l))
+	for _, x := range l {
+		ret = append(ret, *x)
+	}
+	return ret, nil
+}
+
+func (s *ResultStoreS3) WatchCommandResultSummaries(options ListCommandResultSummariesOptions) ([]*result.CommandResultSummary, <-chan WatchCommandResultSummaryEvent, context.CancelFunc, error) {
+	initial, err := s.listSummariesForFilter(options.ProjectFilter)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	known := map[string]*result.CommandResultSummary{}
+	for _, x := range initial {
+		known[x.Id] = x
+	}
+
+	ch := make(chan WatchCommandResultSummaryEvent)
+	done := make(chan struct{})
+
+	send := func(e WatchCommandResultSummaryEvent) bool {
+		select {
+		case ch <- e:
+			return true
+		case <-done:
+			return false
+		}
+	}
+
+	go func() {
+		defer close(ch)
+		ticker := time.NewTicker(s.PollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+			case <-done:
+				return
+			case <-s.ctx.Done():
+				return
+			}
+
+			l, err := s.listSummariesForFilter(options.ProjectFilter)
+			if err != nil {
+				status.Warning(s.ctx, "Failed to poll command results: %s", err)
+				continue
+			}
+
+			newKnown := map[string]*result.CommandResultSummary{}
+			for _, x := range l {
+				newKnown[x.Id] = x
+				if _, ok := known[x.Id]; !ok {
+					if !send(WatchCommandResultSummaryEvent{Summary: x}) {
+						return
+					}
+				}
+			}
+			for id, x := range known {
+				if _, ok := newKnown[id]; !ok {
+					if !send(WatchCommandResultSummaryEvent{Summary: x, Delete: true}) {
+						return
+					}
+				}
+			}
+			known = newKnown
+		}
+	}()
+
+	var cancelOnce sync.Once
+	cancel := func() {
+		cancelOnce.Do(func() {
+			close(done)
+		})
+	}
+	return initial, ch, cancel, nil
+}
+
+func (s *ResultStoreS3) HasCommandResult(id string) (bool, error) {
+	summary, err := s.GetCommandResultSummary(id)
+	if err != nil {
+		return false, err
+	}
+	return summary != nil, nil
+}
+
+func (s *ResultStoreS3) GetCommandResultSummary(id string) (*result.CommandResultSummary, error) {
+	// don't use the cache here, as the result might have been deleted in the meantime
+	k, err := s.buildResultKey(id, "summary.json")
+	if err != nil {
+		return nil, err
+	}
+	return s.readSummary(k)
+}
+
+func (s *ResultStoreS3) GetCommandResult(options GetCommandResultOptions) (*result.CommandResult, error) {
+	k, err := s.buildResultKey(options.Id, "result.json.gz")
+	if err != nil {
+		return nil, err
+	}
+	b, err := s.getObject(k)
+	if err != nil {
+		return nil, err
+	}
+	if b == nil {
+		return nil, nil
+	}
+	b, err = utils.UncompressGzip(b)
+	if err != nil {
+		return nil, err
+	}
+
+	var ccr result.CompactedCommandResult
+	err = yaml.ReadYamlBytes(b, &ccr)
+	if err != nil {
+		return nil, err
+	}
+	cr := ccr.ToNonCompacted()
+	if options.Reduced {
+		cr = cr.ToReducedObjects()
+	}
+	return cr, nil
+}
+
+var _ ResultStore = &ResultStoreS3{}
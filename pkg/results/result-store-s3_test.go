@@ -0,0 +1,178 @@
+package results
+
+import (
+	"bytes"
+	"context"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/kluctl/kluctl/v2/pkg/types/result"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeS3Client is a minimal in-memory stand-in for an S3 compatible server
+type fakeS3Client struct {
+	objects map[string][]byte
+	mutex   sync.Mutex
+}
+
+func newFakeS3Client() *fakeS3Client {
+	return &fakeS3Client{objects: map[string][]byte{}}
+}
+
+func (c *fakeS3Client) PutObject(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error) {
+	b, err := io.ReadAll(params.Body)
+	if err != nil {
+		return nil, err
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.objects[aws.ToString(params.Bucket)+"/"+aws.ToString(params.Key)] = b
+	return &s3.PutObjectOutput{}, nil
+}
+
+func (c *fakeS3Client) GetObject(ctx context.Context, params *s3.GetObjectInput, optFns ...func(*s3.Options)) (*s3.GetObjectOutput, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	b, ok := c.objects[aws.ToString(params.Bucket)+"/"+aws.ToString(params.Key)]
+	if !ok {
+		return nil, &types.NoSuchKey{}
+	}
+	return &s3.GetObjectOutput{Body: io.NopCloser(bytes.NewReader(b))}, nil
+}
+
+func (c *fakeS3Client) DeleteObject(ctx context.Context, params *s3.DeleteObjectInput, optFns ...func(*s3.Options)) (*s3.DeleteObjectOutput, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	delete(c.objects, aws.ToString(params.Bucket)+"/"+aws.ToString(params.Key))
+	return &s3.DeleteObjectOutput{}, nil
+}
+
+func (c *fakeS3Client) ListObjectsV2(ctx context.Context, params *s3.ListObjectsV2Input, optFns ...func(*s3.Options)) (*s3.ListObjectsV2Output, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	prefix := aws.ToString(params.Bucket) + "/" + aws.ToString(params.Prefix)
+	var keys []string
+	for k := range c.objects {
+		if strings.HasPrefix(k, prefix) {
+			keys = append(keys, strings.TrimPrefix(k, aws.ToString(params.Bucket)+"/"))
+		}
+	}
+	sort.Strings(keys)
+
+	// return small pages to test pagination
+	start := 0
+	if params.ContinuationToken != nil {
+		for i, k := range keys {
+			if k == *params.ContinuationToken {
+				start = i
+				break
+			}
+		}
+	}
+	ret := &s3.ListObjectsV2Output{}
+	for i := start; i < len(keys); i++ {
+		if i-start == 2 {
+			ret.IsTruncated = true
+			ret.NextContinuationToken = aws.String(keys[i])
+			break
+		}
+		ret.Contents = append(ret.Contents, types.Object{Key: aws.String(keys[i])})
+	}
+	return ret, nil
+}
+
+func TestResultStoreS3(t *testing.T) {
+	client := newFakeS3Client()
+	s, err := NewResultStoreS3(context.Background(), client, "bucket", "/prefix/", RetentionPolicy{KeepCount: 2})
+	assert.NoError(t, err)
+
+	now := time.Now().Truncate(time.Second)
+	cr1 := buildTestCommandResult("t1", now)
+	cr2 := buildTestCommandResult("t1", now.Add(time.Minute))
+	cr3 := buildTestCommandResult("t1", now.Add(2*time.Minute))
+	cr4 := buildTestCommandResult("t2", now)
+
+	for _, cr := range []*result.CommandResult{cr1, cr2, cr3, cr4} {
+		assert.NoError(t, s.WriteCommandResult(cr))
+	}
+
+	for k := range client.objects {
+		assert.True(t, strings.HasPrefix(k, "bucket/prefix/"))
+	}
+
+	l, err := s.ListCommandResultSummaries(ListCommandResultSummariesOptions{})
+	assert.NoError(t, err)
+	var ids []string
+	for _, x := range l {
+		ids = append(ids, x.Id)
+	}
+	// cr1 got cleaned up as only 2 results per target are kept
+	assert.ElementsMatch(t, []string{cr2.Id, cr3.Id, cr4.Id}, ids)
+
+	l, err = s.ListCommandResultSummaries(ListCommandResultSummariesOptions{ProjectFilter: &result.ProjectKey{SubDir: "other"}})
+	assert.NoError(t, err)
+	assert.Empty(t, l)
+
+	has, err := s.HasCommandResult(cr1.Id)
+	assert.NoError(t, err)
+	assert.False(t, has)
+
+	cr, err := s.GetCommandResult(GetCommandResultOptions{Id: cr3.Id})
+	assert.NoError(t, err)
+	assert.Equal(t, cr3.Id, cr.Id)
+	assert.Len(t, cr.Objects, 1)
+	assert.Equal(t, cr3.Objects[0].Rendered, cr.Objects[0].Rendered)
+
+	cr, err = s.GetCommandResult(GetCommandResultOptions{Id: cr1.Id})
+	assert.NoError(t, err)
+	assert.Nil(t, cr)
+
+	summary, err := s.GetCommandResultSummary(cr4.Id)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, summary.NewObjects)
+
+	_, err = s.GetCommandResult(GetCommandResultOptions{Id: "../foo"})
+	assert.Error(t, err)
+}
+
+func TestResultStoreS3Watch(t *testing.T) {
+	s, err := NewResultStoreS3(context.Background(), newFakeS3Client(), "bucket", "", RetentionPolicy{KeepCount: 1})
+	assert.NoError(t, err)
+	s.PollInterval = 100 * time.Millisecond
+
+	now := time.Now().Truncate(time.Second)
+	cr1 := buildTestCommandResult("t1", now)
+	assert.NoError(t, s.WriteCommandResult(cr1))
+
+	initial, ch, cancel, err := s.WatchCommandResultSummaries(ListCommandResultSummariesOptions{})
+	assert.NoError(t, err)
+	defer cancel()
+	assert.Len(t, initial, 1)
+	assert.Equal(t, cr1.Id, initial[0].Id)
+
+	// writing cr2 causes cr1 to be deleted
+	cr2 := buildTestCommandResult("t1", now.Add(time.Minute))
+	assert.NoError(t, s.WriteCommandResult(cr2))
+
+	var added, deleted bool
+	timeout := time.After(10 * time.Second)
+	for !added || !deleted {
+		select {
+		case e := <-ch:
+			if e.Delete && e.Summary.Id == cr1.Id {
+				deleted = true
+			} else if !e.Delete && e.Summary.Id == cr2.Id {
+				added = true
+			}
+		case <-timeout:
+			t.Fatal("timeout while waiting for watch events")
+		}
+	}
+}
@@ -4,7 +4,6 @@ import (
 	"compress/gzip"
 	"context"
 	"fmt"
-	"github.com/kluctl/kluctl/v2/pkg/status"
 	"github.com/kluctl/kluctl/v2/pkg/types/result"
 	"github.com/kluctl/kluctl/v2/pkg/utils"
 	"github.com/kluctl/kluctl/v2/pkg/yaml"
@@ -17,26 +16,36 @@ import (
 	"regexp"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
 )
 
+// maxSecretDataSize is the maximum amount of data stored in a single Secret. Kubernetes limits Secrets to 1MiB, which
+// includes metadata (e.g. the summary annotation), so we stay well below this limit.
+const maxSecretDataSize = 512 * 1024
+
 type ResultStoreSecrets struct {
 	ctx    context.Context
 	client client.WithWatch
 
-	writeNamespace   string
-	keepResultsCount int
+	writeNamespace string
+	retention      RetentionPolicy
+
+	// chunkSize is the maximum size of data stored per Secret. Results that exceed this size are split into multiple
+	// chunk Secrets
+	chunkSize int
 
 	mutex sync.Mutex
 }
 
-func NewResultStoreSecrets(ctx context.Context, client client.WithWatch, writeNamespace string, keepResultsCount int) (*ResultStoreSecrets, error) {
+func NewResultStoreSecrets(ctx context.Context, client client.WithWatch, writeNamespace string, retention RetentionPolicy) (*ResultStoreSecrets, error) {
 	s := &ResultStoreSecrets{
-		ctx:              ctx,
-		client:           client,
-		writeNamespace:   writeNamespace,
-		keepResultsCount: keepResultsCount,
+		ctx:            ctx,
+		client:         client,
+		writeNamespace: writeNamespace,
+		retention:      retention,
+		chunkSize:      maxSecretDataSize,
 	}
 
 	return s, nil
@@ -120,25 +129,29 @@ func (s *ResultStoreSecrets) WriteCommandResult(cr *result.CommandResult) error
 		return err
 	}
 
+	name := s.buildName(cr)
+	data, chunks := s.splitChunks([]secretField{
+		{name: "reducedResult", data: compressedCr},
+		{name: "compactedObjects", data: compressedObjects},
+	})
+
 	secret := corev1.Secret{
 		TypeMeta: metav1.TypeMeta{
 			APIVersion: "v1",
 			Kind:       "Secret",
 		},
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      s.buildName(cr),
+			Name:      name,
 			Namespace: s.writeNamespace,
 			Labels: map[string]string{
-				"kluctl.io/result-id": cr.Id,
+				"kluctl.io/result-id":      cr.Id,
+				"kluctl.io/result-part-of": cr.Id,
 			},
 			Annotations: map[string]string{
 				"kluctl.io/result-summary": summaryJson,
 			},
 		},
-		Data: map[string][]byte{
-			"reducedResult":    compressedCr,
-			"compactedObjects": compressedObjects,
-		},
+		Data: data,
 	}
 	if cr.ProjectKey.GitRepoKey.String() != "" {
 		secret.Annotations["kluctl.io/result-project-repo-key"] = cr.ProjectKey.GitRepoKey.String()
@@ -146,13 +159,46 @@ func (s *ResultStoreSecrets) WriteCommandResult(cr *result.CommandResult) error
 	if cr.ProjectKey.SubDir != "" {
 		secret.Annotations["kluctl.io/result-project-subdir"] = cr.ProjectKey.SubDir
 	}
+	if len(chunks) != 0 {
+		secret.Annotations["kluctl.io/result-chunks"] = strconv.Itoa(len(chunks))
+	}
+
+	// chunks are written first, so that readers never see a result with missing chunks
+	for i, chunk := range chunks {
+		chunkSecret := corev1.Secret{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: "v1",
+				Kind:       "Secret",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf("%s-chunk-%d", name, i),
+				Namespace: s.writeNamespace,
+				Labels: map[string]string{
+					"kluctl.io/result-part-of":     cr.Id,
+					"kluctl.io/result-chunk-of":    cr.Id,
+					"kluctl.io/result-chunk-index": strconv.Itoa(i),
+				},
+			},
+			Data: chunk,
+		}
+		err = s.client.Patch(s.ctx, &chunkSecret, client.Apply, client.FieldOwner("kluctl-results"))
+		if err != nil {
+			return err
+		}
+	}
 
 	err = s.client.Patch(s.ctx, &secret, client.Apply, client.FieldOwner("kluctl-results"))
 	if err != nil {
 		return err
 	}
 
-	err = s.cleanupResults(cr.ProjectKey, cr.TargetKey)
+	// the result might have been written before with more chunks
+	err = s.deleteStaleChunks(s.writeNamespace, cr.Id, len(chunks))
+	if err != nil {
+		return err
+	}
+
+	err = cleanupResults(s.ctx, s, s.retention, cr.ProjectKey, cr.TargetKey)
 	if err != nil {
 		return err
 	}
@@ -160,37 +206,89 @@ func (s *ResultStoreSecrets) WriteCommandResult(cr *result.CommandResult) error
 	return nil
 }
 
-func (s *ResultStoreSecrets) cleanupResults(project result.ProjectKey, target result.TargetKey) error {
-	results, err := s.ListCommandResultSummaries(ListCommandResultSummariesOptions{
-		ProjectFilter: &project,
+type secretField struct {
+	name string
+	data []byte
+}
+
+// splitChunks decides which fields can be stored inline and which fields must be split into chunks. Each chunk
+// holds a part of a single field, stored under the field name. Chunks must be concatenated in order to get the
+// original field data.
+func (s *ResultStoreSecrets) splitChunks(fields []secretField) (map[string][]byte, []map[string][]byte) {
+	inline := map[string][]byte{}
+	var chunks []map[string][]byte
+
+	remaining := s.chunkSize
+	for _, f := range fields {
+		if len(f.data) <= remaining {
+			inline[f.name] = f.data
+			remaining -= len(f.data)
+			continue
+		}
+		data := f.data
+		for len(data) != 0 {
+			n := len(data)
+			if n > s.chunkSize {
+				n = s.chunkSize
+			}
+			chunks = append(chunks, map[string][]byte{
+				f.name: data[:n],
+			})
+			data = data[n:]
+		}
+	}
+	return inline, chunks
+}
+
+// deleteStaleChunks deletes all chunk Secrets of the given result with an index that is not below chunkCount
+func (s *ResultStoreSecrets) deleteStaleChunks(namespace string, id string, chunkCount int) error {
+	var l metav1.PartialObjectMetadataList
+	l.SetGroupVersionKind(schema.GroupVersionKind{Version: "v1", Kind: "SecretList"})
+	err := s.client.List(s.ctx, &l, client.InNamespace(namespace), client.MatchingLabels{
+		"kluctl.io/result-chunk-of": id,
 	})
 	if err != nil {
 		return err
 	}
-
-	cnt := 0
-	for _, rs := range results {
-		rs := rs
-
-		if rs.TargetKey != target {
+	for i := range l.Items {
+		x := &l.Items[i]
+		idx, err := strconv.Atoi(x.GetLabels()["kluctl.io/result-chunk-index"])
+		if err == nil && idx < chunkCount {
 			continue
 		}
-		cnt++
-
-		if cnt > s.keepResultsCount {
-			err := s.client.DeleteAllOf(s.ctx, &corev1.Secret{}, client.InNamespace(s.writeNamespace), client.MatchingLabels{
-				"kluctl.io/result-id": rs.Id,
-			})
-			if err != nil {
-				status.Warning(s.ctx, "Failed to delete old command result %s: %s", rs.Id, err)
-			} else {
-				status.Info(s.ctx, "Deleted old command result %s", rs.Id)
-			}
+		err = s.client.Delete(s.ctx, &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: x.GetName(), Namespace: x.GetNamespace()}})
+		if err != nil && !errors.IsNotFound(err) {
+			return err
 		}
 	}
 	return nil
 }
 
+// DeleteCommandResult deletes the result Secret and all its chunks in one step, as all of them share the same
+// kluctl.io/result-part-of label.
+func (s *ResultStoreSecrets) DeleteCommandResult(id string) error {
+	secret, err := s.getCommandResultSecret(id)
+	if err != nil {
+		return err
+	}
+	if secret == nil {
+		return nil
+	}
+
+	if _, ok := secret.GetLabels()["kluctl.io/result-part-of"]; !ok {
+		// results written by older versions are never chunked
+		err = s.client.Delete(s.ctx, &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: secret.GetName(), Namespace: secret.GetNamespace()}})
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		return nil
+	}
+
+	return s.client.DeleteAllOf(s.ctx, &corev1.Secret{}, client.InNamespace(secret.GetNamespace()), client.MatchingLabels{
+		"kluctl.io/result-part-of": id,
+	})
+}
+
 func (s *ResultStoreSecrets) ListCommandResultSummaries(options ListCommandResultSummariesOptions) ([]result.CommandResultSummary, error) {
 	var l metav1.PartialObjectMetadataList
 	l.SetGroupVersionKind(schema.GroupVersionKind{Version: "v1", Kind: "SecretList"})
@@ -348,6 +446,42 @@ func (s *ResultStoreSecrets) GetCommandResultSummary(id string) (*result.Command
 	return s.parseSummary(secret.GetAnnotations())
 }
 
+// loadChunks reassembles all chunked fields and stores them in the Data of the given result Secret
+func (s *ResultStoreSecrets) loadChunks(secret *corev1.Secret, id string) error {
+	chunkCount, err := strconv.Atoi(secret.Annotations["kluctl.io/result-chunks"])
+	if err != nil {
+		return fmt.Errorf("invalid or missing chunks annotation for %s", id)
+	}
+
+	var l corev1.SecretList
+	err = s.client.List(s.ctx, &l, client.InNamespace(secret.Namespace), client.MatchingLabels{
+		"kluctl.io/result-chunk-of": id,
+	})
+	if err != nil {
+		return err
+	}
+
+	chunks := make([]*corev1.Secret, chunkCount)
+	for i := range l.Items {
+		x := &l.Items[i]
+		idx, err := strconv.Atoi(x.Labels["kluctl.io/result-chunk-index"])
+		if err != nil || idx < 0 || idx >= chunkCount {
+			return fmt.Errorf("invalid chunk index for chunk %s of %s", x.Name, id)
+		}
+		chunks[idx] = x
+	}
+
+	for i, x := range chunks {
+		if x == nil {
+			return fmt.Errorf("chunk %d of %s is missing", i, id)
+		}
+		for k, v := range x.Data {
+			secret.Data[k] = append(secret.Data[k], v...)
+		}
+	}
+	return nil
+}
+
 func (s *ResultStoreSecrets) GetCommandResult(options GetCommandResultOptions) (*result.CommandResult, error) {
 	has, err := s.HasCommandResult(options.Id)
 	if err != nil {
@@ -370,6 +504,20 @@ func (s *ResultStoreSecrets) GetCommandResult(options GetCommandResultOptions) (
 
 	secret := l.Items[0]
 
+	_, needChunks := secret.Data["reducedResult"]
+	needChunks = !needChunks
+	if !options.Reduced {
+		if _, ok := secret.Data["compactedObjects"]; !ok {
+			needChunks = true
+		}
+	}
+	if needChunks {
+		err = s.loadChunks(&secret, options.Id)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	var crJson, objectsJson []byte
 	err = utils.RunParallelE(s.ctx, func() error {
 		j, ok := secret.Data["reducedResult"]
@@ -0,0 +1,126 @@
+package results
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sort"
+	"strconv"
+	"testing"
+)
+
+func TestResultStoreSecretsSplitChunks(t *testing.T) {
+	s := &ResultStoreSecrets{chunkSize: 10}
+
+	small := []byte("abc")
+	large := bytes.Repeat([]byte("0123456789"), 3)
+	large = append(large, 'x')
+
+	inline, chunks := s.splitChunks([]secretField{
+		{name: "reducedResult", data: small},
+		{name: "compactedObjects", data: large},
+	})
+	assert.Equal(t, map[string][]byte{"reducedResult": small}, inline)
+	assert.Len(t, chunks, 4)
+
+	var joined []byte
+	for _, c := range chunks {
+		assert.Len(t, c, 1)
+		assert.LessOrEqual(t, len(c["compactedObjects"]), 10)
+		joined = append(joined, c["compactedObjects"]...)
+	}
+	assert.Equal(t, large, joined)
+
+	inline, chunks = s.splitChunks([]secretField{
+		{name: "reducedResult", data: []byte("01234")},
+		{name: "compactedObjects", data: []byte("56789")},
+	})
+	assert.Len(t, inline, 2)
+	assert.Empty(t, chunks)
+
+	// the second field does not fit into the remaining space anymore
+	inline, chunks = s.splitChunks([]secretField{
+		{name: "reducedResult", data: []byte("01234")},
+		{name: "compactedObjects", data: []byte("567890")},
+	})
+	assert.Len(t, inline, 1)
+	assert.Equal(t, []map[string][]byte{{"compactedObjects": []byte("567890")}}, chunks)
+}
+
+func buildTestResultSecrets(id string, chunks int, partOf bool) []client.Object {
+	labels := map[string]string{"kluctl.io/result-id": id}
+	if partOf {
+		labels["kluctl.io/result-part-of"] = id
+	}
+	ret := []client.Object{&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: id, Namespace: "ns", Labels: labels},
+	}}
+	for i := 0; i < chunks; i++ {
+		ret = append(ret, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf("%s-chunk-%d", id, i),
+				Namespace: "ns",
+				Labels: map[string]string{
+					"kluctl.io/result-part-of":     id,
+					"kluctl.io/result-chunk-of":    id,
+					"kluctl.io/result-chunk-index": strconv.Itoa(i),
+				},
+			},
+		})
+	}
+	return ret
+}
+
+func listSecretNames(t *testing.T, c client.Client) []string {
+	var l corev1.SecretList
+	err := c.List(context.Background(), &l)
+	assert.NoError(t, err)
+	var ret []string
+	for _, x := range l.Items {
+		ret = append(ret, x.Name)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+func TestResultStoreSecretsDeleteCommandResult(t *testing.T) {
+	var objs []client.Object
+	objs = append(objs, buildTestResultSecrets("r1", 2, true)...)
+	objs = append(objs, buildTestResultSecrets("r2", 1, true)...)
+	objs = append(objs, buildTestResultSecrets("legacy", 0, false)...)
+	c := fake.NewClientBuilder().WithObjects(objs...).Build()
+	s := &ResultStoreSecrets{ctx: context.Background(), client: c}
+
+	err := s.DeleteCommandResult("r1")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"legacy", "r2", "r2-chunk-0"}, listSecretNames(t, c))
+
+	err = s.DeleteCommandResult("legacy")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"r2", "r2-chunk-0"}, listSecretNames(t, c))
+
+	// deleting a result that does not exist is not an error
+	err = s.DeleteCommandResult("r1")
+	assert.NoError(t, err)
+}
+
+func TestResultStoreSecretsDeleteStaleChunks(t *testing.T) {
+	var objs []client.Object
+	objs = append(objs, buildTestResultSecrets("r1", 4, true)...)
+	objs = append(objs, buildTestResultSecrets("r2", 2, true)...)
+	c := fake.NewClientBuilder().WithObjects(objs...).Build()
+	s := &ResultStoreSecrets{ctx: context.Background(), client: c}
+
+	err := s.deleteStaleChunks("ns", "r1", 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"r1", "r1-chunk-0", "r1-chunk-1", "r2", "r2-chunk-0", "r2-chunk-1"}, listSecretNames(t, c))
+
+	err = s.deleteStaleChunks("ns", "r1", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"r1", "r2", "r2-chunk-0", "r2-chunk-1"}, listSecretNames(t, c))
+}
@@ -35,6 +35,8 @@ type ResultStore interface {
 	HasCommandResult(id string) (bool, error)
 	GetCommandResultSummary(id string) (*result.CommandResultSummary, error)
 	GetCommandResult(options GetCommandResultOptions) (*result.CommandResult, error)
+
+	DeleteCommandResult(id string) error
 }
 
 func FilterSummary(x *result.CommandResultSummary, filter *result.ProjectKey) bool {
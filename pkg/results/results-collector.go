@@ -204,3 +204,13 @@ func (rc *ResultsCollector) GetCommandResult(options GetCommandResultOptions) (*
 	}
 	return se.store.GetCommandResult(options)
 }
+
+func (rc *ResultsCollector) DeleteCommandResult(id string) error {
+	rc.mutex.Lock()
+	se, ok := rc.resultSummaries[id]
+	rc.mutex.Unlock()
+	if !ok {
+		return nil
+	}
+	return se.store.DeleteCommandResult(id)
+}
@@ -0,0 +1,129 @@
+package results
+
+import (
+	"context"
+	"github.com/kluctl/kluctl/v2/pkg/status"
+	"github.com/kluctl/kluctl/v2/pkg/types/result"
+	"sort"
+	"time"
+)
+
+// RetentionPolicy controls which command results are kept when old results are cleaned up. The policy is always
+// applied per project and target.
+type RetentionPolicy struct {
+	// KeepCount is the number of results to keep.
+	KeepCount int
+	// KeepCountPerCommand overrides KeepCount for individual commands, e.g. "deploy" or "diff". Results of commands
+	// found in this map are counted separately from all other results.
+	KeepCountPerCommand map[string]int
+	// MaxAge causes results to be deleted when they are older than the given duration. Zero disables this.
+	MaxAge time.Duration
+	// KeepLastSuccessfulDeploy causes the newest successful (non dry-run) deploy to be always kept.
+	KeepLastSuccessfulDeploy bool
+	// KeepFailedCount is the number of failed results that are always kept.
+	KeepFailedCount int
+}
+
+// SelectForDeletion returns the summaries that must be deleted according to the policy. All summaries must belong to
+// the same project and target.
+func (p *RetentionPolicy) SelectForDeletion(summaries []result.CommandResultSummary, now time.Time) []result.CommandResultSummary {
+	sorted := make([]result.CommandResultSummary, len(summaries))
+	copy(sorted, summaries)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return lessSummary(&sorted[i], &sorted[j])
+	})
+
+	counts := map[string]int{}
+	foundSuccessfulDeploy := false
+	failedCount := 0
+
+	var ret []result.CommandResultSummary
+	for _, rs := range sorted {
+		failed := len(rs.Errors) != 0
+
+		protected := false
+		if p.KeepLastSuccessfulDeploy && !foundSuccessfulDeploy && !failed && rs.Command.Command == "deploy" && !rs.Command.DryRun {
+			foundSuccessfulDeploy = true
+			protected = true
+		}
+		if failed {
+			failedCount++
+			if failedCount <= p.KeepFailedCount {
+				protected = true
+			}
+		}
+
+		countKey := ""
+		limit := p.KeepCount
+		if l, ok := p.KeepCountPerCommand[rs.Command.Command]; ok {
+			countKey = rs.Command.Command
+			limit = l
+		}
+		counts[countKey]++
+
+		expired := counts[countKey] > limit
+		if p.MaxAge != 0 && now.Sub(rs.Command.StartTime.Time) > p.MaxAge {
+			expired = true
+		}
+
+		if expired && !protected {
+			ret = append(ret, rs)
+		}
+	}
+	return ret
+}
+
+// cleanupResults applies the retention policy to all results of the given project and target
+func cleanupResults(ctx context.Context, store ResultStore, policy RetentionPolicy, project result.ProjectKey, target result.TargetKey) error {
+	summaries, err := store.ListCommandResultSummaries(ListCommandResultSummariesOptions{
+		ProjectFilter: &project,
+	})
+	if err != nil {
+		return err
+	}
+
+	var forTarget []result.CommandResultSummary
+	for _, rs := range summaries {
+		if rs.TargetKey == target {
+			forTarget = append(forTarget, rs)
+		}
+	}
+
+	deleteResults(ctx, store, policy.SelectForDeletion(forTarget, time.Now()))
+	return nil
+}
+
+// ApplyRetentionPolicy applies the retention policy to all results found in the given store
+func ApplyRetentionPolicy(ctx context.Context, store ResultStore, policy RetentionPolicy) error {
+	summaries, err := store.ListCommandResultSummaries(ListCommandResultSummariesOptions{})
+	if err != nil {
+		return err
+	}
+
+	type groupKey struct {
+		project result.ProjectKey
+		target  result.TargetKey
+	}
+	groups := map[groupKey][]result.CommandResultSummary{}
+	for _, rs := range summaries {
+		k := groupKey{project: rs.ProjectKey, target: rs.TargetKey}
+		groups[k] = append(groups[k], rs)
+	}
+
+	now := time.Now()
+	for _, g := range groups {
+		deleteResults(ctx, store, policy.SelectForDeletion(g, now))
+	}
+	return nil
+}
+
+func deleteResults(ctx context.Context, store ResultStore, summaries []result.CommandResultSummary) {
+	for _, rs := range summaries {
+		err := store.DeleteCommandResult(rs.Id)
+		if err != nil {
+			status.Warning(ctx, "Failed to delete old command result %s: %s", rs.Id, err)
+		} else {
+			status.Info(ctx, "Deleted old command result %s", rs.Id)
+		}
+	}
+}
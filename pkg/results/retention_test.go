@@ -0,0 +1,94 @@
+package results
+
+import (
+	"github.com/kluctl/kluctl/v2/pkg/types/result"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"testing"
+	"time"
+)
+
+func buildTestSummary(id string, command string, startTime time.Time, failed bool) result.CommandResultSummary {
+	s := result.CommandResultSummary{
+		Id: id,
+		Command: result.CommandInfo{
+			Command:   command,
+			StartTime: metav1.NewTime(startTime),
+		},
+	}
+	if failed {
+		s.Errors = []result.DeploymentError{{Message: "error"}}
+	}
+	return s
+}
+
+func selectedIds(l []result.CommandResultSummary) []string {
+	var ret []string
+	for _, x := range l {
+		ret = append(ret, x.Id)
+	}
+	return ret
+}
+
+func TestRetentionPolicy(t *testing.T) {
+	now := time.Now()
+	day := 24 * time.Hour
+
+	// newest first
+	summaries := []result.CommandResultSummary{
+		buildTestSummary("diff-1", "diff", now.Add(-1*time.Hour), false),
+		buildTestSummary("deploy-1", "deploy", now.Add(-2*time.Hour), true),
+		buildTestSummary("diff-2", "diff", now.Add(-3*time.Hour), false),
+		buildTestSummary("deploy-2", "deploy", now.Add(-2*day), false),
+		buildTestSummary("deploy-3", "deploy", now.Add(-3*day), true),
+		buildTestSummary("prune-1", "prune", now.Add(-10*day), false),
+	}
+
+	tests := []struct {
+		name     string
+		policy   RetentionPolicy
+		expected []string
+	}{
+		{
+			name:     "count",
+			policy:   RetentionPolicy{KeepCount: 3},
+			expected: []string{"deploy-2", "deploy-3", "prune-1"},
+		},
+		{
+			name:     "count-per-command",
+			policy:   RetentionPolicy{KeepCount: 2, KeepCountPerCommand: map[string]int{"diff": 1}},
+			expected: []string{"diff-2", "deploy-3", "prune-1"},
+		},
+		{
+			name:     "max-age",
+			policy:   RetentionPolicy{KeepCount: 10, MaxAge: day},
+			expected: []string{"deploy-2", "deploy-3", "prune-1"},
+		},
+		{
+			name:     "keep-last-successful-deploy",
+			policy:   RetentionPolicy{KeepCount: 2, KeepLastSuccessfulDeploy: true},
+			expected: []string{"diff-2", "deploy-3", "prune-1"},
+		},
+		{
+			name:     "keep-failed",
+			policy:   RetentionPolicy{KeepCount: 1, MaxAge: day, KeepFailedCount: 2},
+			expected: []string{"diff-2", "deploy-2", "prune-1"},
+		},
+		{
+			name:     "keep-nothing",
+			policy:   RetentionPolicy{},
+			expected: []string{"diff-1", "deploy-1", "diff-2", "deploy-2", "deploy-3", "prune-1"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// reverse the order to ensure that SelectForDeletion does its own sorting
+			var reversed []result.CommandResultSummary
+			for i := len(summaries) - 1; i >= 0; i-- {
+				reversed = append(reversed, summaries[i])
+			}
+			assert.Equal(t, tc.expected, selectedIds(tc.policy.SelectForDeletion(reversed, now)))
+		})
+	}
+}
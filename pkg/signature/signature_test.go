@@ -0,0 +1,142 @@
+package signature
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/ssh"
+	"strings"
+	"testing"
+)
+
+func newTestGpgKey(t *testing.T) (*Signer, []byte, string) {
+	e, err := openpgp.NewEntity("Test User", "", "no@mail.com", nil)
+	assert.NoError(t, err)
+
+	buf := bytes.NewBuffer(nil)
+	w, err := armor.Encode(buf, openpgp.PublicKeyType, nil)
+	assert.NoError(t, err)
+	assert.NoError(t, e.Serialize(w))
+	assert.NoError(t, w.Close())
+	return NewGpgSigner(e), buf.Bytes(), strings.ToUpper(hex.EncodeToString(e.PrimaryKey.Fingerprint))
+}
+
+func newTestSshKey(t *testing.T, rsaKey bool) (*Signer, []byte, string) {
+	var s ssh.Signer
+	var err error
+	if rsaKey {
+		k, err2 := rsa.GenerateKey(rand.Reader, 2048)
+		assert.NoError(t, err2)
+		s, err = ssh.NewSignerFromKey(k)
+	} else {
+		_, k, err2 := ed25519.GenerateKey(rand.Reader)
+		assert.NoError(t, err2)
+		s, err = ssh.NewSignerFromKey(k)
+	}
+	assert.NoError(t, err)
+	return NewSshSigner(s), ssh.MarshalAuthorizedKey(s.PublicKey()), ssh.FingerprintSHA256(s.PublicKey())
+}
+
+func TestSignVerify(t *testing.T) {
+	gpgSigner, gpgPub, gpgFp := newTestGpgKey(t)
+	otherGpgSigner, _, _ := newTestGpgKey(t)
+	sshSigner, sshPub, sshFp := newTestSshKey(t, false)
+	rsaSigner, rsaPub, rsaFp := newTestSshKey(t, true)
+	otherSshSigner, _, _ := newTestSshKey(t, false)
+
+	keys, err := ParsePublicKeys([][]byte{gpgPub, append(sshPub, rsaPub...)})
+	assert.NoError(t, err)
+
+	payload := []byte("payload")
+
+	for _, tc := range []struct {
+		name   string
+		signer *Signer
+		fp     string
+		err    string
+	}{
+		{name: "gpg", signer: gpgSigner, fp: gpgFp},
+		{name: "ssh", signer: sshSigner, fp: sshFp},
+		{name: "ssh-rsa", signer: rsaSigner, fp: rsaFp},
+		{name: "untrusted-gpg", signer: otherGpgSigner, err: "failed to verify GPG signature"},
+		{name: "untrusted-ssh", signer: otherSshSigner, err: "untrusted key"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			sig, err := tc.signer.Sign("test", payload)
+			assert.NoError(t, err)
+
+			fp, err := keys.Verify("test", sig, payload)
+			if tc.err != "" {
+				assert.ErrorContains(t, err, tc.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.fp, fp)
+
+			_, err = keys.Verify("test", sig, []byte("modified"))
+			assert.Error(t, err)
+		})
+	}
+
+	_, err = keys.Verify("test", "", payload)
+	assert.ErrorContains(t, err, "no signature found")
+
+	sig, err := sshSigner.Sign("other", payload)
+	assert.NoError(t, err)
+	_, err = keys.Verify("test", sig, payload)
+	assert.ErrorContains(t, err, "unexpected SSH signature namespace")
+
+	gpgOnly, err := ParsePublicKeys([][]byte{gpgPub})
+	assert.NoError(t, err)
+	sig, err = sshSigner.Sign("test", payload)
+	assert.NoError(t, err)
+	_, err = gpgOnly.Verify("test", sig, payload)
+	assert.ErrorContains(t, err, "no SSH keys are trusted")
+}
+
+func TestParsePublicKeys(t *testing.T) {
+	_, err := ParsePublicKeys(nil)
+	assert.Error(t, err)
+
+	_, err = ParsePublicKeys([][]byte{[]byte("invalid")})
+	assert.Error(t, err)
+
+	_, pub1, _ := newTestSshKey(t, false)
+	_, pub2, _ := newTestSshKey(t, false)
+	keys, err := ParsePublicKeys([][]byte{append(pub1, pub2...)})
+	assert.NoError(t, err)
+	assert.Len(t, keys.ssh, 2)
+}
+
+func TestParsePrivateKey(t *testing.T) {
+	_, k, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	b, err := x509.MarshalPKCS8PrivateKey(k)
+	assert.NoError(t, err)
+
+	s, err := ParsePrivateKey(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: b}))
+	assert.NoError(t, err)
+	assert.NotNil(t, s.ssh)
+
+	e, err := openpgp.NewEntity("Test User", "", "no@mail.com", nil)
+	assert.NoError(t, err)
+	buf := bytes.NewBuffer(nil)
+	w, err := armor.Encode(buf, openpgp.PrivateKeyType, nil)
+	assert.NoError(t, err)
+	assert.NoError(t, e.SerializePrivate(w, nil))
+	assert.NoError(t, w.Close())
+
+	s, err = ParsePrivateKey(buf.Bytes())
+	assert.NoError(t, err)
+	assert.NotNil(t, s.gpg)
+
+	_, err = ParsePrivateKey([]byte("invalid"))
+	assert.Error(t, err)
+}
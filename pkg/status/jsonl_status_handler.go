@@ -0,0 +1,214 @@
+package status
+
+import (
+	"encoding/json"
+	"github.com/kluctl/kluctl/v2/pkg/types/k8s"
+	"io"
+	"sync"
+	"time"
+)
+
+type EventType string
+
+const (
+	EventStatusStart  EventType = "status-start"
+	EventStatusUpdate EventType = "status-update"
+	EventStatusEnd    EventType = "status-end"
+	EventInfo         EventType = "info"
+	EventWarning      EventType = "warning"
+	EventError        EventType = "error"
+	EventTrace        EventType = "trace"
+	EventPrompt       EventType = "prompt"
+	EventObjectAction EventType = "object"
+)
+
+// Event is a single line emitted by the jsonl status handler.
+type Event struct {
+	Time time.Time `json:"time"`
+	Type EventType `json:"type"`
+
+	// StatusId identifies the status line for status-start/status-update/status-end events
+	StatusId int    `json:"statusId,omitempty"`
+	Total    int    `json:"total,omitempty"`
+	Current  int    `json:"current,omitempty"`
+	Result   string `json:"result,omitempty"`
+
+	Message string `json:"message,omitempty"`
+
+	Action ObjectAction   `json:"action,omitempty"`
+	Ref    *k8s.ObjectRef `json:"ref,omitempty"`
+}
+
+type jsonlStatusHandler struct {
+	out        io.Writer
+	isTerminal bool
+	trace      bool
+
+	mutex  sync.Mutex
+	nextId int
+}
+
+type jsonlStatusLine struct {
+	sh      *jsonlStatusHandler
+	id      int
+	total   int
+	current int
+	message string
+	ended   bool
+}
+
+// NewJsonlStatusHandler creates a StatusHandler that writes one JSON encoded Event per line to out.
+func NewJsonlStatusHandler(out io.Writer, isTerminal bool, trace bool) StatusHandler {
+	return &jsonlStatusHandler{
+		out:        out,
+		isTerminal: isTerminal,
+		trace:      trace,
+	}
+}
+
+func (s *jsonlStatusHandler) emit(e Event) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.emitLocked(e)
+}
+
+func (s *jsonlStatusHandler) emitLocked(e Event) {
+	e.Time = time.Now()
+	b, err := json.Marshal(&e)
+	if err != nil {
+		return
+	}
+	b = append(b, '\n')
+	_, _ = s.out.Write(b)
+}
+
+func (s *jsonlStatusHandler) IsTerminal() bool {
+	return s.isTerminal
+}
+
+func (s *jsonlStatusHandler) IsTraceEnabled() bool {
+	return s.trace
+}
+
+func (s *jsonlStatusHandler) SetTrace(trace bool) {
+	s.trace = trace
+}
+
+func (s *jsonlStatusHandler) Stop() {
+}
+
+func (s *jsonlStatusHandler) Flush() {
+}
+
+func (s *jsonlStatusHandler) StartStatus(total int, message string) StatusLine {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.nextId++
+	sl := &jsonlStatusLine{
+		sh:      s,
+		id:      s.nextId,
+		total:   total,
+		message: message,
+	}
+	s.emitLocked(Event{
+		Type:     EventStatusStart,
+		StatusId: sl.id,
+		Total:    total,
+		Message:  message,
+	})
+	return sl
+}
+
+func (s *jsonlStatusHandler) Info(message string) {
+	s.emit(Event{Type: EventInfo, Message: message})
+}
+
+func (s *jsonlStatusHandler) Warning(message string) {
+	s.emit(Event{Type: EventWarning, Message: message})
+}
+
+func (s *jsonlStatusHandler) Error(message string) {
+	s.emit(Event{Type: EventError, Message: message})
+}
+
+func (s *jsonlStatusHandler) Trace(message string) {
+	if s.trace {
+		s.emit(Event{Type: EventTrace, Message: message})
+	}
+}
+
+func (s *jsonlStatusHandler) PlainText(text string) {
+	s.Info(text)
+}
+
+func (s *jsonlStatusHandler) InfoFallback(message string) {
+	// status line updates are already emitted as events, so there is no need to duplicate them
+}
+
+func (s *jsonlStatusHandler) Prompt(password bool, message string) (string, error) {
+	s.emit(Event{Type: EventPrompt, Message: message})
+	return readPromptResponse(password)
+}
+
+func (s *jsonlStatusHandler) ObjectAction(action ObjectAction, ref k8s.ObjectRef) {
+	s.emit(Event{Type: EventObjectAction, Action: action, Ref: &ref})
+}
+
+var _ StatusHandler = &jsonlStatusHandler{}
+var _ ObjectActionHandler = &jsonlStatusHandler{}
+
+func (sl *jsonlStatusLine) SetTotal(total int) {
+	sl.sh.mutex.Lock()
+	defer sl.sh.mutex.Unlock()
+	sl.total = total
+}
+
+func (sl *jsonlStatusLine) Increment() {
+	sl.sh.mutex.Lock()
+	defer sl.sh.mutex.Unlock()
+	sl.current++
+}
+
+func (sl *jsonlStatusLine) Update(message string) {
+	sl.sh.mutex.Lock()
+	defer sl.sh.mutex.Unlock()
+	if sl.ended {
+		return
+	}
+	sl.message = message
+	sl.sh.emitLocked(Event{
+		Type:     EventStatusUpdate,
+		StatusId: sl.id,
+		Total:    sl.total,
+		Current:  sl.current,
+		Message:  message,
+	})
+}
+
+func (sl *jsonlStatusLine) End(result EndResult) {
+	sl.sh.mutex.Lock()
+	defer sl.sh.mutex.Unlock()
+	if sl.ended {
+		return
+	}
+	sl.ended = true
+
+	var r string
+	switch result {
+	case EndSuccess:
+		r = "success"
+	case EndWarning:
+		r = "warning"
+	case EndError:
+		r = "error"
+	}
+	sl.sh.emitLocked(Event{
+		Type:     EventStatusEnd,
+		StatusId: sl.id,
+		Total:    sl.total,
+		Current:  sl.current,
+		Result:   r,
+		Message:  sl.message,
+	})
+}
@@ -0,0 +1,94 @@
+package status
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/kluctl/kluctl/v2/pkg/types/k8s"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+)
+
+func parseEvents(t *testing.T, buf *bytes.Buffer) []Event {
+	var ret []Event
+	for _, l := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if l == "" {
+			continue
+		}
+		var e Event
+		err := json.Unmarshal([]byte(l), &e)
+		assert.NoError(t, err)
+		assert.False(t, e.Time.IsZero())
+		e.Time = time.Time{}
+		ret = append(ret, e)
+	}
+	return ret
+}
+
+func TestJsonlStatusHandler_StatusLines(t *testing.T) {
+	buf := bytes.NewBuffer(nil)
+	ctx := NewContext(context.Background(), NewJsonlStatusHandler(buf, false, false))
+
+	s1 := Start(ctx, "first")
+	s2 := StartWithOptions(ctx, WithPrefix("p"), WithStatus("second"), WithTotal(2))
+	s2.Increment()
+	s2.Update("updated")
+	s1.Success()
+	s2.Failed()
+	// updates after the end are ignored
+	s2.Update("ignored")
+	s2.Failed()
+
+	assert.Equal(t, []Event{
+		{Type: EventStatusStart, StatusId: 1, Total: 1, Message: "first"},
+		{Type: EventStatusStart, StatusId: 2, Total: 2, Message: "p: second"},
+		{Type: EventStatusUpdate, StatusId: 2, Total: 2, Current: 1, Message: "p: updated"},
+		{Type: EventStatusEnd, StatusId: 1, Total: 1, Result: "success", Message: "first"},
+		{Type: EventStatusEnd, StatusId: 2, Total: 2, Current: 1, Result: "error", Message: "p: updated"},
+	}, parseEvents(t, buf))
+}
+
+func TestJsonlStatusHandler_Messages(t *testing.T) {
+	buf := bytes.NewBuffer(nil)
+	ctx := NewContext(context.Background(), NewJsonlStatusHandler(buf, false, false))
+
+	Info(ctx, "info %d", 1)
+	Warning(ctx, "warning")
+	Error(ctx, "error")
+	Trace(ctx, "not emitted")
+	InfoFallback(ctx, "not emitted")
+	PlainText(ctx, "plain")
+
+	FromContext(ctx).SetTrace(true)
+	Trace(ctx, "trace")
+
+	events := parseEvents(t, buf)
+	var types []EventType
+	var messages []string
+	for _, e := range events {
+		types = append(types, e.Type)
+		messages = append(messages, e.Message)
+	}
+	assert.Equal(t, []EventType{EventInfo, EventWarning, EventError, EventInfo, EventTrace}, types)
+	assert.Equal(t, []string{"info 1", "warning", "error", "plain", "trace"}, messages)
+}
+
+func TestJsonlStatusHandler_ObjectAction(t *testing.T) {
+	buf := bytes.NewBuffer(nil)
+	ctx := NewContext(context.Background(), NewJsonlStatusHandler(buf, false, false))
+
+	ref := k8s.ObjectRef{Version: "v1", Kind: "ConfigMap", Name: "cm", Namespace: "default"}
+	ReportObjectAction(ctx, ObjectApplied, ref)
+	ReportObjectAction(ctx, ObjectDeleted, ref)
+
+	events := parseEvents(t, buf)
+	assert.Len(t, events, 2)
+	assert.Equal(t, EventObjectAction, events[0].Type)
+	assert.Equal(t, ObjectApplied, events[0].Action)
+	assert.Equal(t, &ref, events[0].Ref)
+	assert.Equal(t, ObjectDeleted, events[1].Action)
+
+	assert.Contains(t, buf.String(), `"type":"object","action":"applied","ref":{"version":"v1","kind":"ConfigMap","name":"cm","namespace":"default"}`)
+}
@@ -77,7 +77,10 @@ func (s *simpleStatusHandler) InfoFallback(message string) {
 
 func (s *simpleStatusHandler) Prompt(password bool, message string) (string, error) {
 	s.cb(message)
+	return readPromptResponse(password)
+}
 
+func readPromptResponse(password bool) (string, error) {
 	if password {
 		bytePassword, err := term.ReadPassword(int(syscall.Stdin))
 		_, _ = fmt.Fprintf(os.Stderr, "\n")
@@ -3,6 +3,7 @@ package status
 import (
 	"context"
 	"fmt"
+	"github.com/kluctl/kluctl/v2/pkg/types/k8s"
 	"github.com/kluctl/kluctl/v2/pkg/utils"
 )
 
@@ -56,6 +57,21 @@ type StatusHandler interface {
 	Prompt(password bool, message string) (string, error)
 }
 
+type ObjectAction string
+
+const (
+	ObjectApplied  ObjectAction = "applied"
+	ObjectReplaced ObjectAction = "replaced"
+	ObjectDeleted  ObjectAction = "deleted"
+	ObjectHookRun  ObjectAction = "hook-run"
+)
+
+// ObjectActionHandler can optionally be implemented by a StatusHandler to get informed about actions performed on
+// individual objects, e.g. to report these in a machine-readable way.
+type ObjectActionHandler interface {
+	ObjectAction(action ObjectAction, ref k8s.ObjectRef)
+}
+
 type contextKey struct{}
 type contextValue struct {
 	slh             StatusHandler
@@ -275,6 +291,13 @@ func Deprecation(ctx context.Context, key string, message string) {
 	})
 }
 
+func ReportObjectAction(ctx context.Context, action ObjectAction, ref k8s.ObjectRef) {
+	slh := FromContext(ctx)
+	if h, ok := slh.(ObjectActionHandler); ok {
+		h.ObjectAction(action, ref)
+	}
+}
+
 func Flush(ctx context.Context) {
 	slh := FromContext(ctx)
 	slh.Flush()
@@ -12,6 +12,7 @@ type DeploymentItemConfig struct {
 	Git              *GitProject              `json:"git,omitempty"`
 	Tags             []string                 `json:"tags,omitempty"`
 	Barrier          bool                     `json:"barrier,omitempty"`
+	DependsOn        []string                 `json:"dependsOn,omitempty"`
 	Message          *string                  `json:"message,omitempty"`
 	WaitReadiness    bool                     `json:"waitReadiness,omitempty"`
 	Vars             []*VarsSource            `json:"vars,omitempty"`
@@ -99,6 +100,38 @@ func ValidateIgnoreForDiffItemConfig(sl validator.StructLevel) {
 	}
 }
 
+type HealthCheckCondition struct {
+	Cel      string  `json:"cel,omitempty"`
+	JsonPath string  `json:"jsonPath,omitempty"`
+	Value    *string `json:"value,omitempty"`
+	Message  string  `json:"message,omitempty"`
+}
+
+func ValidateHealthCheckCondition(sl validator.StructLevel) {
+	s := sl.Current().Interface().(HealthCheckCondition)
+	if (s.Cel == "") == (s.JsonPath == "") {
+		sl.ReportError(s, "self", "self", "exactly one of cel or jsonPath must be set", "")
+	}
+	if s.Value != nil && s.JsonPath == "" {
+		sl.ReportError(s, "value", "Value", "value can only be used with jsonPath", "")
+	}
+}
+
+type HealthCheckConfig struct {
+	Group       *string               `json:"group,omitempty"`
+	Kind        string                `json:"kind" validate:"required"`
+	Ready       *HealthCheckCondition `json:"ready,omitempty"`
+	Failed      *HealthCheckCondition `json:"failed,omitempty"`
+	Progressing *HealthCheckCondition `json:"progressing,omitempty"`
+}
+
+func ValidateHealthCheckConfig(sl validator.StructLevel) {
+	s := sl.Current().Interface().(HealthCheckConfig)
+	if s.Ready == nil && s.Failed == nil && s.Progressing == nil {
+		sl.ReportError(s, "self", "self", "at least one of ready, failed or progressing must be set", "")
+	}
+}
+
 type DeploymentProjectConfig struct {
 	Vars          []*VarsSource        `json:"vars,omitempty"`
 	SealedSecrets *SealedSecretsConfig `json:"sealedSecrets,omitempty"`
@@ -113,10 +146,13 @@ type DeploymentProjectConfig struct {
 	Tags              []string          `json:"tags,omitempty"`
 
 	IgnoreForDiff []*IgnoreForDiffItemConfig `json:"ignoreForDiff,omitempty"`
+	HealthChecks  []*HealthCheckConfig       `json:"healthChecks,omitempty"`
 }
 
 func init() {
 	yaml.Validator.RegisterStructValidation(ValidateDeploymentItemConfig, DeploymentItemConfig{})
 	yaml.Validator.RegisterStructValidation(ValidateDeleteObjectItemConfig, DeleteObjectItemConfig{})
 	yaml.Validator.RegisterStructValidation(ValidateIgnoreForDiffItemConfig, IgnoreForDiffItemConfig{})
+	yaml.Validator.RegisterStructValidation(ValidateHealthCheckConfig, HealthCheckConfig{})
+	yaml.Validator.RegisterStructValidation(ValidateHealthCheckCondition, HealthCheckCondition{})
 }
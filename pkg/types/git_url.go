@@ -48,6 +48,10 @@ func (u *GitUrl) UnmarshalJSON(b []byte) error {
 	if err != nil {
 		return err
 	}
+	if s == "" {
+		*u = GitUrl{}
+		return nil
+	}
 	u2, err := ParseGitUrl(s)
 	if err != nil {
 		return err
@@ -3,6 +3,7 @@ package types
 import (
 	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 type SealingConfig struct {
@@ -41,9 +42,33 @@ type SecretsConfig struct {
 	SecretSets    []SecretSet                `json:"secretSets,omitempty"`
 }
 
+type RolloutValidation struct {
+	Wait             *metav1.Duration `json:"wait,omitempty"`
+	Sleep            *metav1.Duration `json:"sleep,omitempty"`
+	WarningsAsErrors bool             `json:"warningsAsErrors,omitempty"`
+}
+
+type RolloutPause struct {
+	Duration *metav1.Duration `json:"duration,omitempty"`
+	Confirm  bool             `json:"confirm,omitempty"`
+}
+
+type RolloutWave struct {
+	Name       string             `json:"name,omitempty"`
+	Targets    []string           `json:"targets" validate:"required"`
+	Validation *RolloutValidation `json:"validation,omitempty"`
+	Pause      *RolloutPause      `json:"pause,omitempty"`
+}
+
+type Rollout struct {
+	Name  string         `json:"name" validate:"required"`
+	Waves []*RolloutWave `json:"waves" validate:"required"`
+}
+
 type KluctlProject struct {
 	Targets       []*Target        `json:"targets,omitempty"`
 	Args          []*DeploymentArg `json:"args,omitempty"`
 	SecretsConfig *SecretsConfig   `json:"secretsConfig,omitempty"`
 	Discriminator string           `json:"discriminator,omitempty"`
+	Rollouts      []*Rollout       `json:"rollouts,omitempty"`
 }
@@ -89,6 +89,7 @@ type CommandInfo struct {
 	ReplaceOnError        bool                   `json:"replaceOnError,omitempty"`
 	ForceReplaceOnError   bool                   `json:"forceReplaceOnError,omitempty"`
 	AbortOnError          bool                   `json:"abortOnError,omitempty"`
+	RollbackOnError       bool                   `json:"rollbackOnError,omitempty"`
 	IncludeTags           []string               `json:"includeTags,omitempty"`
 	ExcludeTags           []string               `json:"excludeTags,omitempty"`
 	IncludeDeploymentDirs []string               `json:"includeDeploymentDirs,omitempty"`
@@ -140,6 +141,9 @@ type CommandResult struct {
 	Errors     []DeploymentError  `json:"errors,omitempty"`
 	Warnings   []DeploymentError  `json:"warnings,omitempty"`
 	SeenImages []types.FixedImage `json:"seenImages,omitempty"`
+
+	// RolledBackTo is the id of the command result that was restored after the command failed
+	RolledBackTo string `json:"rolledBackTo,omitempty"`
 }
 
 func (cr *CommandResult) ToCompacted() *CompactedCommandResult {
@@ -0,0 +1,62 @@
+package result
+
+import (
+	"github.com/kluctl/kluctl/v2/pkg/types/k8s"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DriftedObject is an object that differs between the rendered and the remote state. Only the number of changes is
+// recorded, as drift detection results are stored in the status of KluctlDeployments and sent via notifications,
+// where the changes (which might contain secret data) must not be exposed.
+type DriftedObject struct {
+	Ref     k8s.ObjectRef `json:"ref"`
+	Changes int           `json:"changes"`
+}
+
+type DriftDetectionResult struct {
+	Id        string      `json:"id"`
+	StartTime metav1.Time `json:"startTime"`
+	EndTime   metav1.Time `json:"endTime"`
+
+	// Objects that differ between the rendered and the remote state
+	Objects []DriftedObject `json:"objects,omitempty"`
+	// Objects that are rendered but do not exist on the cluster
+	MissingObjects []k8s.ObjectRef `json:"missingObjects,omitempty"`
+
+	Warnings []DeploymentError `json:"warnings,omitempty"`
+	Errors   []DeploymentError `json:"errors,omitempty"`
+}
+
+func (dr *DriftDetectionResult) HasDrift() bool {
+	return len(dr.Objects) != 0 || len(dr.MissingObjects) != 0
+}
+
+// BuildDriftDetectionResult builds a DriftDetectionResult from the result of a diff. Hooks, orphan objects and objects
+// that would be deleted are not considered as drift.
+func (cr *CommandResult) BuildDriftDetectionResult() *DriftDetectionResult {
+	if cr == nil {
+		return nil
+	}
+
+	ret := &DriftDetectionResult{
+		Id:        cr.Id,
+		StartTime: cr.Command.StartTime,
+		EndTime:   cr.Command.EndTime,
+		Warnings:  cr.Warnings,
+		Errors:    cr.Errors,
+	}
+	for _, o := range cr.Objects {
+		if o.Hook || o.Orphan || o.Deleted {
+			continue
+		}
+		if o.New {
+			ret.MissingObjects = append(ret.MissingObjects, o.Ref)
+		} else if len(o.Changes) != 0 {
+			ret.Objects = append(ret.Objects, DriftedObject{
+				Ref:     o.Ref,
+				Changes: len(o.Changes),
+			})
+		}
+	}
+	return ret
+}
@@ -0,0 +1,87 @@
+package result
+
+import (
+	"encoding/json"
+	"github.com/kluctl/kluctl/v2/pkg/types/k8s"
+	"github.com/stretchr/testify/assert"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"testing"
+	"time"
+)
+
+func buildRef(name string) k8s.ObjectRef {
+	return k8s.ObjectRef{Version: "v1", Kind: "Secret", Name: name, Namespace: "default"}
+}
+
+func buildChanges(n int) []Change {
+	var ret []Change
+	for i := 0; i < n; i++ {
+		ret = append(ret, Change{
+			Type:        "update",
+			JsonPath:    "data.password",
+			OldValue:    &apiextensionsv1.JSON{Raw: []byte(`"secret-old"`)},
+			NewValue:    &apiextensionsv1.JSON{Raw: []byte(`"secret-new"`)},
+			UnifiedDiff: "-secret-old\n+secret-new",
+		})
+	}
+	return ret
+}
+
+func TestBuildDriftDetectionResult(t *testing.T) {
+	startTime := metav1.NewTime(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+	endTime := metav1.NewTime(startTime.Add(time.Minute))
+
+	cr := &CommandResult{
+		Id: "id1",
+		Command: CommandInfo{
+			StartTime: startTime,
+			EndTime:   endTime,
+		},
+		Objects: []ResultObject{
+			{BaseObject: BaseObject{Ref: buildRef("changed"), Changes: buildChanges(2)}},
+			{BaseObject: BaseObject{Ref: buildRef("unchanged")}},
+			{BaseObject: BaseObject{Ref: buildRef("new"), New: true}},
+			{BaseObject: BaseObject{Ref: buildRef("orphan"), Orphan: true}},
+			{BaseObject: BaseObject{Ref: buildRef("deleted"), Deleted: true}},
+			{BaseObject: BaseObject{Ref: buildRef("hook"), Hook: true, Changes: buildChanges(1)}},
+		},
+		Warnings: []DeploymentError{{Message: "warning"}},
+		Errors:   []DeploymentError{{Message: "error"}},
+	}
+
+	dr := cr.BuildDriftDetectionResult()
+	assert.Equal(t, &DriftDetectionResult{
+		Id:             "id1",
+		StartTime:      startTime,
+		EndTime:        endTime,
+		Objects:        []DriftedObject{{Ref: buildRef("changed"), Changes: 2}},
+		MissingObjects: []k8s.ObjectRef{buildRef("new")},
+		Warnings:       []DeploymentError{{Message: "warning"}},
+		Errors:         []DeploymentError{{Message: "error"}},
+	}, dr)
+	assert.True(t, dr.HasDrift())
+
+	// the changes themselves must never end up in the drift detection result
+	b, err := json.Marshal(dr)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(b), "secret-old")
+	assert.NotContains(t, string(b), "secret-new")
+}
+
+func TestBuildDriftDetectionResult_NoDrift(t *testing.T) {
+	cr := &CommandResult{
+		Id: "id1",
+		Objects: []ResultObject{
+			{BaseObject: BaseObject{Ref: buildRef("unchanged")}},
+			{BaseObject: BaseObject{Ref: buildRef("orphan"), Orphan: true}},
+		},
+	}
+	dr := cr.BuildDriftDetectionResult()
+	assert.Empty(t, dr.Objects)
+	assert.Empty(t, dr.MissingObjects)
+	assert.False(t, dr.HasDrift())
+
+	var nilCr *CommandResult
+	assert.Nil(t, nilCr.BuildDriftDetectionResult())
+}
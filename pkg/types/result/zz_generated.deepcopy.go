@@ -23,6 +23,7 @@ package result
 
 import (
 	"github.com/kluctl/kluctl/v2/pkg/types"
+	"github.com/kluctl/kluctl/v2/pkg/types/k8s"
 	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 )
 
@@ -322,6 +323,59 @@ func (in *DeploymentError) DeepCopy() *DeploymentError {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DriftDetectionResult) DeepCopyInto(out *DriftDetectionResult) {
+	*out = *in
+	in.StartTime.DeepCopyInto(&out.StartTime)
+	in.EndTime.DeepCopyInto(&out.EndTime)
+	if in.Objects != nil {
+		in, out := &in.Objects, &out.Objects
+		*out = make([]DriftedObject, len(*in))
+		copy(*out, *in)
+	}
+	if in.MissingObjects != nil {
+		in, out := &in.MissingObjects, &out.MissingObjects
+		*out = make([]k8s.ObjectRef, len(*in))
+		copy(*out, *in)
+	}
+	if in.Warnings != nil {
+		in, out := &in.Warnings, &out.Warnings
+		*out = make([]DeploymentError, len(*in))
+		copy(*out, *in)
+	}
+	if in.Errors != nil {
+		in, out := &in.Errors, &out.Errors
+		*out = make([]DeploymentError, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DriftDetectionResult.
+func (in *DriftDetectionResult) DeepCopy() *DriftDetectionResult {
+	if in == nil {
+		return nil
+	}
+	out := new(DriftDetectionResult)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DriftedObject) DeepCopyInto(out *DriftedObject) {
+	*out = *in
+	out.Ref = in.Ref
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DriftedObject.
+func (in *DriftedObject) DeepCopy() *DriftedObject {
+	if in == nil {
+		return nil
+	}
+	out := new(DriftedObject)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GitInfo) DeepCopyInto(out *GitInfo) {
 	*out = *in
@@ -0,0 +1,184 @@
+package validation
+
+import (
+	"fmt"
+	"github.com/google/cel-go/cel"
+	"github.com/kluctl/kluctl/v2/pkg/types"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// HealthChecks holds the compiled health checks configured via 'healthChecks' in deployment projects. Health checks
+// replace the built-in readiness logic of ValidateObject for the matching kinds.
+type HealthChecks struct {
+	checks []*healthCheck
+}
+
+type healthCheck struct {
+	config *types.HealthCheckConfig
+
+	ready       *healthCheckCondition
+	failed      *healthCheckCondition
+	progressing *healthCheckCondition
+}
+
+type healthCheckCondition struct {
+	config *types.HealthCheckCondition
+
+	prg cel.Program
+	jp  *uo.MyJsonPath
+}
+
+var celEnv *cel.Env
+
+func init() {
+	var err error
+	celEnv, err = cel.NewEnv(cel.Variable("object", cel.DynType))
+	if err != nil {
+		panic(err)
+	}
+}
+
+func NewHealthChecks(configs []*types.HealthCheckConfig) (*HealthChecks, error) {
+	ret := &HealthChecks{}
+	for _, c := range configs {
+		hc := &healthCheck{
+			config: c,
+		}
+		var err error
+		hc.ready, err = newHealthCheckCondition(c.Ready)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ready health check for kind %s: %w", c.Kind, err)
+		}
+		hc.failed, err = newHealthCheckCondition(c.Failed)
+		if err != nil {
+			return nil, fmt.Errorf("invalid failed health check for kind %s: %w", c.Kind, err)
+		}
+		hc.progressing, err = newHealthCheckCondition(c.Progressing)
+		if err != nil {
+			return nil, fmt.Errorf("invalid progressing health check for kind %s: %w", c.Kind, err)
+		}
+		ret.checks = append(ret.checks, hc)
+	}
+	return ret, nil
+}
+
+func newHealthCheckCondition(c *types.HealthCheckCondition) (*healthCheckCondition, error) {
+	if c == nil {
+		return nil, nil
+	}
+	ret := &healthCheckCondition{
+		config: c,
+	}
+	if c.Cel != "" {
+		ast, iss := celEnv.Compile(c.Cel)
+		if iss.Err() != nil {
+			return nil, iss.Err()
+		}
+		if ast.OutputType() != cel.BoolType && ast.OutputType() != cel.DynType {
+			return nil, fmt.Errorf("expression '%s' must evaluate to a bool", c.Cel)
+		}
+		prg, err := celEnv.Program(ast)
+		if err != nil {
+			return nil, err
+		}
+		ret.prg = prg
+	} else {
+		jp, err := uo.NewMyJsonPath(c.JsonPath)
+		if err != nil {
+			return nil, err
+		}
+		ret.jp = jp
+	}
+	return ret, nil
+}
+
+func (hcs *HealthChecks) findChecks(gk schema.GroupKind) []*healthCheck {
+	if hcs == nil {
+		return nil
+	}
+	var ret []*healthCheck
+	for _, hc := range hcs.checks {
+		if hc.config.Group != nil && *hc.config.Group != gk.Group {
+			continue
+		}
+		if hc.config.Kind != gk.Kind {
+			continue
+		}
+		ret = append(ret, hc)
+	}
+	return ret
+}
+
+func (c *healthCheckCondition) eval(o *uo.UnstructuredObject) (bool, error) {
+	if c.prg != nil {
+		out, _, err := c.prg.Eval(map[string]any{
+			"object": o.Object,
+		})
+		if err != nil {
+			return false, fmt.Errorf("failed to evaluate '%s': %w", c.config.Cel, err)
+		}
+		b, ok := out.Value().(bool)
+		if !ok {
+			return false, fmt.Errorf("expression '%s' did not evaluate to a bool", c.config.Cel)
+		}
+		return b, nil
+	}
+
+	for _, v := range c.jp.Get(o) {
+		if c.config.Value != nil {
+			if fmt.Sprint(v) == *c.config.Value {
+				return true, nil
+			}
+		} else if v != nil && v != false && v != "" {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (c *healthCheckCondition) getMessage(def string) string {
+	if c.config.Message == "" {
+		return def
+	}
+	return c.config.Message
+}
+
+// validateWithHealthChecks evaluates all matching health checks. Failed conditions are reported as errors while
+// progressing or unfulfilled ready conditions cause the object to be reported as not ready.
+func validateWithHealthChecks(o *uo.UnstructuredObject, checks []*healthCheck, addError func(message string), addNotReady func(message string)) {
+	for _, hc := range checks {
+		if hc.failed != nil {
+			b, err := hc.failed.eval(o)
+			if err != nil {
+				addNotReady(err.Error())
+				continue
+			}
+			if b {
+				addError(hc.failed.getMessage("Failed"))
+				continue
+			}
+		}
+		if hc.progressing != nil {
+			b, err := hc.progressing.eval(o)
+			if err != nil {
+				addNotReady(err.Error())
+				continue
+			}
+			if b {
+				addNotReady(hc.progressing.getMessage("Progressing"))
+				continue
+			}
+		}
+		if hc.ready != nil {
+			b, err := hc.ready.eval(o)
+			if err != nil {
+				addNotReady(err.Error())
+				continue
+			}
+			if !b {
+				addNotReady(hc.ready.getMessage("Not ready"))
+			}
+		}
+	}
+}
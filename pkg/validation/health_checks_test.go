@@ -0,0 +1,110 @@
+package validation
+
+import (
+	"github.com/kluctl/kluctl/v2/pkg/types"
+	"github.com/kluctl/kluctl/v2/pkg/utils"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func buildClusterObject(phase string) *uo.UnstructuredObject {
+	o := uo.FromMap(map[string]interface{}{
+		"apiVersion": "postgresql.cnpg.io/v1",
+		"kind":       "Cluster",
+		"metadata": map[string]any{
+			"name":      "test",
+			"namespace": "ns",
+		},
+	})
+	if phase != "" {
+		_ = o.SetNestedField(phase, "status", "phase")
+	}
+	return o
+}
+
+func TestHealthChecksCel(t *testing.T) {
+	hc, err := NewHealthChecks([]*types.HealthCheckConfig{
+		{
+			Group: utils.StrPtr("postgresql.cnpg.io"),
+			Kind:  "Cluster",
+			Ready: &types.HealthCheckCondition{
+				Cel: `has(object.status) && object.status.phase == "Cluster in healthy state"`,
+			},
+			Failed: &types.HealthCheckCondition{
+				Cel:     `has(object.status) && object.status.phase == "Failed"`,
+				Message: "cluster failed",
+			},
+		},
+	})
+	assert.NoError(t, err)
+
+	r := ValidateObject(nil, buildClusterObject(""), true, false, hc)
+	assert.False(t, r.Ready)
+	assert.Len(t, r.Errors, 1)
+	assert.Equal(t, "Not ready", r.Errors[0].Message)
+
+	r = ValidateObject(nil, buildClusterObject("Setting up primary"), false, false, hc)
+	assert.False(t, r.Ready)
+	assert.Empty(t, r.Errors)
+	assert.Len(t, r.Warnings, 1)
+
+	r = ValidateObject(nil, buildClusterObject("Failed"), false, false, hc)
+	assert.False(t, r.Ready)
+	assert.Len(t, r.Errors, 1)
+	assert.Equal(t, "cluster failed", r.Errors[0].Message)
+
+	r = ValidateObject(nil, buildClusterObject("Cluster in healthy state"), true, false, hc)
+	assert.True(t, r.Ready)
+	assert.Empty(t, r.Errors)
+	assert.Empty(t, r.Warnings)
+}
+
+func TestHealthChecksJsonPath(t *testing.T) {
+	hc, err := NewHealthChecks([]*types.HealthCheckConfig{
+		{
+			Kind: "Cluster",
+			Ready: &types.HealthCheckCondition{
+				JsonPath: "status.phase",
+				Value:    utils.StrPtr("Cluster in healthy state"),
+			},
+			Progressing: &types.HealthCheckCondition{
+				JsonPath: "status.phase",
+				Value:    utils.StrPtr("Setting up primary"),
+				Message:  "setting up",
+			},
+		},
+	})
+	assert.NoError(t, err)
+
+	r := ValidateObject(nil, buildClusterObject("Setting up primary"), true, false, hc)
+	assert.False(t, r.Ready)
+	assert.Len(t, r.Errors, 1)
+	assert.Equal(t, "setting up", r.Errors[0].Message)
+
+	r = ValidateObject(nil, buildClusterObject("Cluster in healthy state"), true, false, hc)
+	assert.True(t, r.Ready)
+	assert.Empty(t, r.Errors)
+}
+
+func TestHealthChecksInvalidCel(t *testing.T) {
+	_, err := NewHealthChecks([]*types.HealthCheckConfig{
+		{
+			Kind: "Cluster",
+			Ready: &types.HealthCheckCondition{
+				Cel: `object.status.phase ==`,
+			},
+		},
+	})
+	assert.Error(t, err)
+
+	_, err = NewHealthChecks([]*types.HealthCheckConfig{
+		{
+			Kind: "Cluster",
+			Ready: &types.HealthCheckCondition{
+				Cel: `"not a bool"`,
+			},
+		},
+	})
+	assert.Error(t, err)
+}
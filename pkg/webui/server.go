@@ -119,6 +119,7 @@ func (s *CommandResultsServer) Run(port int) error {
 	api.POST("/validateNow", s.auth.authHandler, s.validateNow)
 	api.POST("/reconcileNow", s.auth.authHandler, s.reconcileNow)
 	api.POST("/deployNow", s.auth.authHandler, s.deployNow)
+	api.POST("/approveDeployment", s.auth.authHandler, s.approveDeployment)
 
 	// handles authentication via the first message
 	api.Any("/ws", s.ws)
@@ -335,6 +336,11 @@ type kluctlDeploymentParam struct {
 	Namespace string `json:"namespace"`
 }
 
+type approveDeploymentParam struct {
+	kluctlDeploymentParam
+	ResultId string `json:"resultId"`
+}
+
 func (s *CommandResultsServer) doSetAnnotation(c *gin.Context, aname string, avalue string) {
 	var params kluctlDeploymentParam
 	err := c.Bind(&params)
@@ -343,11 +349,15 @@ func (s *CommandResultsServer) doSetAnnotation(c *gin.Context, aname string, ava
 		return
 	}
 
+	s.setAnnotation(c, params, aname, avalue)
+}
+
+func (s *CommandResultsServer) setAnnotation(c *gin.Context, params kluctlDeploymentParam, aname string, avalue string) {
 	user := s.auth.getUser(c)
 
 	ca := s.cam.getForClusterId(params.Cluster)
 	if ca == nil {
-		_ = c.AbortWithError(http.StatusNotFound, err)
+		_ = c.AbortWithError(http.StatusNotFound, fmt.Errorf("cluster %s not found", params.Cluster))
 		return
 	}
 
@@ -389,3 +399,18 @@ func (s *CommandResultsServer) reconcileNow(c *gin.Context) {
 func (s *CommandResultsServer) deployNow(c *gin.Context) {
 	s.doSetAnnotation(c, kluctlv1.KluctlRequestDeployAnnotation, time.Now().Format(time.RFC3339Nano))
 }
+
+func (s *CommandResultsServer) approveDeployment(c *gin.Context) {
+	var params approveDeploymentParam
+	err := c.Bind(&params)
+	if err != nil {
+		_ = c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+	if params.ResultId == "" {
+		_ = c.AbortWithError(http.StatusBadRequest, fmt.Errorf("missing resultId"))
+		return
+	}
+
+	s.setAnnotation(c, params.kluctlDeploymentParam, kluctlv1.KluctlApproveDeployAnnotation, params.ResultId)
+}
@@ -37,6 +37,7 @@ export interface Api {
     validateNow(project: ProjectKey, target: TargetKey): Promise<Response>
     reconcileNow(cluster: string, name: string, namespace: string): Promise<Response>
     deployNow(cluster: string, name: string, namespace: string): Promise<Response>
+    approveDeployment(cluster: string, name: string, namespace: string, resultId: string): Promise<Response>
 }
 
 export async function checkStaticBuild() {
@@ -255,6 +256,15 @@ export class RealApi implements Api {
             "namespace": namespace,
         })
     }
+
+    async approveDeployment(cluster: string, name: string, namespace: string, resultId: string): Promise<Response> {
+        return this.doPost("/api/approveDeployment", {
+            "cluster": cluster,
+            "name": name,
+            "namespace": namespace,
+            "resultId": resultId,
+        })
+    }
 }
 
 export class StaticApi implements Api {
@@ -323,6 +333,10 @@ export class StaticApi implements Api {
     deployNow(cluster: string, name: string, namespace: string): Promise<Response> {
         throw new Error("not implemented")
     }
+
+    approveDeployment(cluster: string, name: string, namespace: string, resultId: string): Promise<Response> {
+        throw new Error("not implemented")
+    }
 }
 
 function buildRefParams(ref: ObjectRef, params: URLSearchParams) {
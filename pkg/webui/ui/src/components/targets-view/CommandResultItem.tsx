@@ -1,4 +1,4 @@
-import React, { useEffect, useMemo, useState } from "react";
+import React, { useContext, useEffect, useMemo, useState } from "react";
 import { CommandResultSummary } from "../../models";
 import * as yaml from "js-yaml";
 import { CodeViewer } from "../CodeViewer";
@@ -9,6 +9,8 @@ import { DeployIcon, DiffIcon, PruneIcon, TreeViewIcon } from "../../icons/Icons
 import { ProjectSummary, TargetSummary } from "../../project-summaries";
 import { calcAgo } from "../../utils/duration";
 import { CardPaper } from "./Card";
+import { ApiContext } from "../App";
+import { TaskAlt } from "@mui/icons-material";
 
 export const CommandResultItemHeader = React.memo((props: { rs: CommandResultSummary }) => {
     const { rs } = props;
@@ -83,6 +85,11 @@ export const CommandResultItem = React.memo((props: {
 }) => {
     const { rs, onSelectCommandResult } = props;
     const navigate = useNavigate()
+    const api = useContext(ApiContext)
+
+    // diffs performed by a KluctlDeployment are only stored when the approval mode is enabled
+    const kd = rs.commandInfo.kluctlDeployment
+    const canApprove = rs.commandInfo.command === "diff" && kd !== undefined
 
     return <CardPaper
         sx={{ padding: '20px 16px 5px 16px' }}
@@ -95,6 +102,21 @@ export const CommandResultItem = React.memo((props: {
                     <CommandResultStatusLine rs={rs} />
                 </Box>
                 <Box display='flex' gap='6px' alignItems='center' height='39px'>
+                    {canApprove && <IconButton
+                        onClick={e => {
+                            e.stopPropagation();
+                            api.approveDeployment(props.ts.target.clusterId, kd!.name, kd!.namespace, rs.id)
+                        }}
+                        sx={{
+                            padding: 0,
+                            width: 26,
+                            height: 26
+                        }}
+                    >
+                        <Tooltip title={"Approve Deployment"}>
+                            <Box display='flex'><TaskAlt /></Box>
+                        </Tooltip>
+                    </IconButton>}
                     <IconButton
                         onClick={e => {
                             e.stopPropagation();
@@ -87,6 +87,54 @@ export class ResultObject {
 	    return a;
 	}
 }
+export class HealthCheckCondition {
+    cel?: string;
+    jsonPath?: string;
+    value?: string;
+    message?: string;
+
+    constructor(source: any = {}) {
+        if ('string' === typeof source) source = JSON.parse(source);
+        this.cel = source["cel"];
+        this.jsonPath = source["jsonPath"];
+        this.value = source["value"];
+        this.message = source["message"];
+    }
+}
+export class HealthCheckConfig {
+    group?: string;
+    kind: string;
+    ready?: HealthCheckCondition;
+    failed?: HealthCheckCondition;
+    progressing?: HealthCheckCondition;
+
+    constructor(source: any = {}) {
+        if ('string' === typeof source) source = JSON.parse(source);
+        this.group = source["group"];
+        this.kind = source["kind"];
+        this.ready = this.convertValues(source["ready"], HealthCheckCondition);
+        this.failed = this.convertValues(source["failed"], HealthCheckCondition);
+        this.progressing = this.convertValues(source["progressing"], HealthCheckCondition);
+    }
+
+	convertValues(a: any, classs: any, asMap: boolean = false): any {
+	    if (!a) {
+	        return a;
+	    }
+	    if (a.slice) {
+	        return (a as any[]).map(elem => this.convertValues(elem, classs));
+	    } else if ("object" === typeof a) {
+	        if (asMap) {
+	            for (const key of Object.keys(a)) {
+	                a[key] = new classs(a[key]);
+	            }
+	            return a;
+	        }
+	        return new classs(a);
+	    }
+	    return a;
+	}
+}
 export class IgnoreForDiffItemConfig {
     fieldPath?: string[];
     fieldPathRegex?: string[];
@@ -167,6 +215,7 @@ export class DeploymentItemConfig {
     git?: GitProject;
     tags?: string[];
     barrier?: boolean;
+    dependsOn?: string[];
     message?: string;
     waitReadiness?: boolean;
     vars?: VarsSource[];
@@ -186,6 +235,7 @@ export class DeploymentItemConfig {
         this.git = this.convertValues(source["git"], GitProject);
         this.tags = source["tags"];
         this.barrier = source["barrier"];
+        this.dependsOn = source["dependsOn"];
         this.message = source["message"];
         this.waitReadiness = source["waitReadiness"];
         this.vars = this.convertValues(source["vars"], VarsSource);
@@ -351,6 +401,7 @@ export class DeploymentProjectConfig {
     overrideNamespace?: string;
     tags?: string[];
     ignoreForDiff?: IgnoreForDiffItemConfig[];
+    healthChecks?: HealthCheckConfig[];
 
     constructor(source: any = {}) {
         if ('string' === typeof source) source = JSON.parse(source);
@@ -363,6 +414,7 @@ export class DeploymentProjectConfig {
         this.overrideNamespace = source["overrideNamespace"];
         this.tags = source["tags"];
         this.ignoreForDiff = this.convertValues(source["ignoreForDiff"], IgnoreForDiffItemConfig);
+        this.healthChecks = this.convertValues(source["healthChecks"], HealthCheckConfig);
     }
 
 	convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -434,6 +486,7 @@ export class CommandInfo {
     replaceOnError?: boolean;
     forceReplaceOnError?: boolean;
     abortOnError?: boolean;
+    rollbackOnError?: boolean;
     includeTags?: string[];
     excludeTags?: string[];
     includeDeploymentDirs?: string[];
@@ -457,6 +510,7 @@ export class CommandInfo {
         this.replaceOnError = source["replaceOnError"];
         this.forceReplaceOnError = source["forceReplaceOnError"];
         this.abortOnError = source["abortOnError"];
+        this.rollbackOnError = source["rollbackOnError"];
         this.includeTags = source["includeTags"];
         this.excludeTags = source["excludeTags"];
         this.includeDeploymentDirs = source["includeDeploymentDirs"];
@@ -622,6 +676,7 @@ export class CommandResult {
     errors?: DeploymentError[];
     warnings?: DeploymentError[];
     seenImages?: FixedImage[];
+    rolledBackTo?: string;
 
     constructor(source: any = {}) {
         if ('string' === typeof source) source = JSON.parse(source);
@@ -637,6 +692,7 @@ export class CommandResult {
         this.errors = this.convertValues(source["errors"], DeploymentError);
         this.warnings = this.convertValues(source["warnings"], DeploymentError);
         this.seenImages = this.convertValues(source["seenImages"], FixedImage);
+        this.rolledBackTo = source["rolledBackTo"];
     }
 
 	convertValues(a: any, classs: any, asMap: boolean = false): any {